@@ -3,15 +3,83 @@ CREATE SCHEMA weather;
 CREATE TABLE weather.Users (
   id VARCHAR(255) PRIMARY KEY,
   name VARCHAR(255),
-  notification_config JSONB
+  notification_config JSONB,
+  locale VARCHAR(16) NOT NULL DEFAULT 'pt-BR',
+  forecast_style VARCHAR(16) NOT NULL DEFAULT 'simple'
 );
 
+CREATE INDEX users_telegram_chat_idx ON weather.Users ((notification_config->'Telegram'->>'ChatID'));
+CREATE INDEX users_telegram_linking_code_idx ON weather.Users ((notification_config->'Telegram'->>'LinkingCode'));
+
 CREATE TABLE weather.Schedules (
   id VARCHAR(255) PRIMARY KEY,
   user_id VARCHAR(255),
   city_name VARCHAR(255),
   status VARCHAR(255),
-  time TIMESTAMP WITH TIME ZONE
+  time TIMESTAMP WITH TIME ZONE,
+  recurrence VARCHAR(255) NOT NULL DEFAULT '',
+  timezone VARCHAR(255) NOT NULL DEFAULT '',
+  days INTEGER NOT NULL DEFAULT 0,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  claimed_at TIMESTAMP WITH TIME ZONE,
+  latitude DOUBLE PRECISION,
+  longitude DOUBLE PRECISION,
+  city_id VARCHAR(255) NOT NULL DEFAULT '',
+  state VARCHAR(255) NOT NULL DEFAULT '',
+  alerts_only BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX schedules_status_time_idx ON weather.Schedules (status, time);
+
+CREATE TABLE weather.AlertRules (
+  id VARCHAR(255) PRIMARY KEY,
+  user_id VARCHAR(255) NOT NULL,
+  city_id VARCHAR(255) NOT NULL,
+  city_name VARCHAR(255) NOT NULL DEFAULT '',
+  state VARCHAR(255) NOT NULL DEFAULT '',
+  conditions TEXT NOT NULL DEFAULT '',
+  min_temperature INTEGER,
+  max_temperature INTEGER,
+  iuv DOUBLE PRECISION,
+  wave_height DOUBLE PRECISION,
+  wind DOUBLE PRECISION,
+  days INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX alert_rules_user_city_idx ON weather.AlertRules (user_id, city_id);
+
+CREATE TABLE weather.AlertsSent (
+  rule_id VARCHAR(255) REFERENCES weather.AlertRules (id) ON DELETE CASCADE,
+  forecast_date VARCHAR(10),
+  sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY (rule_id, forecast_date)
+);
+
+CREATE TABLE weather.RevisionSubscriptions (
+  id VARCHAR(255) PRIMARY KEY,
+  user_id VARCHAR(255) NOT NULL,
+  city_id VARCHAR(255) NOT NULL,
+  city_name VARCHAR(255) NOT NULL DEFAULT '',
+  state VARCHAR(255) NOT NULL DEFAULT '',
+  threshold INTEGER NOT NULL,
+  days INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX revision_subscriptions_user_idx ON weather.RevisionSubscriptions (user_id);
+CREATE INDEX revision_subscriptions_city_idx ON weather.RevisionSubscriptions (city_id);
+
+CREATE TABLE weather.RevisionSnapshots (
+  subscription_id VARCHAR(255) REFERENCES weather.RevisionSubscriptions (id) ON DELETE CASCADE,
+  forecast_date VARCHAR(10),
+  updated_at VARCHAR(255) NOT NULL DEFAULT '',
+  code VARCHAR(255) NOT NULL DEFAULT '',
+  weather VARCHAR(255) NOT NULL DEFAULT '',
+  min_temperature INTEGER NOT NULL,
+  max_temperature INTEGER NOT NULL,
+  PRIMARY KEY (subscription_id, forecast_date)
 );
 
 INSERT INTO weather.Users(id, name, notification_config)
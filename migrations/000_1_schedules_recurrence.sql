@@ -0,0 +1,4 @@
+-- Recurring schedules keep their cron expression and the timezone it is
+-- evaluated in. One-off schedules have neither.
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS recurrence VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS timezone VARCHAR(255) NOT NULL DEFAULT '';
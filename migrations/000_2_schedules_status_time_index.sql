@@ -0,0 +1,2 @@
+-- Due schedules are claimed by status and time.
+CREATE INDEX IF NOT EXISTS schedules_status_time_idx ON weather.Schedules (status, time);
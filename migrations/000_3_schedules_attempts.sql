@@ -0,0 +1,5 @@
+-- Failed attempts of each schedule, the last error, and when it was claimed,
+-- so schedules stuck in processing can be reaped.
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,2 @@
+-- Days of forecast each schedule sends, 0 for the default.
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS days INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Schedules made from coordinates keep them. Schedules made from a city name
+-- have neither.
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
@@ -0,0 +1,4 @@
+-- Schedules keep the city found when they were created, instead of searching
+-- for it by name on every run. Rows without a city_id are still found by name.
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS city_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS state VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,28 @@
+-- Alert rules, the forecast dates each one already alerted about, and
+-- schedules that only send alerts.
+ALTER TABLE weather.Schedules ADD COLUMN IF NOT EXISTS alerts_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS weather.AlertRules (
+  id VARCHAR(255) PRIMARY KEY,
+  user_id VARCHAR(255) NOT NULL,
+  city_id VARCHAR(255) NOT NULL,
+  city_name VARCHAR(255) NOT NULL DEFAULT '',
+  state VARCHAR(255) NOT NULL DEFAULT '',
+  conditions TEXT NOT NULL DEFAULT '',
+  min_temperature INTEGER,
+  max_temperature INTEGER,
+  iuv DOUBLE PRECISION,
+  wave_height DOUBLE PRECISION,
+  wind DOUBLE PRECISION,
+  days INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS alert_rules_user_city_idx ON weather.AlertRules (user_id, city_id);
+
+CREATE TABLE IF NOT EXISTS weather.AlertsSent (
+  rule_id VARCHAR(255) REFERENCES weather.AlertRules (id) ON DELETE CASCADE,
+  forecast_date VARCHAR(10),
+  sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  PRIMARY KEY (rule_id, forecast_date)
+);
@@ -0,0 +1,25 @@
+-- Subscriptions to forecast revisions and the last forecast fetched for
+-- each subscribed city and date.
+CREATE TABLE IF NOT EXISTS weather.RevisionSubscriptions (
+  id VARCHAR(255) PRIMARY KEY,
+  user_id VARCHAR(255) NOT NULL,
+  city_id VARCHAR(255) NOT NULL,
+  city_name VARCHAR(255) NOT NULL DEFAULT '',
+  state VARCHAR(255) NOT NULL DEFAULT '',
+  threshold INTEGER NOT NULL,
+  days INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS revision_subscriptions_user_idx ON weather.RevisionSubscriptions (user_id);
+CREATE INDEX IF NOT EXISTS revision_subscriptions_city_idx ON weather.RevisionSubscriptions (city_id);
+
+CREATE TABLE IF NOT EXISTS weather.ForecastSnapshots (
+  city_id VARCHAR(255),
+  forecast_date VARCHAR(10),
+  updated_at VARCHAR(255) NOT NULL DEFAULT '',
+  weather VARCHAR(255) NOT NULL DEFAULT '',
+  min_temperature INTEGER NOT NULL,
+  max_temperature INTEGER NOT NULL,
+  PRIMARY KEY (city_id, forecast_date)
+);
@@ -0,0 +1,2 @@
+-- Language each user gets notifications in.
+ALTER TABLE weather.Users ADD COLUMN IF NOT EXISTS locale VARCHAR(16) NOT NULL DEFAULT 'pt-BR';
@@ -0,0 +1,2 @@
+-- How much of the forecast each user gets in notifications.
+ALTER TABLE weather.Users ADD COLUMN IF NOT EXISTS forecast_style VARCHAR(16) NOT NULL DEFAULT 'simple';
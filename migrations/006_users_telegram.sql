@@ -0,0 +1,4 @@
+-- Users are found by the chat linked to them, and by the code sent to the
+-- bot to link one, both kept in the notification config.
+CREATE INDEX IF NOT EXISTS users_telegram_chat_idx ON weather.Users ((notification_config->'Telegram'->>'ChatID'));
+CREATE INDEX IF NOT EXISTS users_telegram_linking_code_idx ON weather.Users ((notification_config->'Telegram'->>'LinkingCode'));
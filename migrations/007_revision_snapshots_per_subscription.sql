@@ -0,0 +1,15 @@
+-- Forecast snapshots are kept per revision subscription, as the forecast its
+-- subscriber was last told about, instead of per city. The city snapshots
+-- are dropped, and each subscription stores its own on the next check.
+DROP TABLE IF EXISTS weather.ForecastSnapshots;
+
+CREATE TABLE IF NOT EXISTS weather.RevisionSnapshots (
+  subscription_id VARCHAR(255) REFERENCES weather.RevisionSubscriptions (id) ON DELETE CASCADE,
+  forecast_date VARCHAR(10),
+  updated_at VARCHAR(255) NOT NULL DEFAULT '',
+  code VARCHAR(255) NOT NULL DEFAULT '',
+  weather VARCHAR(255) NOT NULL DEFAULT '',
+  min_temperature INTEGER NOT NULL,
+  max_temperature INTEGER NOT NULL,
+  PRIMARY KEY (subscription_id, forecast_date)
+);
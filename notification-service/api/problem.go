@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 error response, in the format used by the other
+// services. Notifications arrive from the queue, so the errors answered here
+// are for webhooks and routes that do not exist.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func writeProblem(w http.ResponseWriter, problem Problem) {
+	body, _ := json.Marshal(problem)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	w.Write(body)
+}
+
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusNotFound),
+		Status: http.StatusNotFound,
+		Detail: "no route for " + r.URL.Path,
+	})
+}
+
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusMethodNotAllowed),
+		Status: http.StatusMethodNotAllowed,
+		Detail: r.Method + " is not allowed on " + r.URL.Path,
+	})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type PublicKeyTO struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// PushKeyHandler gives pages the key browsers subscribe to pushes with, as
+// the applicationServerKey of PushManager.subscribe
+type PushKeyHandler struct {
+	PublicKey string
+}
+
+func (h *PushKeyHandler) Key(w http.ResponseWriter, r *http.Request) {
+	body, _ := json.Marshal(PublicKeyTO{PublicKey: h.PublicKey})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/fgouvea/weather/notification-service/telegram"
+	"go.uber.org/zap"
+)
+
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type UpdateHandler interface {
+	HandleUpdate(update telegram.Update) error
+}
+
+// TelegramHandler receives the updates of the bot, registered as its webhook
+// with the same secret token. Updates are acknowledged even when handling
+// them fails, since the Bot API would otherwise send them again. Without a
+// secret every update is refused, as anyone could send them.
+type TelegramHandler struct {
+	Bot    UpdateHandler
+	Secret string
+	Logger *zap.Logger
+}
+
+func (h *TelegramHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if h.Secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(telegramSecretHeader)), []byte(h.Secret)) != 1 {
+		h.Logger.Info("telegram update with an invalid secret token")
+		writeProblem(w, Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusUnauthorized),
+			Status: http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var update telegram.Update
+	err := json.NewDecoder(r.Body).Decode(&update)
+
+	if err != nil {
+		h.Logger.Info("error reading telegram update", zap.Error(err))
+		writeProblem(w, Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusBadRequest),
+			Status: http.StatusBadRequest,
+			Detail: err.Error(),
+		})
+		return
+	}
+
+	err = h.Bot.HandleUpdate(update)
+
+	if err != nil {
+		h.Logger.Error("error handling telegram update", zap.Int64("updateID", update.UpdateID), zap.Error(err))
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,230 @@
+package email
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+)
+
+var (
+	ErrFailedToSend        = errors.New("failed to send notification by email")
+	ErrStartTLSUnsupported = errors.New("smtp server does not support STARTTLS")
+)
+
+// Config is the SMTP server and the sender of the emails. Without StartTLS
+// the connection is not encrypted, and the server only accepts the
+// credentials when it is on localhost.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string `json:"-"`
+	From     string
+	ReplyTo  string
+	Subject  string
+	StartTLS bool
+	Timeout  time.Duration
+}
+
+// Client sends notifications as multipart emails, with the plain text and,
+// when the notification has it, the HTML as alternatives.
+type Client struct {
+	Config    Config
+	TLSConfig *tls.Config
+
+	address string
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		Config:    config,
+		TLSConfig: &tls.Config{ServerName: config.Host},
+
+		address: net.JoinHostPort(config.Host, config.Port),
+	}
+}
+
+func (c *Client) Send(recipient user.User, userNotification notification.Notification) error {
+	config := recipient.NotificationConfig.Email
+
+	if !config.Enabled || config.Address == "" {
+		return notification.ErrUserOptOut
+	}
+
+	from, err := mail.ParseAddress(c.Config.From)
+
+	if err != nil {
+		return fmt.Errorf("%w: invalid sender address: %w", ErrFailedToSend, err)
+	}
+
+	to := mail.Address{Name: recipient.Name, Address: config.Address}
+
+	message, err := c.buildMessage(from, &to, userNotification)
+
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	err = c.deliver(from.Address, to.Address, message)
+
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrFailedToSend, err)
+
+		if isPermanent(err) {
+			return fmt.Errorf("%w: %w", notification.ErrUndeliverable, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// isPermanent tells the replies that sending again would not change, which
+// SMTP marks with 5xx codes, such as a recipient that does not exist
+func isPermanent(err error) bool {
+	var reply *textproto.Error
+
+	return errors.As(err, &reply) && reply.Code >= 500
+}
+
+func (c *Client) deliver(from, to string, message []byte) error {
+	conn, err := net.DialTimeout("tcp", c.address, c.Config.Timeout)
+
+	if err != nil {
+		return err
+	}
+
+	if c.Config.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.Config.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, c.Config.Host)
+
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	defer client.Close()
+
+	if c.Config.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return ErrStartTLSUnsupported
+		}
+
+		if err := client.StartTLS(c.TLSConfig); err != nil {
+			return err
+		}
+	}
+
+	if c.Config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.Config.Username, c.Config.Password, c.Config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	data, err := client.Data()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := data.Write(message); err != nil {
+		return err
+	}
+
+	if err := data.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (c *Client) buildMessage(from, to *mail.Address, userNotification notification.Notification) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	err := writePart(parts, "text/plain", userNotification.Body(notification.FormatText))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userNotification.HTML != "" {
+		err = writePart(parts, "text/html", userNotification.HTML)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = parts.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+
+	writeHeader(&message, "From", from.String())
+	writeHeader(&message, "To", to.String())
+
+	if c.Config.ReplyTo != "" {
+		writeHeader(&message, "Reply-To", c.Config.ReplyTo)
+	}
+
+	writeHeader(&message, "Subject", mime.QEncoding.Encode("utf-8", c.Config.Subject))
+	writeHeader(&message, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&message, "MIME-Version", "1.0")
+	writeHeader(&message, "Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()}))
+
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func writeHeader(message *bytes.Buffer, name, value string) {
+	message.WriteString(name + ": " + value + "\r\n")
+}
+
+func writePart(parts *multipart.Writer, contentType, content string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := parts.CreatePart(header)
+
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
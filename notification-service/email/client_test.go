@@ -0,0 +1,248 @@
+package email
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpServer is a stand-in for an SMTP server, accepting one message per
+// connection and keeping what it was sent. A rcptReply rejects the
+// recipient with it.
+type smtpServer struct {
+	listener  net.Listener
+	rcptReply string
+
+	auth string
+	from string
+	to   string
+	data string
+}
+
+func newSMTPServer(t *testing.T, rcptReply string) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("failed to start smtp server: %s", err.Error())
+	}
+
+	server := &smtpServer{listener: listener, rcptReply: rcptReply}
+
+	go server.serve()
+
+	return server
+}
+
+func (s *smtpServer) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *smtpServer) close() {
+	s.listener.Close()
+}
+
+func (s *smtpServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		s.handle(textproto.NewConn(conn))
+	}
+}
+
+func (s *smtpServer) handle(conn *textproto.Conn) {
+	defer conn.Close()
+
+	conn.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := conn.ReadLine()
+
+		if err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			conn.PrintfLine("250-localhost")
+			conn.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = argument
+			conn.PrintfLine("235 2.7.0 authenticated")
+		case "MAIL":
+			s.from = argument
+			conn.PrintfLine("250 2.1.0 ok")
+		case "RCPT":
+			if s.rcptReply != "" {
+				conn.PrintfLine("%s", s.rcptReply)
+				continue
+			}
+
+			s.to = argument
+			conn.PrintfLine("250 2.1.5 ok")
+		case "DATA":
+			conn.PrintfLine("354 go ahead")
+			data, _ := conn.ReadDotBytes()
+			s.data = string(data)
+			conn.PrintfLine("250 2.0.0 queued")
+		case "QUIT":
+			conn.PrintfLine("221 2.0.0 bye")
+			return
+		default:
+			conn.PrintfLine("502 5.5.1 unknown command")
+		}
+	}
+}
+
+func TestClient_Send(t *testing.T) {
+	tests := []struct {
+		name          string
+		enabled       bool
+		html          string
+		startTLS      bool
+		rcptReply     string
+		expectedParts map[string]string
+		expectedError error
+		undeliverable bool
+	}{
+		{
+			name:    "success",
+			enabled: true,
+			html:    "<p>Manhã de sol</p>",
+			expectedParts: map[string]string{
+				"text/plain": "Manhã de sol",
+				"text/html":  "<p>Manhã de sol</p>",
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "without html",
+			enabled: true,
+			expectedParts: map[string]string{
+				"text/plain": "Manhã de sol",
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "email notification disabled",
+			enabled:       false,
+			expectedError: notification.ErrUserOptOut,
+		},
+		{
+			name:          "recipient rejected",
+			enabled:       true,
+			rcptReply:     "550 5.1.1 no such user",
+			expectedError: ErrFailedToSend,
+			undeliverable: true,
+		},
+		{
+			name:          "recipient deferred",
+			enabled:       true,
+			rcptReply:     "450 4.2.1 mailbox busy",
+			expectedError: ErrFailedToSend,
+			undeliverable: false,
+		},
+		{
+			name:          "starttls not supported",
+			enabled:       true,
+			startTLS:      true,
+			expectedError: ErrStartTLSUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSMTPServer(t, tt.rcptReply)
+			defer server.close()
+
+			client := NewClient(Config{
+				Host:     "127.0.0.1",
+				Port:     server.port(),
+				Username: "weather",
+				Password: "secret",
+				From:     "Previsão do Tempo <previsao@example.com>",
+				ReplyTo:  "contato@example.com",
+				Subject:  "Previsão do tempo",
+				StartTLS: tt.startTLS,
+			})
+
+			recipient := user.User{
+				Name: "Fulano",
+				NotificationConfig: user.NotificationConfig{
+					Enabled: true,
+					Email: user.EmailNotificationConfig{
+						Enabled: tt.enabled,
+						Address: "fulano@example.com",
+					},
+				},
+			}
+
+			err := client.Send(recipient, notification.Notification{
+				Content: "Manhã de sol",
+				HTML:    tt.html,
+			})
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.undeliverable, errors.Is(err, notification.ErrUndeliverable))
+
+			if tt.expectedError != nil {
+				assert.Empty(t, server.data)
+				return
+			}
+
+			credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(server.auth, "PLAIN "))
+			assert.Equal(t, "\x00weather\x00secret", string(credentials))
+			assert.Equal(t, "FROM:<previsao@example.com>", server.from)
+			assert.Equal(t, "TO:<fulano@example.com>", server.to)
+
+			message, err := mail.ReadMessage(strings.NewReader(server.data))
+			assert.Nil(t, err)
+
+			subject, _ := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+			assert.Equal(t, "Previsão do tempo", subject)
+			assert.Equal(t, `"Fulano" <fulano@example.com>`, message.Header.Get("To"))
+			assert.Equal(t, "contato@example.com", message.Header.Get("Reply-To"))
+
+			mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+			assert.Nil(t, err)
+			assert.Equal(t, "multipart/alternative", mediaType)
+
+			parts := map[string]string{}
+			reader := multipart.NewReader(message.Body, params["boundary"])
+
+			for {
+				part, err := reader.NextPart()
+
+				if err == io.EOF {
+					break
+				}
+
+				assert.Nil(t, err)
+
+				contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+				content, _ := io.ReadAll(part)
+				parts[contentType] = strings.ReplaceAll(string(content), "\r\n", "\n")
+			}
+
+			assert.Equal(t, tt.expectedParts, parts)
+		})
+	}
+}
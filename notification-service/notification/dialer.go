@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var ErrPrivateAddress = errors.New("address is not public")
+
+// sharedAddressSpace is the range carriers use for NAT (RFC 6598), which is
+// not routable on the internet either
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicDialer connects only to public addresses. It is meant for the URLs
+// users register, such as webhooks and push endpoints, so they cannot point
+// the service at itself or at the internal network. The address is checked
+// after the name is resolved, so names resolving to private addresses are
+// refused as well.
+func PublicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: denyPrivate,
+	}
+}
+
+// denyPrivate refuses loopback, private, link-local, multicast and
+// unspecified addresses. Sending again would be refused the same way, so
+// the error is undeliverable.
+func denyPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrUndeliverable, ErrPrivateAddress, address)
+	}
+
+	ip, err := netip.ParseAddr(host)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrUndeliverable, ErrPrivateAddress, address)
+	}
+
+	ip = ip.Unmap()
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %w: %s", ErrUndeliverable, ErrPrivateAddress, ip)
+	}
+
+	return nil
+}
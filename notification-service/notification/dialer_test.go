@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDenyPrivate(t *testing.T) {
+	tests := []struct {
+		name          string
+		address       string
+		expectedError error
+	}{
+		{name: "public ipv4", address: "142.250.79.46:443", expectedError: nil},
+		{name: "public ipv6", address: "[2800:3f0:4001:82a::200e]:443", expectedError: nil},
+		{name: "loopback", address: "127.0.0.1:8090", expectedError: ErrPrivateAddress},
+		{name: "loopback ipv6", address: "[::1]:8090", expectedError: ErrPrivateAddress},
+		{name: "private", address: "10.0.0.5:80", expectedError: ErrPrivateAddress},
+		{name: "private ipv6", address: "[fd00::1]:80", expectedError: ErrPrivateAddress},
+		{name: "link-local", address: "169.254.169.254:80", expectedError: ErrPrivateAddress},
+		{name: "ipv4 mapped", address: "[::ffff:192.168.0.1]:80", expectedError: ErrPrivateAddress},
+		{name: "shared address space", address: "100.64.0.1:80", expectedError: ErrPrivateAddress},
+		{name: "unspecified", address: "0.0.0.0:80", expectedError: ErrPrivateAddress},
+		{name: "not an address", address: "localhost:80", expectedError: ErrPrivateAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := denyPrivate("tcp", tt.address, nil)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedError != nil, errors.Is(err, ErrUndeliverable))
+		})
+	}
+}
+
+func TestPublicDialer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{DialContext: PublicDialer(time.Second).DialContext}}
+
+	_, err := client.Get(server.URL)
+
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+	assert.ErrorIs(t, err, ErrUndeliverable)
+}
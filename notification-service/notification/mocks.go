@@ -19,8 +19,8 @@ type senderMock struct {
 	sendError          error
 }
 
-func (m *senderMock) Send(recipient user.User, content string) error {
+func (m *senderMock) Send(recipient user.User, notification Notification) error {
 	m.sendCallsRecipient = append(m.sendCallsRecipient, recipient)
-	m.sendCallsContent = append(m.sendCallsContent, content)
+	m.sendCallsContent = append(m.sendCallsContent, notification.Content)
 	return m.sendError
 }
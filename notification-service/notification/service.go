@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -12,12 +13,62 @@ var (
 	ErrFailedToProcess = errors.New("failed to process notification")
 	ErrUserOptOut      = errors.New("user opted out of receiving notifications")
 	ErrUnknownChannel  = errors.New("unknown channel")
+	// ErrUndeliverable is wrapped by senders when sending again would fail
+	// the same way, so the notification is dropped instead of retried
+	ErrUndeliverable = errors.New("notification cannot be delivered")
 )
 
+// Channels a notification can be sent through, named after their senders
+const (
+	ChannelWeb      = "web"
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelTelegram = "telegram"
+	ChannelWebhook  = "webhook"
+	ChannelPush     = "push"
+)
+
+// Notification is published by weather-service. ID is unique to each
+// notification, and kept when it is requeued. Content is the plain text,
+// while Markdown and HTML are the same message for channels that can format
+// it. Payload is the data the message was written from, as JSON, for
+// channels that present it on their own. Notifications without a Channel go
+// to every channel enabled by the user.
 type Notification struct {
-	UserID  string
-	Content string
-	Channel string
+	ID       string
+	UserID   string
+	Content  string
+	Markdown string
+	HTML     string
+	Payload  json.RawMessage
+	Channel  string
+}
+
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// Body is the message in the given format, or its plain text when it was
+// published without one
+func (n Notification) Body(format Format) string {
+	var body string
+
+	switch format {
+	case FormatMarkdown:
+		body = n.Markdown
+	case FormatHTML:
+		body = n.HTML
+	}
+
+	if body == "" {
+		return n.Content
+	}
+
+	return body
 }
 
 type UserFinder interface {
@@ -25,7 +76,7 @@ type UserFinder interface {
 }
 
 type Sender interface {
-	Send(recipient user.User, content string) error
+	Send(recipient user.User, notification Notification) error
 }
 
 type Service struct {
@@ -54,25 +105,99 @@ func (s *Service) Process(notification Notification) error {
 		return nil
 	}
 
-	sender, exists := s.Senders[notification.Channel]
+	if notification.Channel != "" {
+		return s.send(recipient, notification.Channel, notification)
+	}
+
+	channels := enabledChannels(recipient.NotificationConfig)
+
+	if len(channels) == 0 {
+		s.Logger.Info("notification skipped, no channel enabled", zap.String("userID", recipient.ID))
+		return nil
+	}
+
+	var errs []error
+	var failed []string
+
+	for _, channel := range channels {
+		err = s.send(recipient, channel, notification)
+
+		if err != nil {
+			errs = append(errs, err)
+			failed = append(failed, channel)
+		}
+	}
+
+	// Retrying sends again through every channel, so it is only worth it when
+	// none of them got the notification
+	if len(errs) < len(channels) {
+		if len(failed) > 0 {
+			s.Logger.Warn("notification not retried through failed channels", zap.String("userID", recipient.ID), zap.Strings("channels", failed), zap.Error(errors.Join(errs...)))
+		}
+
+		return nil
+	}
+
+	var retryable []error
+
+	for _, err := range errs {
+		if !errors.Is(err, ErrUndeliverable) {
+			retryable = append(retryable, err)
+		}
+	}
+
+	if len(retryable) > 0 {
+		return errors.Join(retryable...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Service) send(recipient user.User, channel string, notification Notification) error {
+	sender, exists := s.Senders[channel]
 
 	if !exists {
-		return fmt.Errorf("%w: %s", ErrUnknownChannel, notification.Channel)
+		return fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
 	}
 
-	err = sender.Send(recipient, notification.Content)
+	err := sender.Send(recipient, notification)
 
 	if errors.Is(ErrUserOptOut, err) {
-		s.Logger.Info("notification skipped", zap.String("sender", notification.Channel), zap.String("userID", recipient.ID))
+		s.Logger.Info("notification skipped", zap.String("sender", channel), zap.String("userID", recipient.ID))
 		return nil
 	}
 
 	if err != nil {
-		s.Logger.Error("failed to send", zap.String("sender", notification.Channel), zap.String("userID", recipient.ID), zap.Error(err))
+		s.Logger.Error("failed to send", zap.String("sender", channel), zap.String("userID", recipient.ID), zap.Error(err))
 		return fmt.Errorf("%w: %w", ErrFailedToProcess, err)
 	}
 
-	s.Logger.Info("notification sent", zap.String("sender", notification.Channel), zap.String("userID", recipient.ID))
+	s.Logger.Info("notification sent", zap.String("sender", channel), zap.String("userID", recipient.ID))
 
 	return nil
 }
+
+// enabledChannels lists the channels turned on by the user, in a fixed order
+func enabledChannels(config user.NotificationConfig) []string {
+	enabled := []struct {
+		channel string
+		enabled bool
+	}{
+		{ChannelWeb, config.Web.Enabled},
+		{ChannelEmail, config.Email.Enabled},
+		{ChannelSMS, config.SMS.Enabled},
+		{ChannelTelegram, config.Telegram.Enabled},
+		{ChannelWebhook, config.Webhook.Enabled},
+		{ChannelPush, config.Push.Enabled},
+	}
+
+	var channels []string
+
+	for _, e := range enabled {
+		if e.enabled {
+			channels = append(channels, e.channel)
+		}
+	}
+
+	return channels
+}
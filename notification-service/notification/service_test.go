@@ -8,6 +8,7 @@ import (
 	"github.com/fgouvea/weather/notification-service/user"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestService_Process(t *testing.T) {
@@ -109,3 +110,131 @@ func TestService_Process(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Process_EnabledChannels(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             user.NotificationConfig
+		webError           error
+		emailError         error
+		expectedError      error
+		undeliverable      bool
+		expectedWeb        int
+		expectedEmail      int
+		expectedNotRetried []string
+	}{
+		{
+			name:          "every enabled channel",
+			config:        user.NotificationConfig{Enabled: true, Web: user.WebNotificationConfig{Enabled: true}, Email: user.EmailNotificationConfig{Enabled: true}},
+			expectedWeb:   1,
+			expectedEmail: 1,
+		},
+		{
+			name:          "only enabled channels",
+			config:        user.NotificationConfig{Enabled: true, Email: user.EmailNotificationConfig{Enabled: true}},
+			expectedWeb:   0,
+			expectedEmail: 1,
+		},
+		{
+			name:          "no channel enabled",
+			config:        user.NotificationConfig{Enabled: true},
+			expectedWeb:   0,
+			expectedEmail: 0,
+		},
+		{
+			name:               "some channel failed",
+			config:             user.NotificationConfig{Enabled: true, Web: user.WebNotificationConfig{Enabled: true}, Email: user.EmailNotificationConfig{Enabled: true}},
+			emailError:         fmt.Errorf("runtime error"),
+			expectedWeb:        1,
+			expectedEmail:      1,
+			expectedNotRetried: []string{ChannelEmail},
+		},
+		{
+			name:          "every channel failed",
+			config:        user.NotificationConfig{Enabled: true, Web: user.WebNotificationConfig{Enabled: true}, Email: user.EmailNotificationConfig{Enabled: true}},
+			webError:      fmt.Errorf("runtime error"),
+			emailError:    fmt.Errorf("runtime error"),
+			expectedError: ErrFailedToProcess,
+			expectedWeb:   1,
+			expectedEmail: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userFinderMock := &userFinderMock{
+				findUserResult: user.User{ID: "USER-123", NotificationConfig: tt.config},
+			}
+
+			web := &senderMock{sendError: tt.webError}
+			email := &senderMock{sendError: tt.emailError}
+			sms := &senderMock{}
+
+			core, logs := observer.New(zap.WarnLevel)
+			logger := zap.New(core)
+
+			service := NewService(userFinderMock, map[string]Sender{ChannelWeb: web, ChannelEmail: email, ChannelSMS: sms}, logger)
+
+			err := service.Process(Notification{
+				UserID:  "USER-123",
+				Content: "test notification content",
+			})
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.undeliverable, errors.Is(err, ErrUndeliverable))
+			assert.Equal(t, tt.expectedWeb, len(web.sendCallsContent))
+			assert.Equal(t, tt.expectedEmail, len(email.sendCallsContent))
+			assert.Equal(t, 0, len(sms.sendCallsContent))
+
+			notRetried := logs.FilterMessage("notification not retried through failed channels").All()
+
+			if tt.expectedNotRetried == nil {
+				assert.Empty(t, notRetried)
+				return
+			}
+
+			assert.Equal(t, 1, len(notRetried))
+			assert.ElementsMatch(t, tt.expectedNotRetried, notRetried[0].ContextMap()["channels"])
+		})
+	}
+}
+
+func TestNotification_Body(t *testing.T) {
+	tests := []struct {
+		name           string
+		notification   Notification
+		format         Format
+		expectedResult string
+	}{
+		{
+			name:           "text",
+			notification:   Notification{Content: "text", Markdown: "*markdown*", HTML: "<b>html</b>"},
+			format:         FormatText,
+			expectedResult: "text",
+		},
+		{
+			name:           "markdown",
+			notification:   Notification{Content: "text", Markdown: "*markdown*", HTML: "<b>html</b>"},
+			format:         FormatMarkdown,
+			expectedResult: "*markdown*",
+		},
+		{
+			name:           "html",
+			notification:   Notification{Content: "text", Markdown: "*markdown*", HTML: "<b>html</b>"},
+			format:         FormatHTML,
+			expectedResult: "<b>html</b>",
+		},
+		{
+			name:           "published without the format",
+			notification:   Notification{Content: "text"},
+			format:         FormatHTML,
+			expectedResult: "text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, tt.notification.Body(tt.format))
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+	"go.uber.org/zap"
+)
+
+// vapidExpiration is how long the tokens sent to push services are valid for
+const vapidExpiration = 12 * time.Hour
+
+var (
+	ErrFailedToSend       = errors.New("failed to push notification")
+	ErrSubscriptionGone   = errors.New("push subscription expired")
+	ErrMessageUnencodable = errors.New("failed to encode push message")
+)
+
+// SubscriptionStore reads the subscriptions with their keys, which the user
+// leaves out, and removes the ones push services say are gone, as long as the
+// device has not subscribed again since
+type SubscriptionStore interface {
+	FindPushSubscriptions(userID string) ([]user.PushSubscription, error)
+	RemovePushSubscription(userID, device, endpoint string) error
+}
+
+// Message is the payload of a push, which the service worker of the page
+// shows as a notification with the title and body
+type Message struct {
+	Title   string          `json:"title"`
+	Body    string          `json:"body"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Client pushes notifications to every browser the user subscribed, with the
+// payload encrypted for each of them. Subscriptions the push service answers
+// with 404 or 410 are removed from user-service, and invalid keys or other
+// client errors, but timeouts and rate limits, are not retried.
+type Client struct {
+	Client        *http.Client
+	VAPID         *VAPID
+	Subscriptions SubscriptionStore
+	Title         string
+	TTL           time.Duration
+	Logger        *zap.Logger
+	Now           func() time.Time
+}
+
+func NewClient(httpClient *http.Client, vapid *VAPID, subscriptions SubscriptionStore, title string, ttl time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		Client:        httpClient,
+		VAPID:         vapid,
+		Subscriptions: subscriptions,
+		Title:         title,
+		TTL:           ttl,
+		Logger:        logger,
+		Now:           time.Now,
+	}
+}
+
+// Send pushes the notification to each subscription. It fails only when no
+// browser got it, since the notification is sent again to all of them when it
+// is retried; when every subscription is gone, the user no longer has
+// browsers to push to.
+func (c *Client) Send(recipient user.User, userNotification notification.Notification) error {
+	config := recipient.NotificationConfig.Push
+
+	if !config.Enabled || len(config.Subscriptions) == 0 {
+		return notification.ErrUserOptOut
+	}
+
+	payload, err := c.encodeMessage(userNotification)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", notification.ErrUndeliverable, err)
+	}
+
+	subscriptions, err := c.Subscriptions.FindPushSubscriptions(recipient.ID)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, err)
+	}
+
+	delivered := 0
+	var errs []error
+
+	for _, subscription := range subscriptions {
+		err := c.push(subscription, payload)
+
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, ErrSubscriptionGone):
+			c.remove(recipient.ID, subscription)
+		default:
+			c.Logger.Warn("failed to push notification", zap.String("userID", recipient.ID), zap.String("device", subscription.Device), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", subscription.Device, err))
+		}
+	}
+
+	if delivered > 0 {
+		return nil
+	}
+
+	if len(errs) == 0 {
+		return notification.ErrUserOptOut
+	}
+
+	// Retrying pushes to every subscription again, so it is only worth it
+	// when one of them may succeed
+	var retryable []error
+
+	for _, err := range errs {
+		if !errors.Is(err, notification.ErrUndeliverable) {
+			retryable = append(retryable, err)
+		}
+	}
+
+	if len(retryable) == 0 {
+		return fmt.Errorf("%w: %w: %w", notification.ErrUndeliverable, ErrFailedToSend, errors.Join(errs...))
+	}
+
+	return fmt.Errorf("%w: %w", ErrFailedToSend, errors.Join(retryable...))
+}
+
+func (c *Client) push(subscription user.PushSubscription, payload []byte) error {
+	keys, err := ParseKeys(subscription.P256dh, subscription.Auth)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", notification.ErrUndeliverable, err)
+	}
+
+	body, err := Encrypt(payload, keys)
+
+	if errors.Is(err, ErrInvalidKeys) || errors.Is(err, ErrPayloadTooLarge) {
+		return fmt.Errorf("%w: %w", notification.ErrUndeliverable, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	authorization, err := c.VAPID.Authorization(subscription.Endpoint, c.Now().Add(vapidExpiration))
+
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, err)
+	}
+
+	request.Header.Set("Content-Type", "application/octet-stream")
+	request.Header.Set("Content-Encoding", "aes128gcm")
+	request.Header.Set("TTL", strconv.Itoa(int(c.TTL.Seconds())))
+	request.Header.Set("Authorization", authorization)
+
+	response, err := c.Client.Do(request)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusGone {
+		return ErrSubscriptionGone
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = fmt.Errorf("%w: unexpected status code: %d", ErrFailedToSend, response.StatusCode)
+
+		if isPermanent(response.StatusCode) {
+			return fmt.Errorf("%w: %w", notification.ErrUndeliverable, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// isPermanent tells the client errors that pushing again would not fix,
+// which are all but timeouts and rate limits
+func isPermanent(statusCode int) bool {
+	return statusCode >= 400 && statusCode < 500 && statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests
+}
+
+func (c *Client) remove(userID string, subscription user.PushSubscription) {
+	err := c.Subscriptions.RemovePushSubscription(userID, subscription.Device, subscription.Endpoint)
+
+	if err != nil {
+		c.Logger.Error("failed to remove expired push subscription", zap.String("userID", userID), zap.String("device", subscription.Device), zap.Error(err))
+		return
+	}
+
+	c.Logger.Info("removed expired push subscription", zap.String("userID", userID), zap.String("device", subscription.Device))
+}
+
+// encodeMessage fits the message in a push: the payload is left out when it
+// makes the message too large, and then the body is cut short
+func (c *Client) encodeMessage(userNotification notification.Notification) ([]byte, error) {
+	message := Message{
+		Title:   c.Title,
+		Body:    userNotification.Body(notification.FormatText),
+		Payload: userNotification.Payload,
+	}
+
+	encoded, err := json.Marshal(message)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMessageUnencodable, err)
+	}
+
+	if len(encoded) <= MaxPayloadSize {
+		return encoded, nil
+	}
+
+	message.Payload = nil
+
+	for {
+		encoded, err = json.Marshal(message)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMessageUnencodable, err)
+		}
+
+		excess := len(encoded) - MaxPayloadSize
+
+		if excess <= 0 {
+			return encoded, nil
+		}
+
+		if message.Body == "" {
+			return nil, fmt.Errorf("%w: title does not fit in a push", ErrMessageUnencodable)
+		}
+
+		message.Body = truncate(message.Body, len(message.Body)-excess-len("..."))
+	}
+}
+
+// truncate cuts the text to at most size bytes, without splitting a rune,
+// and marks it as cut
+func truncate(text string, size int) string {
+	if size >= len(text) {
+		return text
+	}
+
+	if size <= 0 {
+		return ""
+	}
+
+	for size > 0 && !utf8.RuneStart(text[size]) {
+		size--
+	}
+
+	return text[:size] + "..."
+}
@@ -0,0 +1,268 @@
+package push
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestClient_Send(t *testing.T) {
+	tests := []struct {
+		name              string
+		enabled           bool
+		invalidKeys       bool
+		responseCodes     map[string]int
+		findError         error
+		removeError       error
+		expectedError     error
+		undeliverable     bool
+		expectedPushes    []string
+		expectedRemovals  []string
+		expectedEndpoints []string
+	}{
+		{
+			name:           "success",
+			enabled:        true,
+			responseCodes:  map[string]int{"/laptop": 201, "/phone": 201},
+			expectedError:  nil,
+			expectedPushes: []string{"/laptop", "/phone"},
+		},
+		{
+			name:              "expired subscription is removed",
+			enabled:           true,
+			responseCodes:     map[string]int{"/laptop": 201, "/phone": 410},
+			expectedError:     nil,
+			expectedPushes:    []string{"/laptop", "/phone"},
+			expectedRemovals:  []string{"phone"},
+			expectedEndpoints: []string{"/phone"},
+		},
+		{
+			name:              "every subscription expired",
+			enabled:           true,
+			responseCodes:     map[string]int{"/laptop": 404, "/phone": 410},
+			expectedError:     notification.ErrUserOptOut,
+			expectedPushes:    []string{"/laptop", "/phone"},
+			expectedRemovals:  []string{"laptop", "phone"},
+			expectedEndpoints: []string{"/laptop", "/phone"},
+		},
+		{
+			name:              "error removing subscription",
+			enabled:           true,
+			responseCodes:     map[string]int{"/laptop": 201, "/phone": 410},
+			removeError:       user.ErrRequestAPI,
+			expectedError:     nil,
+			expectedPushes:    []string{"/laptop", "/phone"},
+			expectedRemovals:  []string{"phone"},
+			expectedEndpoints: []string{"/phone"},
+		},
+		{
+			name:           "push service error on one device",
+			enabled:        true,
+			responseCodes:  map[string]int{"/laptop": 500, "/phone": 201},
+			expectedError:  nil,
+			expectedPushes: []string{"/laptop", "/phone"},
+		},
+		{
+			name:           "push service error on every device",
+			enabled:        true,
+			responseCodes:  map[string]int{"/laptop": 500, "/phone": 429},
+			expectedError:  ErrFailedToSend,
+			expectedPushes: []string{"/laptop", "/phone"},
+		},
+		{
+			name:           "push service refuses every device",
+			enabled:        true,
+			responseCodes:  map[string]int{"/laptop": 400, "/phone": 413},
+			expectedError:  ErrFailedToSend,
+			undeliverable:  true,
+			expectedPushes: []string{"/laptop", "/phone"},
+		},
+		{
+			name:           "push service refuses one device and fails on the other",
+			enabled:        true,
+			responseCodes:  map[string]int{"/laptop": 403, "/phone": 503},
+			expectedError:  ErrFailedToSend,
+			undeliverable:  false,
+			expectedPushes: []string{"/laptop", "/phone"},
+		},
+		{
+			name:           "invalid keys",
+			enabled:        true,
+			invalidKeys:    true,
+			responseCodes:  map[string]int{"/laptop": 400},
+			expectedError:  ErrFailedToSend,
+			undeliverable:  true,
+			expectedPushes: []string{"/laptop"},
+		},
+		{
+			name:           "invalid keys on one device",
+			enabled:        true,
+			invalidKeys:    true,
+			responseCodes:  map[string]int{"/laptop": 201},
+			expectedError:  nil,
+			expectedPushes: []string{"/laptop"},
+		},
+		{
+			name:          "error finding subscriptions",
+			enabled:       true,
+			findError:     user.ErrRequestAPI,
+			expectedError: ErrFailedToSend,
+		},
+		{
+			name:          "push disabled",
+			enabled:       false,
+			expectedError: notification.ErrUserOptOut,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pushes := []string{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pushes = append(pushes, r.URL.Path)
+
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "aes128gcm", r.Header.Get("Content-Encoding"))
+				assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+				assert.Equal(t, "86400", r.Header.Get("TTL"))
+				assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "vapid t="))
+
+				body, _ := io.ReadAll(r.Body)
+
+				var message Message
+				assert.Nil(t, json.Unmarshal(decrypt(t, body), &message))
+				assert.Equal(t, Message{Title: "Previsão do tempo", Body: "Manhã de sol", Payload: json.RawMessage(`{"type":"forecast"}`)}, message)
+
+				w.WriteHeader(tt.responseCodes[r.URL.Path])
+			}))
+
+			defer server.Close()
+
+			vapid, err := NewVAPID(exampleServerPrivateKey, "mailto:previsao@example.com")
+			assert.Nil(t, err)
+
+			storeMock := &subscriptionStoreMock{
+				findResult: []user.PushSubscription{
+					{Device: "laptop", Endpoint: server.URL + "/laptop", P256dh: exampleUserAgentPublic, Auth: exampleAuth},
+					{Device: "phone", Endpoint: server.URL + "/phone", P256dh: exampleUserAgentPublic, Auth: exampleAuth},
+				},
+				findError:   tt.findError,
+				removeError: tt.removeError,
+			}
+
+			if tt.invalidKeys {
+				storeMock.findResult[1].P256dh = exampleAuth
+			}
+
+			client := NewClient(server.Client(), vapid, storeMock, "Previsão do tempo", 24*time.Hour, zap.NewNop())
+
+			recipient := user.User{
+				ID: "USER-123",
+				NotificationConfig: user.NotificationConfig{
+					Enabled: true,
+					Push: user.PushNotificationConfig{
+						Enabled: tt.enabled,
+						Subscriptions: []user.PushSubscription{
+							{Device: "laptop", Endpoint: server.URL + "/laptop"},
+							{Device: "phone", Endpoint: server.URL + "/phone"},
+						},
+					},
+				},
+			}
+
+			err = client.Send(recipient, notification.Notification{
+				UserID:  "USER-123",
+				Content: "Manhã de sol",
+				Payload: json.RawMessage(`{"type":"forecast"}`),
+			})
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.undeliverable, errors.Is(err, notification.ErrUndeliverable))
+
+			if tt.expectedPushes == nil {
+				tt.expectedPushes = []string{}
+			}
+
+			assert.Equal(t, tt.expectedPushes, pushes)
+			assert.Equal(t, tt.expectedRemovals, storeMock.removeCallsDevice)
+
+			for i, endpoint := range tt.expectedEndpoints {
+				assert.Equal(t, "USER-123", storeMock.removeCallsUserID[i])
+				assert.Equal(t, server.URL+endpoint, storeMock.removeCallsEndpoint[i])
+			}
+		})
+	}
+}
+
+func TestClient_encodeMessage(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		payload         string
+		expectedBody    string
+		expectedPayload bool
+	}{
+		{
+			name:            "fits",
+			content:         "Manhã de sol",
+			payload:         `{"type":"forecast"}`,
+			expectedBody:    "Manhã de sol",
+			expectedPayload: true,
+		},
+		{
+			name:            "payload too large",
+			content:         "Manhã de sol",
+			payload:         `{"days":"` + strings.Repeat("a", MaxPayloadSize) + `"}`,
+			expectedBody:    "Manhã de sol",
+			expectedPayload: false,
+		},
+		{
+			name:            "body too large",
+			content:         strings.Repeat("ã", MaxPayloadSize),
+			payload:         `{"type":"forecast"}`,
+			expectedPayload: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(nil, nil, nil, "Previsão do tempo", time.Hour, zap.NewNop())
+
+			encoded, err := client.encodeMessage(notification.Notification{
+				Content: tt.content,
+				Payload: json.RawMessage(tt.payload),
+			})
+
+			assert.Nil(t, err)
+			assert.LessOrEqual(t, len(encoded), MaxPayloadSize)
+
+			var message Message
+			assert.Nil(t, json.Unmarshal(encoded, &message))
+
+			assert.Equal(t, "Previsão do tempo", message.Title)
+			assert.Equal(t, tt.expectedPayload, message.Payload != nil)
+
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, message.Body)
+				return
+			}
+
+			assert.True(t, utf8.ValidString(message.Body))
+			assert.True(t, strings.HasSuffix(message.Body, "..."))
+			assert.True(t, strings.HasPrefix(tt.content, strings.TrimSuffix(message.Body, "...")))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package push
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// recordSize is the size of the only aes128gcm record of a push, which
+	// push services must accept up to 4096 bytes
+	recordSize = 4096
+
+	saltSize      = 16
+	publicKeySize = 65
+	authSize      = 16
+	tagSize       = 16
+
+	headerSize = saltSize + 4 + 1 + publicKeySize
+
+	// MaxPayloadSize is the longest payload that fits in a push, after the
+	// header, the delimiter of the record and the tag of AES-GCM
+	MaxPayloadSize = recordSize - headerSize - 1 - tagSize
+)
+
+var (
+	ErrInvalidKeys     = errors.New("invalid subscription keys")
+	ErrPayloadTooLarge = errors.New("payload too large for a push")
+)
+
+// Keys are the keys of a subscription, which the browser decrypts the pushes
+// sent to it with: P256dh is its public key and Auth the secret shared with
+// the server.
+type Keys struct {
+	P256dh []byte
+	Auth   []byte
+}
+
+// ParseKeys reads the keys of a subscription, in base64url as browsers give
+// them
+func ParseKeys(p256dh, auth string) (Keys, error) {
+	publicKey, err := decodeBase64URL(p256dh)
+
+	if err != nil || len(publicKey) != publicKeySize {
+		return Keys{}, fmt.Errorf("%w: p256dh must be an uncompressed P-256 public key", ErrInvalidKeys)
+	}
+
+	secret, err := decodeBase64URL(auth)
+
+	if err != nil || len(secret) != authSize {
+		return Keys{}, fmt.Errorf("%w: auth must have %d bytes", ErrInvalidKeys, authSize)
+	}
+
+	return Keys{P256dh: publicKey, Auth: secret}, nil
+}
+
+// Encrypt encrypts the payload for the subscription as RFC 8291 tells, in a
+// single aes128gcm record (RFC 8188), with a new key pair and salt for each
+// push
+func Encrypt(payload []byte, keys Keys) ([]byte, error) {
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("error generating key: %w", err)
+	}
+
+	salt := make([]byte, saltSize)
+
+	_, err = rand.Read(salt)
+
+	if err != nil {
+		return nil, fmt.Errorf("error generating salt: %w", err)
+	}
+
+	return encrypt(payload, keys, serverKey, salt)
+}
+
+func encrypt(payload []byte, keys Keys, serverKey *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d", ErrPayloadTooLarge, len(payload), MaxPayloadSize)
+	}
+
+	userAgentKey, err := ecdh.P256().NewPublicKey(keys.P256dh)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeys, err)
+	}
+
+	sharedSecret, err := serverKey.ECDH(userAgentKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeys, err)
+	}
+
+	serverPublicKey := serverKey.PublicKey().Bytes()
+
+	keyInfo := concat([]byte("WebPush: info\x00"), keys.P256dh, serverPublicKey)
+	ikm := hkdf(keys.Auth, sharedSecret, keyInfo, 32)
+
+	contentKey := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(contentKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(serverPublicKey)))
+	header = append(header, serverPublicKey...)
+
+	// 0x02 marks the last record, which is the only one
+	record := append(append([]byte{}, payload...), 0x02)
+
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// hkdf is HKDF-SHA-256 (RFC 5869) for outputs of up to 32 bytes, which is all
+// RFC 8291 needs, so the expansion takes a single block
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm)
+
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write(info)
+	expand.Write([]byte{0x01})
+
+	return expand.Sum(nil)[:length]
+}
+
+func concat(parts ...[]byte) []byte {
+	var result []byte
+
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+
+	return result
+}
+
+// decodeBase64URL reads base64url with or without padding
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
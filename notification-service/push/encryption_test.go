@@ -0,0 +1,162 @@
+package push
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The example of RFC 8291, Appendix A
+const (
+	exampleServerPrivateKey = "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"
+	exampleUserAgentPrivate = "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94"
+	exampleUserAgentPublic  = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	exampleAuth             = "BTBZMqHH6r4Tts7J_aSIgg"
+	exampleSalt             = "DGv6ra1nlYgDCS1FRnbzlw"
+	examplePlaintext        = "When I grow up, I want to be a watermelon"
+	exampleMessage          = "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+)
+
+func TestEncrypt_Example(t *testing.T) {
+	keys, err := ParseKeys(exampleUserAgentPublic, exampleAuth)
+	assert.Nil(t, err)
+
+	serverKey, err := ecdh.P256().NewPrivateKey(mustDecode(t, exampleServerPrivateKey))
+	assert.Nil(t, err)
+
+	message, err := encrypt([]byte(examplePlaintext), keys, serverKey, mustDecode(t, exampleSalt))
+
+	assert.Nil(t, err)
+	assert.Equal(t, exampleMessage, base64.RawURLEncoding.EncodeToString(message))
+}
+
+func TestEncrypt(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       string
+		expectedError error
+	}{
+		{
+			name:    "short payload",
+			payload: `{"title":"Previsão do tempo","body":"Manhã de sol"}`,
+		},
+		{
+			name:    "largest payload",
+			payload: strings.Repeat("a", MaxPayloadSize),
+		},
+		{
+			name:          "payload too large",
+			payload:       strings.Repeat("a", MaxPayloadSize+1),
+			expectedError: ErrPayloadTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseKeys(exampleUserAgentPublic, exampleAuth)
+			assert.Nil(t, err)
+
+			message, err := Encrypt([]byte(tt.payload), keys)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+
+			if tt.expectedError != nil {
+				return
+			}
+
+			assert.LessOrEqual(t, len(message), recordSize)
+			assert.Equal(t, tt.payload, string(decrypt(t, message)))
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		p256dh        string
+		auth          string
+		expectedError error
+	}{
+		{
+			name:   "success",
+			p256dh: exampleUserAgentPublic,
+			auth:   exampleAuth,
+		},
+		{
+			name:   "padded",
+			p256dh: exampleUserAgentPublic + "=",
+			auth:   exampleAuth + "==",
+		},
+		{
+			name:          "p256dh not a public key",
+			p256dh:        exampleAuth,
+			auth:          exampleAuth,
+			expectedError: ErrInvalidKeys,
+		},
+		{
+			name:          "auth not base64url",
+			p256dh:        exampleUserAgentPublic,
+			auth:          "not base64!",
+			expectedError: ErrInvalidKeys,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseKeys(tt.p256dh, tt.auth)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+
+			if tt.expectedError == nil {
+				assert.Len(t, keys.P256dh, 65)
+				assert.Len(t, keys.Auth, 16)
+			}
+		})
+	}
+}
+
+// decrypt is what the browser of the example does with a push
+func decrypt(t *testing.T, message []byte) []byte {
+	salt := message[:saltSize]
+	assert.Equal(t, uint32(recordSize), binary.BigEndian.Uint32(message[saltSize:]))
+
+	serverPublicKey, err := ecdh.P256().NewPublicKey(message[saltSize+5 : headerSize])
+	assert.Nil(t, err)
+
+	userAgentKey, err := ecdh.P256().NewPrivateKey(mustDecode(t, exampleUserAgentPrivate))
+	assert.Nil(t, err)
+
+	sharedSecret, err := userAgentKey.ECDH(serverPublicKey)
+	assert.Nil(t, err)
+
+	keyInfo := concat([]byte("WebPush: info\x00"), userAgentKey.PublicKey().Bytes(), serverPublicKey.Bytes())
+	ikm := hkdf(mustDecode(t, exampleAuth), sharedSecret, keyInfo, 32)
+
+	block, _ := aes.NewCipher(hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16))
+	gcm, _ := cipher.NewGCM(block)
+
+	record, err := gcm.Open(nil, hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12), message[headerSize:], nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, byte(0x02), record[len(record)-1])
+
+	return record[:len(record)-1]
+}
+
+func mustDecode(t *testing.T, value string) []byte {
+	decoded, err := decodeBase64URL(value)
+
+	if err != nil {
+		t.Fatalf("invalid base64url %s: %s", value, err.Error())
+	}
+
+	return decoded
+}
@@ -0,0 +1,28 @@
+package push
+
+import "github.com/fgouvea/weather/notification-service/user"
+
+type subscriptionStoreMock struct {
+	findCallsUserID []string
+	findResult      []user.PushSubscription
+	findError       error
+
+	removeCallsUserID   []string
+	removeCallsDevice   []string
+	removeCallsEndpoint []string
+	removeError         error
+}
+
+var _ SubscriptionStore = (*subscriptionStoreMock)(nil)
+
+func (m *subscriptionStoreMock) FindPushSubscriptions(userID string) ([]user.PushSubscription, error) {
+	m.findCallsUserID = append(m.findCallsUserID, userID)
+	return m.findResult, m.findError
+}
+
+func (m *subscriptionStoreMock) RemovePushSubscription(userID, device, endpoint string) error {
+	m.removeCallsUserID = append(m.removeCallsUserID, userID)
+	m.removeCallsDevice = append(m.removeCallsDevice, device)
+	m.removeCallsEndpoint = append(m.removeCallsEndpoint, endpoint)
+	return m.removeError
+}
@@ -0,0 +1,133 @@
+package push
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrInvalidVAPIDKey = errors.New("invalid vapid private key")
+)
+
+// vapidHeader is the header of every token, which is always signed with
+// ES256
+var vapidHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+
+type vapidClaims struct {
+	Audience   string `json:"aud"`
+	Expiration int64  `json:"exp"`
+	Subject    string `json:"sub"`
+}
+
+// VAPID identifies the server to push services (RFC 8292). Subscriptions are
+// made with its public key, so the key must not change while they are used.
+// Subject is how the push service reaches whoever runs the server, as a
+// mailto: or https: URL.
+type VAPID struct {
+	Subject string
+
+	key       *ecdsa.PrivateKey
+	publicKey []byte
+}
+
+// NewVAPID reads the private key, a P-256 scalar in base64url, as generated
+// by the usual web push tools
+func NewVAPID(privateKey, subject string) (*VAPID, error) {
+	scalar, err := decodeBase64URL(privateKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVAPIDKey, err)
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(scalar)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVAPIDKey, err)
+	}
+
+	return newVAPID(key, subject), nil
+}
+
+// GenerateVAPID creates a new key, for when none is configured. Browsers
+// subscribed with it must subscribe again once it changes.
+func GenerateVAPID(subject string) (*VAPID, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("error generating vapid key: %w", err)
+	}
+
+	return newVAPID(key, subject), nil
+}
+
+func newVAPID(key *ecdh.PrivateKey, subject string) *VAPID {
+	publicKey := key.PublicKey().Bytes()
+
+	return &VAPID{
+		Subject: subject,
+
+		key: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(publicKey[1:33]),
+				Y:     new(big.Int).SetBytes(publicKey[33:]),
+			},
+			D: new(big.Int).SetBytes(key.Bytes()),
+		},
+		publicKey: publicKey,
+	}
+}
+
+// PublicKey is the applicationServerKey browsers subscribe with, in
+// base64url
+func (v *VAPID) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(v.publicKey)
+}
+
+// Authorization is the Authorization header of a push to the endpoint, with
+// a token valid until the expiration, which push services limit to 24 hours
+func (v *VAPID) Authorization(endpoint string, expiration time.Time) (string, error) {
+	parsed, err := url.Parse(endpoint)
+
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	claims, err := json.Marshal(vapidClaims{
+		Audience:   parsed.Scheme + "://" + parsed.Host,
+		Expiration: expiration.Unix(),
+		Subject:    v.Subject,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("error encoding vapid claims: %w", err)
+	}
+
+	unsigned := vapidHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+
+	r, s, err := ecdsa.Sign(rand.Reader, v.key, digest[:])
+
+	if err != nil {
+		return "", fmt.Errorf("error signing vapid token: %w", err)
+	}
+
+	// JWS signatures are r and s with 32 bytes each, rather than ASN.1
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	return fmt.Sprintf("vapid t=%s, k=%s", token, v.PublicKey()), nil
+}
@@ -0,0 +1,90 @@
+package push
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVAPID(t *testing.T) {
+	tests := []struct {
+		name              string
+		privateKey        string
+		expectedPublicKey string
+		expectedError     error
+	}{
+		{
+			name:              "success",
+			privateKey:        exampleServerPrivateKey,
+			expectedPublicKey: "BP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A8",
+		},
+		{
+			name:          "not base64url",
+			privateKey:    "not base64!",
+			expectedError: ErrInvalidVAPIDKey,
+		},
+		{
+			name:          "wrong length",
+			privateKey:    exampleAuth,
+			expectedError: ErrInvalidVAPIDKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vapid, err := NewVAPID(tt.privateKey, "mailto:previsao@example.com")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedPublicKey, vapid.PublicKey())
+			}
+		})
+	}
+}
+
+func TestVAPID_Authorization(t *testing.T) {
+	vapid, err := NewVAPID(exampleServerPrivateKey, "mailto:previsao@example.com")
+	assert.Nil(t, err)
+
+	expiration := time.Date(2025, 3, 11, 0, 30, 0, 0, time.UTC)
+
+	authorization, err := vapid.Authorization("https://push.example.com/send/abc?x=1", expiration)
+	assert.Nil(t, err)
+
+	token, key, found := strings.Cut(strings.TrimPrefix(authorization, "vapid t="), ", k=")
+	assert.True(t, found, authorization)
+	assert.Equal(t, vapid.PublicKey(), key)
+
+	parts := strings.Split(token, ".")
+	assert.Len(t, parts, 3)
+
+	header := mustDecode(t, parts[0])
+	assert.JSONEq(t, `{"typ":"JWT","alg":"ES256"}`, string(header))
+
+	var claims vapidClaims
+	assert.Nil(t, json.Unmarshal(mustDecode(t, parts[1]), &claims))
+	assert.Equal(t, vapidClaims{Audience: "https://push.example.com", Expiration: expiration.Unix(), Subject: "mailto:previsao@example.com"}, claims)
+
+	publicKey := mustDecode(t, key)
+	signature := mustDecode(t, parts[2])
+	assert.Len(t, signature, 64)
+
+	verifier := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(publicKey[1:33]),
+		Y:     new(big.Int).SetBytes(publicKey[33:]),
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	assert.True(t, ecdsa.Verify(verifier, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+}
@@ -90,6 +90,19 @@ func (c *NotificationConsumer) consume(consumerName string, delivery amqp.Delive
 		return
 	}
 
+	if errors.Is(err, notification.ErrUndeliverable) {
+		c.Logger.Error("notification cannot be delivered", zap.String("consumer", consumerName), zap.String("userID", string(userNotification.UserID)), zap.Error(err))
+		delivery.Nack(false, false)
+		return
+	}
+
+	// Sending it again would not find the channel either
+	if errors.Is(err, notification.ErrUnknownChannel) {
+		c.Logger.Error("unknown notification channel", zap.String("consumer", consumerName), zap.String("userID", string(userNotification.UserID)), zap.String("channel", userNotification.Channel))
+		delivery.Nack(false, false)
+		return
+	}
+
 	if err != nil {
 		c.Logger.Error("error processing notification", zap.String("consumer", consumerName), zap.String("userID", string(userNotification.UserID)), zap.Error(err))
 		delivery.Nack(false, true)
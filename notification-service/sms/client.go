@@ -0,0 +1,124 @@
+package sms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+)
+
+var (
+	ErrFailedToSend = errors.New("failed to send notification by sms")
+)
+
+// Provider delivers text messages to phone numbers in E.164 format
+type Provider interface {
+	SendSMS(phone, text string) error
+}
+
+// Client sends the plain text of notifications by SMS, summarized to fit in
+// MaxSegments messages. With Transliterate, accented letters are replaced
+// so the text is sent as GSM-7.
+type Client struct {
+	Provider      Provider
+	MaxSegments   int
+	Transliterate bool
+}
+
+func NewClient(provider Provider, maxSegments int, transliterate bool) *Client {
+	return &Client{
+		Provider:      provider,
+		MaxSegments:   max(maxSegments, 1),
+		Transliterate: transliterate,
+	}
+}
+
+func (c *Client) Send(recipient user.User, userNotification notification.Notification) error {
+	config := recipient.NotificationConfig.SMS
+
+	if !config.Enabled || config.Phone == "" {
+		return notification.ErrUserOptOut
+	}
+
+	text := userNotification.Body(notification.FormatText)
+
+	if c.Transliterate {
+		text = Transliterate(text)
+	}
+
+	err := c.Provider.SendSMS(config.Phone, Summarize(text, c.MaxSegments))
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, err)
+	}
+
+	return nil
+}
+
+// Message is posted by HTTPProvider, with the encoding and number of
+// segments for providers that charge by them
+type Message struct {
+	To       string   `json:"to"`
+	Text     string   `json:"text"`
+	Encoding Encoding `json:"encoding"`
+	Segments int      `json:"segments"`
+}
+
+// HTTPProvider posts messages to an SMS gateway as JSON, authenticated by a
+// bearer token when one is configured
+type HTTPProvider struct {
+	Client *http.Client
+	URL    string
+	Token  string
+}
+
+func NewHTTPProvider(httpClient *http.Client, url, token string) *HTTPProvider {
+	return &HTTPProvider{
+		Client: httpClient,
+		URL:    url,
+		Token:  token,
+	}
+}
+
+func (p *HTTPProvider) SendSMS(phone, text string) error {
+	body, err := json.Marshal(Message{
+		To:       phone,
+		Text:     text,
+		Encoding: EncodingOf(text),
+		Segments: Segments(text),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, p.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	if p.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	response, err := p.Client.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
+
+	return nil
+}
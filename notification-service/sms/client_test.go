@@ -0,0 +1,142 @@
+package sms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Send(t *testing.T) {
+	tests := []struct {
+		name          string
+		enabled       bool
+		transliterate bool
+		providerError error
+		expectedText  []string
+		expectedError error
+	}{
+		{
+			name:          "success",
+			enabled:       true,
+			expectedText:  []string{"Previsão para Rio de Janeiro\nManhã: 23-32°C"},
+			expectedError: nil,
+		},
+		{
+			name:          "transliterated",
+			enabled:       true,
+			transliterate: true,
+			expectedText:  []string{"Previsao para Rio de Janeiro\nManha: 23-32C"},
+			expectedError: nil,
+		},
+		{
+			name:          "sms notification disabled",
+			enabled:       false,
+			expectedText:  nil,
+			expectedError: notification.ErrUserOptOut,
+		},
+		{
+			name:          "provider error",
+			enabled:       true,
+			providerError: fmt.Errorf("runtime error"),
+			expectedText:  []string{"Previsão para Rio de Janeiro\nManhã: 23-32°C"},
+			expectedError: ErrFailedToSend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &providerMock{sendSMSError: tt.providerError}
+
+			client := NewClient(provider, 1, tt.transliterate)
+
+			recipient := user.User{
+				NotificationConfig: user.NotificationConfig{
+					Enabled: true,
+					SMS: user.SMSNotificationConfig{
+						Enabled: tt.enabled,
+						Phone:   "+5521999990000",
+					},
+				},
+			}
+
+			err := client.Send(recipient, notification.Notification{
+				Content: "Previsão para Rio de Janeiro\n\nManhã: 23-32°C\n",
+				HTML:    "<p>Previsão para Rio de Janeiro</p>",
+			})
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedText, provider.sendSMSCallsText)
+
+			if tt.expectedText != nil {
+				assert.Equal(t, []string{"+5521999990000"}, provider.sendSMSCallsPhone)
+			}
+		})
+	}
+}
+
+func TestHTTPProvider_SendSMS(t *testing.T) {
+	tests := []struct {
+		name            string
+		token           string
+		apiResponseCode int
+		expectedError   bool
+	}{
+		{
+			name:            "success",
+			token:           "secret",
+			apiResponseCode: 200,
+			expectedError:   false,
+		},
+		{
+			name:            "any 2xx is a success",
+			apiResponseCode: 202,
+			expectedError:   false,
+		},
+		{
+			name:            "api error",
+			apiResponseCode: 500,
+			expectedError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/sms", r.URL.String())
+
+				if tt.token != "" {
+					assert.Equal(t, "Bearer "+tt.token, r.Header.Get("Authorization"))
+				} else {
+					assert.Empty(t, r.Header.Get("Authorization"))
+				}
+
+				var body Message
+
+				err := json.NewDecoder(r.Body).Decode(&body)
+
+				if err != nil {
+					assert.Fail(t, fmt.Sprintf("failed to decode body: %s", err.Error()))
+				}
+
+				assert.Equal(t, Message{To: "+5521999990000", Text: "Manhã: 23-32°C", Encoding: EncodingUCS2, Segments: 1}, body)
+
+				w.WriteHeader(tt.apiResponseCode)
+			}))
+
+			defer server.Close()
+
+			provider := NewHTTPProvider(server.Client(), server.URL+"/sms", tt.token)
+
+			err := provider.SendSMS("+5521999990000", "Manhã: 23-32°C")
+
+			assert.Equal(t, tt.expectedError, err != nil)
+		})
+	}
+}
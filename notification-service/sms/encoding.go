@@ -0,0 +1,110 @@
+package sms
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// gsmBasic are the characters of the GSM 03.38 default alphabet, taking one
+// septet each, and gsmExtended the ones taking two, with an escape
+const (
+	gsmBasic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsmExtended = "^{}\\[~]|€\f"
+)
+
+// Segment sizes of single and concatenated messages. Concatenated segments
+// lose room to the header joining them.
+const (
+	gsmSingle  = 160
+	gsmPart    = 153
+	ucs2Single = 70
+	ucs2Part   = 67
+)
+
+type Encoding string
+
+const (
+	EncodingGSM7 Encoding = "GSM-7"
+	EncodingUCS2 Encoding = "UCS-2"
+)
+
+// transliterations replace what is missing from the GSM alphabet in
+// Portuguese and Spanish texts, so "previsão" does not turn the whole
+// message into UCS-2, which fits less than half as much per segment.
+var transliterations = strings.NewReplacer(
+	"á", "a", "â", "a", "ã", "a", "Á", "A", "Â", "A", "Ã", "A", "À", "A",
+	"ê", "e", "Ê", "E", "È", "E",
+	"í", "i", "î", "i", "Í", "I", "Ì", "I",
+	"ó", "o", "ô", "o", "õ", "o", "Ó", "O", "Ô", "O", "Õ", "O", "Ò", "O",
+	"ú", "u", "û", "u", "Ú", "U", "Ù", "U",
+	"ç", "c",
+	"°", "", "º", "o", "ª", "a",
+	"–", "-", "—", "-", "…", "...", "‘", "'", "’", "'", "“", "\"", "”", "\"",
+)
+
+// Transliterate replaces the accented letters and punctuation missing from
+// the GSM alphabet with their closest GSM characters
+func Transliterate(text string) string {
+	return transliterations.Replace(text)
+}
+
+// EncodingOf is how the text is sent: GSM-7 when every character is in the
+// GSM alphabet, and UCS-2 otherwise
+func EncodingOf(text string) Encoding {
+	for _, r := range text {
+		if !strings.ContainsRune(gsmBasic, r) && !strings.ContainsRune(gsmExtended, r) {
+			return EncodingUCS2
+		}
+	}
+
+	return EncodingGSM7
+}
+
+// Length is the size of the text in its encoding: septets for GSM-7 and
+// UTF-16 code units for UCS-2
+func Length(text string, encoding Encoding) int {
+	if encoding == EncodingUCS2 {
+		return len(utf16.Encode([]rune(text)))
+	}
+
+	length := 0
+
+	for _, r := range text {
+		length += runeLength(r, encoding)
+	}
+
+	return length
+}
+
+// Segments is how many messages the text is sent as
+func Segments(text string) int {
+	encoding := EncodingOf(text)
+	length := Length(text, encoding)
+	single, part := segmentSizes(encoding)
+
+	if length <= single {
+		return 1
+	}
+
+	return (length + part - 1) / part
+}
+
+func runeLength(r rune, encoding Encoding) int {
+	if encoding == EncodingUCS2 {
+		return len(utf16.Encode([]rune{r}))
+	}
+
+	if strings.ContainsRune(gsmExtended, r) {
+		return 2
+	}
+
+	return 1
+}
+
+func segmentSizes(encoding Encoding) (int, int) {
+	if encoding == EncodingUCS2 {
+		return ucs2Single, ucs2Part
+	}
+
+	return gsmSingle, gsmPart
+}
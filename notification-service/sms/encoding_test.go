@@ -0,0 +1,92 @@
+package sms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		name             string
+		text             string
+		expectedEncoding Encoding
+		expectedLength   int
+		expectedSegments int
+	}{
+		{
+			name:             "gsm text",
+			text:             "Manha de sol, 23-32C",
+			expectedEncoding: EncodingGSM7,
+			expectedLength:   20,
+			expectedSegments: 1,
+		},
+		{
+			name:             "gsm accents",
+			text:             "Café à noite",
+			expectedEncoding: EncodingGSM7,
+			expectedLength:   12,
+			expectedSegments: 1,
+		},
+		{
+			name:             "gsm extended characters take two septets",
+			text:             "[€]",
+			expectedEncoding: EncodingGSM7,
+			expectedLength:   6,
+			expectedSegments: 1,
+		},
+		{
+			name:             "ucs-2 for characters out of the gsm alphabet",
+			text:             "Manhã",
+			expectedEncoding: EncodingUCS2,
+			expectedLength:   5,
+			expectedSegments: 1,
+		},
+		{
+			name:             "emoji take two code units",
+			text:             "Sol ☀️🌊",
+			expectedEncoding: EncodingUCS2,
+			expectedLength:   8,
+			expectedSegments: 1,
+		},
+		{
+			name:             "single gsm segment",
+			text:             strings.Repeat("a", 160),
+			expectedEncoding: EncodingGSM7,
+			expectedLength:   160,
+			expectedSegments: 1,
+		},
+		{
+			name:             "concatenated gsm segments",
+			text:             strings.Repeat("a", 307),
+			expectedEncoding: EncodingGSM7,
+			expectedLength:   307,
+			expectedSegments: 3,
+		},
+		{
+			name:             "concatenated ucs-2 segments",
+			text:             "previsão " + strings.Repeat("a", 62),
+			expectedEncoding: EncodingUCS2,
+			expectedLength:   71,
+			expectedSegments: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoding := EncodingOf(tt.text)
+
+			assert.Equal(t, tt.expectedEncoding, encoding)
+			assert.Equal(t, tt.expectedLength, Length(tt.text, encoding))
+			assert.Equal(t, tt.expectedSegments, Segments(tt.text))
+		})
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	text := Transliterate("Previsão para São Paulo – manhã: 23°C, índice UV alto… Café")
+
+	assert.Equal(t, "Previsao para Sao Paulo - manha: 23C, indice UV alto... Café", text)
+	assert.Equal(t, EncodingGSM7, EncodingOf(text))
+}
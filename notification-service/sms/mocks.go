@@ -0,0 +1,15 @@
+package sms
+
+type providerMock struct {
+	sendSMSCallsPhone []string
+	sendSMSCallsText  []string
+	sendSMSError      error
+}
+
+var _ Provider = (*providerMock)(nil)
+
+func (m *providerMock) SendSMS(phone, text string) error {
+	m.sendSMSCallsPhone = append(m.sendSMSCallsPhone, phone)
+	m.sendSMSCallsText = append(m.sendSMSCallsText, text)
+	return m.sendSMSError
+}
@@ -0,0 +1,94 @@
+package sms
+
+import (
+	"strings"
+)
+
+const ellipsis = "..."
+
+// Summarize fits the text in at most maxSegments messages. Blank lines and
+// repeated spaces are dropped first, then the lines that do not fit, and
+// the last line is cut at a word when even the first does not fit.
+func Summarize(text string, maxSegments int) string {
+	text = compact(text)
+
+	if Segments(text) <= maxSegments {
+		return text
+	}
+
+	encoding := EncodingOf(text)
+	single, part := segmentSizes(encoding)
+
+	limit := single
+
+	if maxSegments > 1 {
+		limit = part * maxSegments
+	}
+
+	limit -= Length(ellipsis, encoding)
+
+	lines := strings.Split(text, "\n")
+	summary := ""
+
+	for _, line := range lines {
+		candidate := line
+
+		if summary != "" {
+			candidate = summary + "\n" + line
+		}
+
+		if Length(candidate, encoding) > limit {
+			break
+		}
+
+		summary = candidate
+	}
+
+	if summary == "" {
+		summary = truncate(lines[0], limit, encoding)
+	}
+
+	return summary + ellipsis
+}
+
+// compact removes blank lines and the spaces around and between words
+func compact(text string) string {
+	var lines []string
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// truncate cuts the line at the last word that fits in limit
+func truncate(line string, limit int, encoding Encoding) string {
+	length := 0
+	end := 0
+	lastSpace := 0
+
+	for i, r := range line {
+		length += runeLength(r, encoding)
+
+		if length > limit {
+			break
+		}
+
+		if r == ' ' {
+			lastSpace = i
+		}
+
+		end = i + len(string(r))
+	}
+
+	if lastSpace > 0 && end < len(line) {
+		return line[:lastSpace]
+	}
+
+	return line[:end]
+}
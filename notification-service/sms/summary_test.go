@@ -0,0 +1,57 @@
+package sms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		maxSegments int
+		expected    string
+	}{
+		{
+			name:        "fits",
+			text:        "Rio de Janeiro\n\n  10/02:  23-32C  \n",
+			maxSegments: 1,
+			expected:    "Rio de Janeiro\n10/02: 23-32C",
+		},
+		{
+			name:        "drops the lines that do not fit",
+			text:        "Rio de Janeiro\n" + strings.Repeat("10/02: 23-32C, ondas 1m\n", 10),
+			maxSegments: 1,
+			expected:    "Rio de Janeiro\n" + strings.TrimSuffix(strings.Repeat("10/02: 23-32C, ondas 1m\n", 5), "\n") + "...",
+		},
+		{
+			name:        "more segments fit more lines",
+			text:        "Rio de Janeiro\n" + strings.Repeat("10/02: 23-32C, ondas 1m\n", 10),
+			maxSegments: 2,
+			expected:    "Rio de Janeiro\n" + strings.TrimSuffix(strings.Repeat("10/02: 23-32C, ondas 1m\n", 10), "\n"),
+		},
+		{
+			name:        "ucs-2 segments are shorter",
+			text:        "Previsão para Rio de Janeiro\nManhã: 23-32°C\nTarde: 24-33°C\nNoite: 22-28°C",
+			maxSegments: 1,
+			expected:    "Previsão para Rio de Janeiro\nManhã: 23-32°C\nTarde: 24-33°C...",
+		},
+		{
+			name:        "cuts a long first line at a word",
+			text:        strings.Repeat("previsão ", 10),
+			maxSegments: 1,
+			expected:    "previsão previsão previsão previsão previsão previsão previsão...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Summarize(tt.text, tt.maxSegments)
+
+			assert.Equal(t, tt.expected, result)
+			assert.LessOrEqual(t, Segments(result), tt.maxSegments)
+		})
+	}
+}
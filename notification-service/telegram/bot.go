@@ -0,0 +1,213 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/fgouvea/weather/notification-service/weather"
+	"go.uber.org/zap"
+)
+
+// Channel is the notification channel of Telegram, which forecasts asked in
+// the chat are sent through
+const Channel = "telegram"
+
+const (
+	replyHelp           = "Comandos disponíveis:\n/start <código> conecta sua conta a este chat\n/previsao <cidade> envia a previsão do tempo, como /previsao Rio de Janeiro, RJ\n/parar desativa as notificações por aqui\n/voltar reativa as notificações por aqui"
+	replyStartHelp      = "Envie /start seguido do código gerado para a sua conta."
+	replyInvalidCode    = "Código inválido. Gere um novo código para a sua conta e tente de novo."
+	replyLinked         = "Pronto, %s! Você vai receber as notificações por aqui."
+	replyNotLinked      = "Este chat ainda não está conectado a uma conta. Envie /start seguido do código gerado para a sua conta."
+	replyForecastHelp   = "Envie /previsao seguido do nome da cidade, como /previsao Rio de Janeiro, RJ."
+	replyCityNotFound   = "Não encontrei a cidade %s."
+	replyMultipleCities = "Há mais de uma cidade chamada %s. Informe também o estado, como /previsao %s, SP."
+	replyStopped        = "Pronto, você não vai mais receber notificações por aqui. Envie /voltar para reativá-las."
+	replyResumed        = "Pronto, você vai voltar a receber as notificações por aqui."
+	replyChatDisabled   = "As notificações por aqui estão desativadas. Envie /voltar para reativá-las."
+	replyUserDisabled   = "As notificações da sua conta estão desativadas. Reative-as na sua conta para recebê-las por aqui."
+	replyError          = "Não foi possível atender o pedido agora. Tente de novo mais tarde."
+)
+
+type Messenger interface {
+	SendMessage(chatID int64, text string) error
+}
+
+type UserDirectory interface {
+	LinkTelegram(chatID int64, code string) (user.User, error)
+	FindByTelegramChat(chatID int64) (user.User, error)
+	SetTelegramNotifications(chatID int64, enabled bool) (user.User, error)
+}
+
+type ForecastRequester interface {
+	NotifyUser(userID, city, state, channel string) error
+}
+
+// Bot answers the commands users send to it: /start links the chat to the
+// account the code was created for, /previsao asks weather-service for the
+// forecast of a city, which arrives as a notification, and /parar and /voltar
+// turn the notifications sent to the chat off and on again. The other channels
+// of the user are left as they are.
+type Bot struct {
+	Messenger Messenger
+	Users     UserDirectory
+	Forecasts ForecastRequester
+	Logger    *zap.Logger
+}
+
+func NewBot(messenger Messenger, users UserDirectory, forecasts ForecastRequester, logger *zap.Logger) *Bot {
+	return &Bot{
+		Messenger: messenger,
+		Users:     users,
+		Forecasts: forecasts,
+		Logger:    logger,
+	}
+}
+
+// HandleUpdate answers the message of the update. Errors of the services are
+// answered with a generic reply and returned.
+func (b *Bot) HandleUpdate(update Update) error {
+	if update.Message == nil || update.Message.Text == "" {
+		return nil
+	}
+
+	chatID := update.Message.Chat.ID
+	command, argument := parseCommand(update.Message.Text)
+
+	var err error
+
+	switch command {
+	case "/start":
+		err = b.start(chatID, argument)
+	case "/previsao":
+		err = b.forecast(chatID, argument)
+	case "/parar":
+		err = b.stop(chatID)
+	case "/voltar":
+		err = b.resume(chatID)
+	default:
+		err = b.Messenger.SendMessage(chatID, replyHelp)
+	}
+
+	if err != nil {
+		b.Messenger.SendMessage(chatID, replyError)
+		return fmt.Errorf("error handling %s: %w", command, err)
+	}
+
+	return nil
+}
+
+func (b *Bot) start(chatID int64, code string) error {
+	if code == "" {
+		return b.Messenger.SendMessage(chatID, replyStartHelp)
+	}
+
+	linked, err := b.Users.LinkTelegram(chatID, code)
+
+	if errors.Is(err, user.ErrInvalidLinkingCode) {
+		return b.Messenger.SendMessage(chatID, replyInvalidCode)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	b.Logger.Info("telegram chat linked", zap.String("userID", linked.ID), zap.Int64("chatID", chatID))
+
+	return b.Messenger.SendMessage(chatID, fmt.Sprintf(replyLinked, linked.Name))
+}
+
+func (b *Bot) forecast(chatID int64, query string) error {
+	if query == "" {
+		return b.Messenger.SendMessage(chatID, replyForecastHelp)
+	}
+
+	recipient, err := b.Users.FindByTelegramChat(chatID)
+
+	if errors.Is(err, user.ErrUserNotFound) {
+		return b.Messenger.SendMessage(chatID, replyNotLinked)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !recipient.NotificationConfig.Enabled {
+		return b.Messenger.SendMessage(chatID, replyUserDisabled)
+	}
+
+	if !recipient.NotificationConfig.Telegram.Enabled {
+		return b.Messenger.SendMessage(chatID, replyChatDisabled)
+	}
+
+	city, state := parseCity(query)
+
+	err = b.Forecasts.NotifyUser(recipient.ID, city, state, Channel)
+
+	if errors.Is(err, weather.ErrCityNotFound) {
+		return b.Messenger.SendMessage(chatID, fmt.Sprintf(replyCityNotFound, query))
+	}
+
+	if errors.Is(err, weather.ErrMultipleCities) {
+		return b.Messenger.SendMessage(chatID, fmt.Sprintf(replyMultipleCities, city, city))
+	}
+
+	return err
+}
+
+func (b *Bot) stop(chatID int64) error {
+	recipient, err := b.Users.SetTelegramNotifications(chatID, false)
+
+	if errors.Is(err, user.ErrUserNotFound) {
+		return b.Messenger.SendMessage(chatID, replyNotLinked)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	b.Logger.Info("telegram notifications disabled", zap.String("userID", recipient.ID), zap.Int64("chatID", chatID))
+
+	return b.Messenger.SendMessage(chatID, replyStopped)
+}
+
+func (b *Bot) resume(chatID int64) error {
+	recipient, err := b.Users.SetTelegramNotifications(chatID, true)
+
+	if errors.Is(err, user.ErrUserNotFound) {
+		return b.Messenger.SendMessage(chatID, replyNotLinked)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	b.Logger.Info("telegram notifications enabled", zap.String("userID", recipient.ID), zap.Int64("chatID", chatID))
+
+	if !recipient.NotificationConfig.Enabled {
+		return b.Messenger.SendMessage(chatID, replyUserDisabled)
+	}
+
+	return b.Messenger.SendMessage(chatID, replyResumed)
+}
+
+// parseCommand splits a message into its command, without the bot name sent
+// in group chats, as in "/previsao@WeatherBot", and the rest of the text
+func parseCommand(text string) (string, string) {
+	command, argument, _ := strings.Cut(strings.TrimSpace(text), " ")
+	command, _, _ = strings.Cut(strings.ToLower(command), "@")
+
+	return command, strings.TrimSpace(argument)
+}
+
+// parseCity reads "Rio de Janeiro, RJ" as the city and its state
+func parseCity(query string) (string, string) {
+	city, state, found := strings.Cut(query, ",")
+
+	if !found {
+		return strings.TrimSpace(query), ""
+	}
+
+	return strings.TrimSpace(city), strings.TrimSpace(state)
+}
@@ -0,0 +1,207 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/fgouvea/weather/notification-service/weather"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestBot_HandleUpdate(t *testing.T) {
+	linkedUser := user.User{
+		ID:   "USER-123",
+		Name: "Fulano",
+		NotificationConfig: user.NotificationConfig{
+			Enabled:  true,
+			Telegram: user.TelegramNotificationConfig{Enabled: true, ChatID: 42},
+		},
+	}
+
+	chatDisabledUser := linkedUser
+	chatDisabledUser.NotificationConfig.Telegram.Enabled = false
+
+	userDisabledUser := linkedUser
+	userDisabledUser.NotificationConfig.Enabled = false
+
+	tests := []struct {
+		name              string
+		text              string
+		users             *userDirectoryMock
+		forecastError     error
+		expectedReplies   []string
+		expectedLinks     []linkCall
+		expectedForecasts []forecastCall
+		expectedSets      []setTelegramCall
+		expectedError     bool
+	}{
+		{
+			name:            "start links the chat",
+			text:            "/start K3QF7XWA",
+			users:           &userDirectoryMock{linkTelegramResult: linkedUser},
+			expectedReplies: []string{"Pronto, Fulano! Você vai receber as notificações por aqui."},
+			expectedLinks:   []linkCall{{chatID: 42, code: "K3QF7XWA"}},
+		},
+		{
+			name:            "start with an invalid code",
+			text:            "/start K3QF7XWA",
+			users:           &userDirectoryMock{linkTelegramError: user.ErrInvalidLinkingCode},
+			expectedReplies: []string{replyInvalidCode},
+			expectedLinks:   []linkCall{{chatID: 42, code: "K3QF7XWA"}},
+		},
+		{
+			name:            "start without a code",
+			text:            "/start",
+			users:           &userDirectoryMock{},
+			expectedReplies: []string{replyStartHelp},
+		},
+		{
+			name:              "forecast",
+			text:              "/previsao Rio de Janeiro, RJ",
+			users:             &userDirectoryMock{findByTelegramChatResult: linkedUser},
+			expectedReplies:   []string{},
+			expectedForecasts: []forecastCall{{userID: "USER-123", city: "Rio de Janeiro", state: "RJ", channel: "telegram"}},
+		},
+		{
+			name:              "forecast with the bot name",
+			text:              "/previsao@WeatherBot Niterói",
+			users:             &userDirectoryMock{findByTelegramChatResult: linkedUser},
+			expectedReplies:   []string{},
+			expectedForecasts: []forecastCall{{userID: "USER-123", city: "Niterói", channel: "telegram"}},
+		},
+		{
+			name:              "forecast for a city not found",
+			text:              "/previsao Atlantida",
+			users:             &userDirectoryMock{findByTelegramChatResult: linkedUser},
+			forecastError:     fmt.Errorf("%w: Atlantida", weather.ErrCityNotFound),
+			expectedReplies:   []string{"Não encontrei a cidade Atlantida."},
+			expectedForecasts: []forecastCall{{userID: "USER-123", city: "Atlantida", channel: "telegram"}},
+		},
+		{
+			name:              "forecast for an ambiguous city",
+			text:              "/previsao Bom Jesus",
+			users:             &userDirectoryMock{findByTelegramChatResult: linkedUser},
+			forecastError:     weather.ErrMultipleCities,
+			expectedReplies:   []string{"Há mais de uma cidade chamada Bom Jesus. Informe também o estado, como /previsao Bom Jesus, SP."},
+			expectedForecasts: []forecastCall{{userID: "USER-123", city: "Bom Jesus", channel: "telegram"}},
+		},
+		{
+			name:            "forecast from a chat not linked",
+			text:            "/previsao Rio de Janeiro",
+			users:           &userDirectoryMock{findByTelegramChatError: user.ErrUserNotFound},
+			expectedReplies: []string{replyNotLinked},
+		},
+		{
+			name:            "forecast with the chat notifications disabled",
+			text:            "/previsao Rio de Janeiro",
+			users:           &userDirectoryMock{findByTelegramChatResult: chatDisabledUser},
+			expectedReplies: []string{replyChatDisabled},
+		},
+		{
+			name:            "forecast with the user notifications disabled",
+			text:            "/previsao Rio de Janeiro",
+			users:           &userDirectoryMock{findByTelegramChatResult: userDisabledUser},
+			expectedReplies: []string{replyUserDisabled},
+		},
+		{
+			name:            "forecast without a city",
+			text:            "/previsao",
+			users:           &userDirectoryMock{},
+			expectedReplies: []string{replyForecastHelp},
+		},
+		{
+			name:            "stop disables the chat notifications",
+			text:            "/parar",
+			users:           &userDirectoryMock{setTelegramNotificationsResult: chatDisabledUser},
+			expectedReplies: []string{replyStopped},
+			expectedSets:    []setTelegramCall{{chatID: 42, enabled: false}},
+		},
+		{
+			name:            "stop from a chat not linked",
+			text:            "/parar",
+			users:           &userDirectoryMock{setTelegramNotificationsError: user.ErrUserNotFound},
+			expectedReplies: []string{replyNotLinked},
+			expectedSets:    []setTelegramCall{{chatID: 42, enabled: false}},
+		},
+		{
+			name:            "stop fails",
+			text:            "/parar",
+			users:           &userDirectoryMock{setTelegramNotificationsError: errors.New("runtime error")},
+			expectedReplies: []string{replyError},
+			expectedSets:    []setTelegramCall{{chatID: 42, enabled: false}},
+			expectedError:   true,
+		},
+		{
+			name:            "resume enables the chat notifications",
+			text:            "/voltar",
+			users:           &userDirectoryMock{setTelegramNotificationsResult: linkedUser},
+			expectedReplies: []string{replyResumed},
+			expectedSets:    []setTelegramCall{{chatID: 42, enabled: true}},
+		},
+		{
+			name:            "resume with the user notifications disabled",
+			text:            "/voltar",
+			users:           &userDirectoryMock{setTelegramNotificationsResult: userDisabledUser},
+			expectedReplies: []string{replyUserDisabled},
+			expectedSets:    []setTelegramCall{{chatID: 42, enabled: true}},
+		},
+		{
+			name:            "resume from a chat not linked",
+			text:            "/voltar",
+			users:           &userDirectoryMock{setTelegramNotificationsError: user.ErrUserNotFound},
+			expectedReplies: []string{replyNotLinked},
+			expectedSets:    []setTelegramCall{{chatID: 42, enabled: true}},
+		},
+		{
+			name:            "unknown command",
+			text:            "oi",
+			users:           &userDirectoryMock{},
+			expectedReplies: []string{replyHelp},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newBotAPI(t, 200, `{"ok":true,"result":{"message_id":2}}`)
+			defer api.server.Close()
+
+			forecasts := &forecastRequesterMock{notifyUserError: tt.forecastError}
+
+			bot := NewBot(api.client(), tt.users, forecasts, zap.NewNop())
+
+			err := bot.HandleUpdate(Update{
+				UpdateID: 1,
+				Message:  &Message{MessageID: 1, Chat: Chat{ID: 42}, Text: tt.text},
+			})
+
+			assert.Equal(t, tt.expectedError, err != nil)
+
+			replies := []string{}
+
+			for _, message := range api.messages {
+				assert.Equal(t, int64(42), message.ChatID)
+				replies = append(replies, message.Text)
+			}
+
+			assert.Equal(t, tt.expectedReplies, replies)
+			assert.Equal(t, tt.expectedLinks, tt.users.linkTelegramCalls)
+			assert.Equal(t, tt.expectedForecasts, forecasts.notifyUserCalls)
+			assert.Equal(t, tt.expectedSets, tt.users.setTelegramNotificationsCalls)
+		})
+	}
+}
+
+func TestBot_HandleUpdate_WithoutMessage(t *testing.T) {
+	api := newBotAPI(t, 200, `{"ok":true}`)
+	defer api.server.Close()
+
+	bot := NewBot(api.client(), &userDirectoryMock{}, &forecastRequesterMock{}, zap.NewNop())
+
+	err := bot.HandleUpdate(Update{UpdateID: 1})
+
+	assert.Nil(t, err)
+	assert.Empty(t, api.messages)
+}
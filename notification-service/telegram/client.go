@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+)
+
+const (
+	sendMessagePath = "/bot%s/sendMessage"
+)
+
+var (
+	ErrFailedToSend = errors.New("failed to send notification to telegram")
+	ErrBotBlocked   = errors.New("bot was blocked by the user")
+)
+
+// Client sends messages through the Telegram Bot API, as the bot the token
+// belongs to
+type Client struct {
+	Client *http.Client
+
+	sendMessageURL string
+}
+
+func NewClient(httpClient *http.Client, host, token string) *Client {
+	return &Client{
+		Client: httpClient,
+
+		sendMessageURL: host + fmt.Sprintf(sendMessagePath, token),
+	}
+}
+
+// Send sends the plain text of the notification to the chat linked to the
+// user. A user who blocked the bot is treated as opted out of the channel.
+func (c *Client) Send(recipient user.User, userNotification notification.Notification) error {
+	config := recipient.NotificationConfig.Telegram
+
+	if !config.Enabled || config.ChatID == 0 {
+		return notification.ErrUserOptOut
+	}
+
+	err := c.SendMessage(config.ChatID, userNotification.Body(notification.FormatText))
+
+	if errors.Is(err, ErrBotBlocked) {
+		return notification.ErrUserOptOut
+	}
+
+	return err
+}
+
+func (c *Client) SendMessage(chatID int64, text string) error {
+	body, err := json.Marshal(SendMessageRequestTO{ChatID: chatID, Text: text})
+
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	response, err := c.Client.Post(c.sendMessageURL, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, withoutURL(err))
+	}
+
+	defer response.Body.Close()
+
+	var parsedResponse ResponseTO
+
+	err = json.NewDecoder(response.Body).Decode(&parsedResponse)
+
+	if err != nil {
+		return fmt.Errorf("%w: unexpected response, status code: %d", ErrFailedToSend, response.StatusCode)
+	}
+
+	if parsedResponse.ErrorCode == http.StatusForbidden {
+		return fmt.Errorf("%w: %s", ErrBotBlocked, parsedResponse.Description)
+	}
+
+	if !parsedResponse.OK {
+		return fmt.Errorf("%w: %d %s", ErrFailedToSend, parsedResponse.ErrorCode, parsedResponse.Description)
+	}
+
+	return nil
+}
+
+// withoutURL drops the request URL from the errors of the HTTP client, which
+// would otherwise write the bot token to the logs
+func withoutURL(err error) error {
+	var urlErr *url.Error
+
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s request failed: %w", urlErr.Op, urlErr.Err)
+	}
+
+	return err
+}
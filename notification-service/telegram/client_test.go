@@ -0,0 +1,126 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/stretchr/testify/assert"
+)
+
+// botAPI is a stand-in for the Bot API, keeping the messages sent through it
+type botAPI struct {
+	server   *httptest.Server
+	messages []SendMessageRequestTO
+}
+
+func newBotAPI(t *testing.T, statusCode int, response string) *botAPI {
+	api := &botAPI{messages: []SendMessageRequestTO{}}
+
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/botTOKEN/sendMessage", r.URL.String())
+
+		var body SendMessageRequestTO
+
+		err := json.NewDecoder(r.Body).Decode(&body)
+
+		if err != nil {
+			assert.Fail(t, fmt.Sprintf("failed to decode body: %s", err.Error()))
+		}
+
+		api.messages = append(api.messages, body)
+
+		w.WriteHeader(statusCode)
+		w.Write([]byte(response))
+	}))
+
+	return api
+}
+
+func (a *botAPI) client() *Client {
+	return NewClient(a.server.Client(), a.server.URL, "TOKEN")
+}
+
+func TestClient_Send(t *testing.T) {
+	tests := []struct {
+		name             string
+		enabled          bool
+		apiResponseCode  int
+		apiResponse      string
+		expectedMessages []SendMessageRequestTO
+		expectedError    error
+	}{
+		{
+			name:             "success",
+			enabled:          true,
+			apiResponseCode:  200,
+			apiResponse:      `{"ok":true,"result":{"message_id":1}}`,
+			expectedMessages: []SendMessageRequestTO{{ChatID: 42, Text: "test notification content"}},
+			expectedError:    nil,
+		},
+		{
+			name:             "api error",
+			enabled:          true,
+			apiResponseCode:  400,
+			apiResponse:      `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`,
+			expectedMessages: []SendMessageRequestTO{{ChatID: 42, Text: "test notification content"}},
+			expectedError:    ErrFailedToSend,
+		},
+		{
+			name:             "bot blocked by the user",
+			enabled:          true,
+			apiResponseCode:  403,
+			apiResponse:      `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+			expectedMessages: []SendMessageRequestTO{{ChatID: 42, Text: "test notification content"}},
+			expectedError:    notification.ErrUserOptOut,
+		},
+		{
+			name:             "telegram notification disabled",
+			enabled:          false,
+			expectedMessages: []SendMessageRequestTO{},
+			expectedError:    notification.ErrUserOptOut,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newBotAPI(t, tt.apiResponseCode, tt.apiResponse)
+			defer api.server.Close()
+
+			recipient := user.User{
+				NotificationConfig: user.NotificationConfig{
+					Enabled: true,
+					Telegram: user.TelegramNotificationConfig{
+						Enabled: tt.enabled,
+						ChatID:  42,
+					},
+				},
+			}
+
+			err := api.client().Send(recipient, notification.Notification{
+				Content:  "test notification content",
+				Markdown: "*test notification content*",
+			})
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedMessages, api.messages)
+		})
+	}
+}
+
+func TestClient_SendMessage_HidesToken(t *testing.T) {
+	api := newBotAPI(t, 200, `{"ok":true}`)
+	api.server.Close()
+
+	client := NewClient(api.server.Client(), api.server.URL, "123456:SECRET-TOKEN")
+
+	err := client.SendMessage(42, "test notification content")
+
+	assert.ErrorIs(t, err, ErrFailedToSend)
+	assert.NotContains(t, err.Error(), "SECRET-TOKEN")
+}
@@ -0,0 +1,63 @@
+package telegram
+
+import "github.com/fgouvea/weather/notification-service/user"
+
+type linkCall struct {
+	chatID int64
+	code   string
+}
+
+type setTelegramCall struct {
+	chatID  int64
+	enabled bool
+}
+
+type userDirectoryMock struct {
+	linkTelegramCalls  []linkCall
+	linkTelegramResult user.User
+	linkTelegramError  error
+
+	findByTelegramChatCalls  []int64
+	findByTelegramChatResult user.User
+	findByTelegramChatError  error
+
+	setTelegramNotificationsCalls  []setTelegramCall
+	setTelegramNotificationsResult user.User
+	setTelegramNotificationsError  error
+}
+
+var _ UserDirectory = (*userDirectoryMock)(nil)
+
+func (m *userDirectoryMock) LinkTelegram(chatID int64, code string) (user.User, error) {
+	m.linkTelegramCalls = append(m.linkTelegramCalls, linkCall{chatID: chatID, code: code})
+	return m.linkTelegramResult, m.linkTelegramError
+}
+
+func (m *userDirectoryMock) FindByTelegramChat(chatID int64) (user.User, error) {
+	m.findByTelegramChatCalls = append(m.findByTelegramChatCalls, chatID)
+	return m.findByTelegramChatResult, m.findByTelegramChatError
+}
+
+func (m *userDirectoryMock) SetTelegramNotifications(chatID int64, enabled bool) (user.User, error) {
+	m.setTelegramNotificationsCalls = append(m.setTelegramNotificationsCalls, setTelegramCall{chatID: chatID, enabled: enabled})
+	return m.setTelegramNotificationsResult, m.setTelegramNotificationsError
+}
+
+type forecastCall struct {
+	userID  string
+	city    string
+	state   string
+	channel string
+}
+
+type forecastRequesterMock struct {
+	notifyUserCalls []forecastCall
+	notifyUserError error
+}
+
+var _ ForecastRequester = (*forecastRequesterMock)(nil)
+
+func (m *forecastRequesterMock) NotifyUser(userID, city, state, channel string) error {
+	m.notifyUserCalls = append(m.notifyUserCalls, forecastCall{userID: userID, city: city, state: state, channel: channel})
+	return m.notifyUserError
+}
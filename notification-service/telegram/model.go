@@ -0,0 +1,30 @@
+package telegram
+
+type SendMessageRequestTO struct {
+	ChatID int64  `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// ResponseTO is the envelope of every Bot API response
+type ResponseTO struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+// Update is what the Bot API posts to the webhook. Only messages are handled,
+// so the other kinds of updates leave Message empty.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
@@ -1,14 +1,23 @@
 package user
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 const (
-	getUserPath = "/user-service/user/%s"
+	getUserPath      = "/user-service/user/%s"
+	telegramChatPath = "/user-service/telegram/chats/%d"
+	telegramPath     = "/user-service/telegram/chats/%d/notifications"
+	pushPath         = "/user-service/user/%s/push/%s"
+	webhookPath      = "/user-service/internal/user/%s/webhook/secret"
+	pushKeysPath     = "/user-service/internal/user/%s/push"
+
+	invalidLinkingCodeProblem = "urn:weather:problem:invalid-linking-code"
 )
 
 var (
@@ -16,17 +25,31 @@ var (
 	ErrReadingResponse = errors.New("error reading api response")
 )
 
+// Client calls the public routes of user-service and, with the token shared
+// between the services, the internal ones, served on their own host
 type Client struct {
 	Client *http.Client
+	Token  string
 
-	getUserURL string
+	getUserURL      string
+	telegramChatURL string
+	telegramURL     string
+	pushURL         string
+	webhookURL      string
+	pushKeysURL     string
 }
 
-func NewClient(httpClient *http.Client, basePath string) *Client {
+func NewClient(httpClient *http.Client, basePath, internalBasePath, token string) *Client {
 	return &Client{
 		Client: httpClient,
+		Token:  token,
 
-		getUserURL: fmt.Sprintf("%s%s", basePath, getUserPath),
+		getUserURL:      fmt.Sprintf("%s%s", basePath, getUserPath),
+		telegramChatURL: fmt.Sprintf("%s%s", basePath, telegramChatPath),
+		telegramURL:     fmt.Sprintf("%s%s", basePath, telegramPath),
+		pushURL:         fmt.Sprintf("%s%s", basePath, pushPath),
+		webhookURL:      fmt.Sprintf("%s%s", internalBasePath, webhookPath),
+		pushKeysURL:     fmt.Sprintf("%s%s", internalBasePath, pushKeysPath),
 	}
 }
 
@@ -37,6 +60,192 @@ func (c *Client) FindUser(id string) (User, error) {
 		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
 	}
 
+	return readUser(response)
+}
+
+// FindByTelegramChat finds the user linked to a Telegram chat
+func (c *Client) FindByTelegramChat(chatID int64) (User, error) {
+	response, err := c.Client.Get(fmt.Sprintf(c.telegramChatURL, chatID))
+
+	if err != nil {
+		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	return readUser(response)
+}
+
+// LinkTelegram links the chat to the user the linking code was given to
+func (c *Client) LinkTelegram(chatID int64, code string) (User, error) {
+	body, err := json.Marshal(LinkTelegramRequestTO{Code: code})
+
+	if err != nil {
+		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf(c.telegramChatURL, chatID), bytes.NewReader(body))
+
+	if err != nil {
+		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.Client.Do(request)
+
+	if err != nil {
+		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	if response.StatusCode == http.StatusBadRequest {
+		var problem ProblemTO
+		json.NewDecoder(response.Body).Decode(&problem)
+
+		if problem.Type == invalidLinkingCodeProblem {
+			return User{}, ErrInvalidLinkingCode
+		}
+	}
+
+	return readUser(response)
+}
+
+// SetTelegramNotifications turns the notifications sent to the chat on or
+// off, leaving the other channels of the user linked to it as they are
+func (c *Client) SetTelegramNotifications(chatID int64, enabled bool) (User, error) {
+	body, err := json.Marshal(SetTelegramNotificationsRequestTO{Enabled: enabled})
+
+	if err != nil {
+		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf(c.telegramURL, chatID), bytes.NewReader(body))
+
+	if err != nil {
+		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.Client.Do(request)
+
+	if err != nil {
+		return User{}, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	return readUser(response)
+}
+
+// RemovePushSubscription removes the subscription of the device, as long as
+// it still has the endpoint. A subscription already removed is not an error.
+func (c *Client) RemovePushSubscription(id, device, endpoint string) error {
+	requestURL := fmt.Sprintf(c.pushURL, url.PathEscape(id), url.PathEscape(device)) + "?endpoint=" + url.QueryEscape(endpoint)
+
+	request, err := http.NewRequest(http.MethodDelete, requestURL, nil)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	response, err := c.Client.Do(request)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("%w: unexpected status code: %d", ErrRequestAPI, response.StatusCode)
+	}
+
+	return nil
+}
+
+// FindWebhookSecret reads the secret the webhook notifications of the user
+// are signed with, which the user itself leaves out
+func (c *Client) FindWebhookSecret(id string) (string, error) {
+	response, err := c.getInternal(fmt.Sprintf(c.webhookURL, url.PathEscape(id)))
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return "", ErrUserNotFound
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: unexpected status code: %d", ErrRequestAPI, response.StatusCode)
+	}
+
+	var parsedResponse WebhookSecretTO
+
+	err = json.NewDecoder(response.Body).Decode(&parsedResponse)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrReadingResponse, err)
+	}
+
+	return parsedResponse.Secret, nil
+}
+
+// FindPushSubscriptions reads the push subscriptions of the user with the
+// keys the payload is encrypted with, which the user itself leaves out
+func (c *Client) FindPushSubscriptions(id string) ([]PushSubscription, error) {
+	response, err := c.getInternal(fmt.Sprintf(c.pushKeysURL, url.PathEscape(id)))
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrUserNotFound
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status code: %d", ErrRequestAPI, response.StatusCode)
+	}
+
+	var parsedResponse PushSubscriptionsTO
+
+	err = json.NewDecoder(response.Body).Decode(&parsedResponse)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingResponse, err)
+	}
+
+	var subscriptions []PushSubscription
+
+	for _, subscription := range parsedResponse.Subscriptions {
+		subscriptions = append(subscriptions, PushSubscription{
+			Device:   subscription.Device,
+			Endpoint: subscription.Endpoint,
+			P256dh:   subscription.Keys.P256dh,
+			Auth:     subscription.Keys.Auth,
+		})
+	}
+
+	return subscriptions, nil
+}
+
+func (c *Client) getInternal(requestURL string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, requestURL, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+c.Token)
+
+	return c.Client.Do(request)
+}
+
+func readUser(response *http.Response) (User, error) {
+	defer response.Body.Close()
+
 	if response.StatusCode == http.StatusNotFound {
 		return User{}, ErrUserNotFound
 	}
@@ -47,7 +256,7 @@ func (c *Client) FindUser(id string) (User, error) {
 
 	var parsedResponse UserTO
 
-	err = json.NewDecoder(response.Body).Decode(&parsedResponse)
+	err := json.NewDecoder(response.Body).Decode(&parsedResponse)
 
 	if err != nil {
 		return User{}, fmt.Errorf("%w: %w", ErrReadingResponse, err)
@@ -62,6 +271,39 @@ func (c *Client) FindUser(id string) (User, error) {
 				Enabled: parsedResponse.NotificationConfig.Web.Enabled,
 				ID:      parsedResponse.NotificationConfig.Web.ID,
 			},
+			Email: EmailNotificationConfig{
+				Enabled: parsedResponse.NotificationConfig.Email.Enabled,
+				Address: parsedResponse.NotificationConfig.Email.Address,
+			},
+			SMS: SMSNotificationConfig{
+				Enabled: parsedResponse.NotificationConfig.SMS.Enabled,
+				Phone:   parsedResponse.NotificationConfig.SMS.Phone,
+			},
+			Telegram: TelegramNotificationConfig{
+				Enabled: parsedResponse.NotificationConfig.Telegram.Enabled,
+				ChatID:  parsedResponse.NotificationConfig.Telegram.ChatID,
+			},
+			Webhook: WebhookNotificationConfig{
+				Enabled: parsedResponse.NotificationConfig.Webhook.Enabled,
+				URL:     parsedResponse.NotificationConfig.Webhook.URL,
+			},
+			Push: readPushNotificationConfig(parsedResponse.NotificationConfig.Push),
 		},
 	}, nil
 }
+
+func readPushNotificationConfig(config PushNotificationConfigTO) PushNotificationConfig {
+	var subscriptions []PushSubscription
+
+	for _, subscription := range config.Subscriptions {
+		subscriptions = append(subscriptions, PushSubscription{
+			Device:   subscription.Device,
+			Endpoint: subscription.Endpoint,
+		})
+	}
+
+	return PushNotificationConfig{
+		Enabled:       config.Enabled,
+		Subscriptions: subscriptions,
+	}
+}
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -25,6 +26,44 @@ func TestClient_Find(t *testing.T) {
 			expectedResult: User{
 				ID:   "USER-123",
 				Name: "Fulano",
+				NotificationConfig: NotificationConfig{
+					Enabled: true,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "success with every channel",
+			apiResponseCode: 200,
+			apiResponse:     `{"id":"USER-123","name":"Fulano","notification":{"enabled":true,"web":{"enabled":false,"id":""},"email":{"enabled":true,"address":"fulano@example.com"},"sms":{"enabled":true,"phone":"+5521999990000"},"telegram":{"enabled":true,"chatId":42},"webhook":{"enabled":true,"url":"https://partner.example.com"},"push":{"enabled":true,"subscriptions":[{"device":"laptop","endpoint":"https://push.example.com/laptop"}]}}}`,
+			expectedResult: User{
+				ID:   "USER-123",
+				Name: "Fulano",
+				NotificationConfig: NotificationConfig{
+					Enabled: true,
+					Email: EmailNotificationConfig{
+						Enabled: true,
+						Address: "fulano@example.com",
+					},
+					SMS: SMSNotificationConfig{
+						Enabled: true,
+						Phone:   "+5521999990000",
+					},
+					Telegram: TelegramNotificationConfig{
+						Enabled: true,
+						ChatID:  42,
+					},
+					Webhook: WebhookNotificationConfig{
+						Enabled: true,
+						URL:     "https://partner.example.com",
+					},
+					Push: PushNotificationConfig{
+						Enabled: true,
+						Subscriptions: []PushSubscription{
+							{Device: "laptop", Endpoint: "https://push.example.com/laptop"},
+						},
+					},
+				},
 			},
 			expectedError: nil,
 		},
@@ -61,7 +100,7 @@ func TestClient_Find(t *testing.T) {
 
 			defer server.Close()
 
-			client := NewClient(server.Client(), server.URL)
+			client := NewClient(server.Client(), server.URL, server.URL, "TOKEN")
 
 			result, err := client.FindUser("USER-123")
 
@@ -70,3 +109,292 @@ func TestClient_Find(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_LinkTelegram(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		apiResponse     string
+		expectedResult  User
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			apiResponse:     `{"id":"USER-123","name":"Fulano","notification":{"enabled":true,"telegram":{"enabled":true,"chatId":42}}}`,
+			expectedResult: User{
+				ID:   "USER-123",
+				Name: "Fulano",
+				NotificationConfig: NotificationConfig{
+					Enabled:  true,
+					Telegram: TelegramNotificationConfig{Enabled: true, ChatID: 42},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "invalid linking code",
+			apiResponseCode: 400,
+			apiResponse:     `{"type":"urn:weather:problem:invalid-linking-code","title":"Invalid Telegram linking code","status":400}`,
+			expectedResult:  User{},
+			expectedError:   ErrInvalidLinkingCode,
+		},
+		{
+			name:            "invalid request",
+			apiResponseCode: 400,
+			apiResponse:     `{"type":"urn:weather:problem:invalid-request","title":"Invalid request","status":400}`,
+			expectedResult:  User{},
+			expectedError:   ErrRequestAPI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/user-service/telegram/chats/42", r.URL.String())
+
+				var body LinkTelegramRequestTO
+				json.NewDecoder(r.Body).Decode(&body)
+				assert.Equal(t, "K3QF7XWA", body.Code)
+
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, "TOKEN")
+
+			result, err := client.LinkTelegram(42, "K3QF7XWA")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_SetTelegramNotifications(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		apiResponse     string
+		expectedResult  User
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			apiResponse:     `{"id":"USER-123","name":"Fulano","notification":{"enabled":true,"telegram":{"enabled":false,"chatId":42}}}`,
+			expectedResult: User{
+				ID:   "USER-123",
+				Name: "Fulano",
+				NotificationConfig: NotificationConfig{
+					Enabled:  true,
+					Telegram: TelegramNotificationConfig{Enabled: false, ChatID: 42},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "chat not linked",
+			apiResponseCode: 404,
+			apiResponse:     `{"type":"urn:weather:problem:user-not-found","title":"User not found","status":404}`,
+			expectedResult:  User{},
+			expectedError:   ErrUserNotFound,
+		},
+		{
+			name:            "error calling api",
+			apiResponseCode: 500,
+			apiResponse:     `{}`,
+			expectedResult:  User{},
+			expectedError:   ErrRequestAPI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/user-service/telegram/chats/42/notifications", r.URL.String())
+
+				var body SetTelegramNotificationsRequestTO
+				json.NewDecoder(r.Body).Decode(&body)
+				assert.False(t, body.Enabled)
+
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, "TOKEN")
+
+			result, err := client.SetTelegramNotifications(42, false)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_RemovePushSubscription(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			expectedError:   nil,
+		},
+		{
+			name:            "subscription already removed",
+			apiResponseCode: 404,
+			expectedError:   nil,
+		},
+		{
+			name:            "error calling api",
+			apiResponseCode: 500,
+			expectedError:   ErrRequestAPI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				assert.Equal(t, "/user-service/user/USER-123/push/my%20laptop", r.URL.EscapedPath())
+				assert.Equal(t, "https://push.example.com/send/abc?x=1", r.URL.Query().Get("endpoint"))
+				w.WriteHeader(tt.apiResponseCode)
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, "TOKEN")
+
+			err := client.RemovePushSubscription("USER-123", "my laptop", "https://push.example.com/send/abc?x=1")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+		})
+	}
+}
+
+func TestClient_FindWebhookSecret(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		apiResponse     string
+		expectedResult  string
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			apiResponse:     `{"secret":"s3cr3t"}`,
+			expectedResult:  "s3cr3t",
+			expectedError:   nil,
+		},
+		{
+			name:            "user not found",
+			apiResponseCode: 404,
+			expectedResult:  "",
+			expectedError:   ErrUserNotFound,
+		},
+		{
+			name:            "error calling api",
+			apiResponseCode: 500,
+			expectedResult:  "",
+			expectedError:   ErrRequestAPI,
+		},
+		{
+			name:            "error reading response",
+			apiResponseCode: 200,
+			apiResponse:     `{"secret":`,
+			expectedResult:  "",
+			expectedError:   ErrReadingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/user-service/internal/user/USER-123/webhook/secret", r.URL.String())
+				assert.Equal(t, "Bearer TOKEN", r.Header.Get("Authorization"))
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, "TOKEN")
+
+			result, err := client.FindWebhookSecret("USER-123")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_FindPushSubscriptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		apiResponse     string
+		expectedResult  []PushSubscription
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			apiResponse:     `{"subscriptions":[{"device":"laptop","endpoint":"https://push.example.com/laptop","keys":{"p256dh":"BCVx","auth":"BTBZ"}}]}`,
+			expectedResult: []PushSubscription{
+				{Device: "laptop", Endpoint: "https://push.example.com/laptop", P256dh: "BCVx", Auth: "BTBZ"},
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "user not found",
+			apiResponseCode: 404,
+			expectedResult:  nil,
+			expectedError:   ErrUserNotFound,
+		},
+		{
+			name:            "error calling api",
+			apiResponseCode: 500,
+			expectedResult:  nil,
+			expectedError:   ErrRequestAPI,
+		},
+		{
+			name:            "error reading response",
+			apiResponseCode: 200,
+			apiResponse:     `{"subscriptions":`,
+			expectedResult:  nil,
+			expectedError:   ErrReadingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/user-service/internal/user/USER-123/push", r.URL.String())
+				assert.Equal(t, "Bearer TOKEN", r.Header.Get("Authorization"))
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, "TOKEN")
+
+			result, err := client.FindPushSubscriptions("USER-123")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
@@ -7,11 +7,80 @@ type UserTO struct {
 }
 
 type NotificationConfigTO struct {
-	Enabled bool                    `json:"enabled"`
-	Web     WebNotificationConfigTO `json:"web"`
+	Enabled  bool                         `json:"enabled"`
+	Web      WebNotificationConfigTO      `json:"web"`
+	Email    EmailNotificationConfigTO    `json:"email"`
+	SMS      SMSNotificationConfigTO      `json:"sms"`
+	Telegram TelegramNotificationConfigTO `json:"telegram"`
+	Webhook  WebhookNotificationConfigTO  `json:"webhook"`
+	Push     PushNotificationConfigTO     `json:"push"`
 }
 
 type WebNotificationConfigTO struct {
 	Enabled bool   `json:"enabled"`
 	ID      string `json:"id"`
 }
+
+type EmailNotificationConfigTO struct {
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address"`
+}
+
+type SMSNotificationConfigTO struct {
+	Enabled bool   `json:"enabled"`
+	Phone   string `json:"phone"`
+}
+
+type TelegramNotificationConfigTO struct {
+	Enabled bool  `json:"enabled"`
+	ChatID  int64 `json:"chatId"`
+}
+
+type WebhookNotificationConfigTO struct {
+	Enabled bool   `json:"enabled"`
+	URL     string `json:"url"`
+}
+
+type WebhookSecretTO struct {
+	Secret string `json:"secret"`
+}
+
+type PushNotificationConfigTO struct {
+	Enabled       bool                 `json:"enabled"`
+	Subscriptions []PushSubscriptionTO `json:"subscriptions"`
+}
+
+type PushSubscriptionTO struct {
+	Device   string `json:"device"`
+	Endpoint string `json:"endpoint"`
+}
+
+// PushSubscriptionsTO has the keys of the subscriptions, which only the
+// internal route of user-service serves
+type PushSubscriptionsTO struct {
+	Subscriptions []PushSubscriptionKeysTO `json:"subscriptions"`
+}
+
+type PushSubscriptionKeysTO struct {
+	Device   string     `json:"device"`
+	Endpoint string     `json:"endpoint"`
+	Keys     PushKeysTO `json:"keys"`
+}
+
+type PushKeysTO struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+type LinkTelegramRequestTO struct {
+	Code string `json:"code"`
+}
+
+type SetTelegramNotificationsRequestTO struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ProblemTO is the body of the errors of user-service
+type ProblemTO struct {
+	Type string `json:"type"`
+}
@@ -2,7 +2,10 @@ package user
 
 import "errors"
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidLinkingCode = errors.New("invalid telegram linking code")
+)
 
 type User struct {
 	ID                 string
@@ -11,11 +14,53 @@ type User struct {
 }
 
 type NotificationConfig struct {
-	Enabled bool
-	Web     WebNotificationConfig
+	Enabled  bool
+	Web      WebNotificationConfig
+	Email    EmailNotificationConfig
+	SMS      SMSNotificationConfig
+	Telegram TelegramNotificationConfig
+	Webhook  WebhookNotificationConfig
+	Push     PushNotificationConfig
 }
 
 type WebNotificationConfig struct {
 	Enabled bool
 	ID      string
 }
+
+type EmailNotificationConfig struct {
+	Enabled bool
+	Address string
+}
+
+type SMSNotificationConfig struct {
+	Enabled bool
+	Phone   string
+}
+
+type TelegramNotificationConfig struct {
+	Enabled bool
+	ChatID  int64
+}
+
+// WebhookNotificationConfig leaves the secret out, which is read on its own
+// by FindWebhookSecret
+type WebhookNotificationConfig struct {
+	Enabled bool
+	URL     string
+}
+
+type PushNotificationConfig struct {
+	Enabled       bool
+	Subscriptions []PushSubscription
+}
+
+// PushSubscription is a browser of the user subscribed to pushes. Only
+// FindPushSubscriptions fills the keys the payload is encrypted with, in
+// base64url.
+type PushSubscription struct {
+	Device   string
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
@@ -0,0 +1,76 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	notifyPath = "/weather-service/notify"
+
+	cityNotFoundProblem   = "urn:weather:problem:city-not-found"
+	multipleCitiesProblem = "urn:weather:problem:multiple-cities"
+)
+
+var (
+	ErrRequestAPI     = errors.New("error requesting forecast from api")
+	ErrCityNotFound   = errors.New("city not found")
+	ErrMultipleCities = errors.New("multiple cities found with name")
+)
+
+// Client asks weather-service for forecasts, which arrive as notifications
+// like the scheduled ones.
+type Client struct {
+	Client *http.Client
+
+	notifyURL string
+}
+
+func NewClient(httpClient *http.Client, host string) *Client {
+	return &Client{
+		Client: httpClient,
+
+		notifyURL: fmt.Sprintf("%s%s", host, notifyPath),
+	}
+}
+
+// NotifyUser sends the forecast of the city to the user through the channel
+func (c *Client) NotifyUser(userID, city, state, channel string) error {
+	body, err := json.Marshal(NotifyRequestTO{
+		UserID:  userID,
+		City:    city,
+		State:   state,
+		Channel: channel,
+	})
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	response, err := c.Client.Post(c.notifyURL, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequestAPI, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var problem ProblemTO
+	json.NewDecoder(response.Body).Decode(&problem)
+
+	switch problem.Type {
+	case cityNotFoundProblem:
+		return fmt.Errorf("%w: %s", ErrCityNotFound, city)
+	case multipleCitiesProblem:
+		return fmt.Errorf("%w: %s", ErrMultipleCities, city)
+	default:
+		return fmt.Errorf("%w: unexpected status code: %d", ErrRequestAPI, response.StatusCode)
+	}
+}
@@ -0,0 +1,74 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_NotifyUser(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		apiResponse     string
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			expectedError:   nil,
+		},
+		{
+			name:            "city not found",
+			apiResponseCode: 404,
+			apiResponse:     `{"type":"urn:weather:problem:city-not-found","title":"City not found","status":404}`,
+			expectedError:   ErrCityNotFound,
+		},
+		{
+			name:            "multiple cities",
+			apiResponseCode: 409,
+			apiResponse:     `{"type":"urn:weather:problem:multiple-cities","title":"Ambiguous city, a state or city ID is needed","status":409}`,
+			expectedError:   ErrMultipleCities,
+		},
+		{
+			name:            "error calling api",
+			apiResponseCode: 500,
+			apiResponse:     `{"type":"about:blank","title":"Internal Server Error","status":500}`,
+			expectedError:   ErrRequestAPI,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/weather-service/notify", r.URL.String())
+
+				var body NotifyRequestTO
+
+				err := json.NewDecoder(r.Body).Decode(&body)
+
+				if err != nil {
+					assert.Fail(t, fmt.Sprintf("failed to decode body: %s", err.Error()))
+				}
+
+				assert.Equal(t, NotifyRequestTO{UserID: "USER-123", City: "Rio de Janeiro", State: "RJ", Channel: "telegram"}, body)
+
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			err := client.NotifyUser("USER-123", "Rio de Janeiro", "RJ", "telegram")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+		})
+	}
+}
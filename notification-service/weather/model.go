@@ -0,0 +1,14 @@
+package weather
+
+type NotifyRequestTO struct {
+	UserID  string `json:"userId"`
+	City    string `json:"city"`
+	State   string `json:"state,omitempty"`
+	Channel string `json:"channel"`
+}
+
+// ProblemTO is the body of the errors of weather-service
+type ProblemTO struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
@@ -19,9 +19,13 @@ var (
 	ErrFailedToSend = errors.New("failed to send notification to web api")
 )
 
+// ExternalNotification carries the plain text, for the API to show as is,
+// and the HTML and payload for the pages that render them.
 type ExternalNotification struct {
-	ID      string `json:"id"`
-	Content string `json:"content"`
+	ID      string          `json:"id"`
+	Content string          `json:"content"`
+	HTML    string          `json:"html,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
 }
 
 type Client struct {
@@ -38,17 +42,19 @@ func NewClient(httpClient *http.Client, host string) *Client {
 	}
 }
 
-func (c *Client) Send(recipient user.User, content string) error {
+func (c *Client) Send(recipient user.User, userNotification notification.Notification) error {
 	if !recipient.NotificationConfig.Web.Enabled {
 		return notification.ErrUserOptOut
 	}
 
-	notification := ExternalNotification{
+	external := ExternalNotification{
 		ID:      recipient.NotificationConfig.Web.ID,
-		Content: content,
+		Content: userNotification.Body(notification.FormatText),
+		HTML:    userNotification.Body(notification.FormatHTML),
+		Payload: userNotification.Payload,
 	}
 
-	body, err := json.Marshal(notification)
+	body, err := json.Marshal(external)
 
 	if err != nil {
 		return fmt.Errorf("failed to encode notification: %w", err)
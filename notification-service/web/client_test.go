@@ -54,6 +54,8 @@ func TestClient_Send(t *testing.T) {
 
 				assert.Equal(t, "USER-123", body.ID)
 				assert.Equal(t, "test notification content", body.Content)
+				assert.Equal(t, "<p>test notification content</p>", body.HTML)
+				assert.JSONEq(t, `{"type": "forecast"}`, string(body.Payload))
 
 				w.WriteHeader(tt.apiResponseCode)
 			}))
@@ -72,7 +74,11 @@ func TestClient_Send(t *testing.T) {
 				},
 			}
 
-			err := client.Send(recipient, "test notification content")
+			err := client.Send(recipient, notification.Notification{
+				Content: "test notification content",
+				HTML:    "<p>test notification content</p>",
+				Payload: json.RawMessage(`{"type": "forecast"}`),
+			})
 
 			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
 		})
@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/google/uuid"
+)
+
+const (
+	TimestampHeader      = "X-Weather-Timestamp"
+	SignatureHeader      = "X-Weather-Signature"
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrFailedToSend = errors.New("failed to send notification to webhook")
+)
+
+// SecretFinder reads the secret of the webhook of a user, which the user
+// found for the notification leaves out
+type SecretFinder interface {
+	FindWebhookSecret(userID string) (string, error)
+}
+
+// Envelope is the body posted to the webhook. ID is also sent as the
+// idempotency key, so the receiver can drop the deliveries retried after a
+// failure.
+type Envelope struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"userId"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Content   string          `json:"content"`
+	Markdown  string          `json:"markdown,omitempty"`
+	HTML      string          `json:"html,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+type Client struct {
+	Client  *http.Client
+	Secrets SecretFinder
+	Now     func() time.Time
+}
+
+func NewClient(httpClient *http.Client, secrets SecretFinder) *Client {
+	return &Client{
+		Client:  httpClient,
+		Secrets: secrets,
+		Now:     time.Now,
+	}
+}
+
+// Send posts the notification to the webhook of the user, signed with its
+// secret. Any 2xx response means it was delivered, while client errors other
+// than timeouts and rate limits are not retried.
+func (c *Client) Send(recipient user.User, userNotification notification.Notification) error {
+	config := recipient.NotificationConfig.Webhook
+
+	if !config.Enabled || config.URL == "" {
+		return notification.ErrUserOptOut
+	}
+
+	secret, err := c.Secrets.FindWebhookSecret(recipient.ID)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, err)
+	}
+
+	now := c.Now()
+
+	envelope := Envelope{
+		ID:        idempotencyKey(userNotification),
+		UserID:    recipient.ID,
+		CreatedAt: now.UTC(),
+		Content:   userNotification.Content,
+		Markdown:  userNotification.Markdown,
+		HTML:      userNotification.HTML,
+		Payload:   userNotification.Payload,
+	}
+
+	body, err := json.Marshal(envelope)
+
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	request, err := http.NewRequest(http.MethodPost, config.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	request.Header.Set(IdempotencyKeyHeader, envelope.ID)
+
+	response, err := c.Client.Do(request)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSend, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = fmt.Errorf("%w: unexpected status code: %d", ErrFailedToSend, response.StatusCode)
+
+		if isPermanent(response.StatusCode) {
+			return fmt.Errorf("%w: %w", notification.ErrUndeliverable, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// isPermanent tells the client errors that sending again would not fix, which
+// are all but timeouts and rate limits
+func isPermanent(statusCode int) bool {
+	return statusCode >= 400 && statusCode < 500 && statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests
+}
+
+// Sign is the signature header of a body sent at the timestamp: the
+// HMAC-SHA256 of "<timestamp>.<body>" with the secret of the user, in hex
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// idempotencyKey is the ID weather-service gave the notification, which it
+// keeps when requeued after a failed delivery. Notifications published
+// without one get a new key on each delivery.
+func idempotencyKey(userNotification notification.Notification) string {
+	if userNotification.ID != "" {
+		return userNotification.ID
+	}
+
+	return uuid.New().String()
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fgouvea/weather/notification-service/notification"
+	"github.com/fgouvea/weather/notification-service/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Send(t *testing.T) {
+	tests := []struct {
+		name            string
+		enabled         bool
+		withoutURL      bool
+		apiResponseCode int
+		secretError     error
+		expectedError   error
+		permanent       bool
+	}{
+		{
+			name:            "success",
+			enabled:         true,
+			apiResponseCode: 200,
+			expectedError:   nil,
+		},
+		{
+			name:            "success with no content",
+			enabled:         true,
+			apiResponseCode: 204,
+			expectedError:   nil,
+		},
+		{
+			name:            "webhook error",
+			enabled:         true,
+			apiResponseCode: 500,
+			expectedError:   ErrFailedToSend,
+		},
+		{
+			name:            "webhook rejects the notification",
+			enabled:         true,
+			apiResponseCode: 410,
+			expectedError:   ErrFailedToSend,
+			permanent:       true,
+		},
+		{
+			name:            "webhook rate limited",
+			enabled:         true,
+			apiResponseCode: 429,
+			expectedError:   ErrFailedToSend,
+			permanent:       false,
+		},
+		{
+			name:            "webhook timed out",
+			enabled:         true,
+			apiResponseCode: 408,
+			expectedError:   ErrFailedToSend,
+			permanent:       false,
+		},
+		{
+			name:            "not modified is not a delivery",
+			enabled:         true,
+			apiResponseCode: 304,
+			expectedError:   ErrFailedToSend,
+		},
+		{
+			name:          "error finding secret",
+			enabled:       true,
+			secretError:   user.ErrUserNotFound,
+			expectedError: ErrFailedToSend,
+		},
+		{
+			name:          "webhook disabled",
+			enabled:       false,
+			expectedError: notification.ErrUserOptOut,
+		},
+		{
+			name:          "webhook without url",
+			enabled:       true,
+			withoutURL:    true,
+			expectedError: notification.ErrUserOptOut,
+		},
+	}
+
+	now := time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/hooks/weather", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "1741609800", r.Header.Get(TimestampHeader))
+
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, Sign("s3cr3t", "1741609800", body), r.Header.Get(SignatureHeader))
+
+				var envelope Envelope
+
+				err := json.Unmarshal(body, &envelope)
+
+				if err != nil {
+					assert.Fail(t, fmt.Sprintf("failed to decode body: %s", err.Error()))
+				}
+
+				assert.Equal(t, "NOTIFICATION-1", r.Header.Get(IdempotencyKeyHeader))
+				assert.Equal(t, "NOTIFICATION-1", envelope.ID)
+				assert.Equal(t, "USER-123", envelope.UserID)
+				assert.Equal(t, now, envelope.CreatedAt)
+				assert.Equal(t, "test notification content", envelope.Content)
+				assert.Equal(t, "<p>test notification content</p>", envelope.HTML)
+				assert.JSONEq(t, `{"type": "forecast"}`, string(envelope.Payload))
+
+				w.WriteHeader(tt.apiResponseCode)
+			}))
+
+			defer server.Close()
+
+			secrets := &secretFinderMock{findResult: "s3cr3t", findError: tt.secretError}
+
+			client := NewClient(server.Client(), secrets)
+			client.Now = func() time.Time { return now }
+
+			url := server.URL + "/hooks/weather"
+
+			if tt.withoutURL {
+				url = ""
+			}
+
+			recipient := user.User{
+				ID: "USER-123",
+				NotificationConfig: user.NotificationConfig{
+					Enabled: true,
+					Webhook: user.WebhookNotificationConfig{
+						Enabled: tt.enabled,
+						URL:     url,
+					},
+				},
+			}
+
+			err := client.Send(recipient, notification.Notification{
+				ID:      "NOTIFICATION-1",
+				UserID:  "USER-123",
+				Content: "test notification content",
+				HTML:    "<p>test notification content</p>",
+				Payload: json.RawMessage(`{"type": "forecast"}`),
+			})
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.permanent, errors.Is(err, notification.ErrUndeliverable))
+
+			if errors.Is(err, notification.ErrUserOptOut) || tt.secretError != nil {
+				assert.Equal(t, 0, requests)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		expected  string
+	}{
+		{
+			name:      "signs timestamp and body",
+			secret:    "key",
+			timestamp: "1741609800",
+			body:      `{"id":"1"}`,
+			expected:  "sha256=879c0c0dbfa8cd2d88a90c09582960552873abfc270c0d540c1ba4cc490bd8f6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Sign(tt.secret, tt.timestamp, []byte(tt.body))
+
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	forecast := notification.Notification{
+		ID:      "NOTIFICATION-1",
+		Content: "Manhã de sol",
+	}
+
+	assert.Equal(t, "NOTIFICATION-1", idempotencyKey(forecast), "retries keep the key")
+	assert.NotEqual(t, idempotencyKey(notification.Notification{ID: "NOTIFICATION-2", Content: "Manhã de sol"}), idempotencyKey(forecast), "notifications with the same content have their own keys")
+	assert.NotEqual(t, idempotencyKey(notification.Notification{Content: "Manhã de sol"}), idempotencyKey(notification.Notification{Content: "Manhã de sol"}), "notifications without an ID get a new key")
+}
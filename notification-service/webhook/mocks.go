@@ -0,0 +1,14 @@
+package webhook
+
+type secretFinderMock struct {
+	findCalls  []string
+	findResult string
+	findError  error
+}
+
+var _ SecretFinder = (*secretFinderMock)(nil)
+
+func (m *secretFinderMock) FindWebhookSecret(userID string) (string, error) {
+	m.findCalls = append(m.findCalls, userID)
+	return m.findResult, m.findError
+}
@@ -0,0 +1,5 @@
+module github.com/fgouvea/weather/sms-provider-mock
+
+go 1.23.5
+
+require github.com/go-chi/chi/v5 v5.2.1
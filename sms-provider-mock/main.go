@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AppConfig struct {
+	Port string
+}
+
+func readConfigFromEnv() AppConfig {
+	return AppConfig{
+		Port: fmt.Sprintf(":%s", readFromEnv("PORT", "8084")),
+	}
+}
+
+type Message struct {
+	To       string `json:"to"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding"`
+	Segments int    `json:"segments"`
+}
+
+func main() {
+	config := readConfigFromEnv()
+
+	r := chi.NewRouter()
+
+	r.Route("/sms/send", func(r chi.Router) {
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			var body Message
+			err := json.NewDecoder(r.Body).Decode(&body)
+
+			if err != nil {
+				fmt.Println("ERROR READING SMS")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			fmt.Println("SMS RECEIVED")
+			fmt.Printf("To: %s (%s, %d segments)\n", body.To, body.Encoding, body.Segments)
+			fmt.Println(body.Text)
+
+			w.WriteHeader(http.StatusAccepted)
+		})
+	})
+
+	fmt.Printf("Listenning on %s...\n", config.Port)
+	http.ListenAndServe(config.Port, r)
+}
+
+func readFromEnv(env, def string) string {
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+
+	return def
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// InternalAuth lets through only the requests of the other services, which
+// send the shared token as a bearer token. Without a token every request is
+// refused.
+func InternalAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+			if token == "" || !found || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				writeProblem(w, Problem{
+					Type:   "about:blank",
+					Title:  http.StatusText(http.StatusUnauthorized),
+					Status: http.StatusUnauthorized,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -5,17 +5,73 @@ import "github.com/fgouvea/weather/user-service/user"
 type CreateUserRequestTO struct {
 	Name              string `json:"name"`
 	WebNotificationID string `json:"webNotificationId"`
+	Locale            string `json:"locale"`
+}
+
+type SetLocaleRequestTO struct {
+	Locale string `json:"locale"`
+}
+
+type SetForecastStyleRequestTO struct {
+	ForecastStyle string `json:"forecastStyle"`
+}
+
+type SetEmailRequestTO struct {
+	Address string `json:"address"`
+}
+
+type SetPhoneRequestTO struct {
+	Phone string `json:"phone"`
+}
+
+type LinkTelegramRequestTO struct {
+	Code string `json:"code"`
+}
+
+type SetTelegramNotificationsRequestTO struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type TelegramLinkingCodeTO struct {
+	Code string `json:"code"`
+}
+
+type SetWebhookRequestTO struct {
+	URL string `json:"url"`
+}
+
+type WebhookSecretTO struct {
+	Secret string `json:"secret"`
+}
+
+// PushSubscriptionRequestTO is the JSON of a browser PushSubscription, as
+// given by its toJSON method
+type PushSubscriptionRequestTO struct {
+	Endpoint string     `json:"endpoint"`
+	Keys     PushKeysTO `json:"keys"`
+}
+
+type PushKeysTO struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
 }
 
 type UserTO struct {
 	Id                 string               `json:"id"`
 	Name               string               `json:"name"`
+	Locale             string               `json:"locale"`
+	ForecastStyle      string               `json:"forecastStyle"`
 	NotificationConfig NotificationConfigTO `json:"notification"`
 }
 
 type NotificationConfigTO struct {
-	Enabled bool                    `json:"enabled"`
-	Web     WebNotificationConfigTO `json:"web"`
+	Enabled  bool                         `json:"enabled"`
+	Web      WebNotificationConfigTO      `json:"web"`
+	Email    EmailNotificationConfigTO    `json:"email"`
+	SMS      SMSNotificationConfigTO      `json:"sms"`
+	Telegram TelegramNotificationConfigTO `json:"telegram"`
+	Webhook  WebhookNotificationConfigTO  `json:"webhook"`
+	Push     PushNotificationConfigTO     `json:"push"`
 }
 
 type WebNotificationConfigTO struct {
@@ -23,16 +79,137 @@ type WebNotificationConfigTO struct {
 	Id      string `json:"id"`
 }
 
+type EmailNotificationConfigTO struct {
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address"`
+}
+
+type SMSNotificationConfigTO struct {
+	Enabled bool   `json:"enabled"`
+	Phone   string `json:"phone"`
+}
+
+type TelegramNotificationConfigTO struct {
+	Enabled bool  `json:"enabled"`
+	ChatID  int64 `json:"chatId"`
+}
+
+// WebhookNotificationConfigTO leaves the secret out, which only
+// notification-service reads, by FindWebhookSecret
+type WebhookNotificationConfigTO struct {
+	Enabled bool   `json:"enabled"`
+	URL     string `json:"url"`
+}
+
+// PushNotificationConfigTO leaves the keys of the subscriptions out, which
+// only notification-service reads, by FindPushSubscriptions
+type PushNotificationConfigTO struct {
+	Enabled       bool                 `json:"enabled"`
+	Subscriptions []PushSubscriptionTO `json:"subscriptions"`
+}
+
+type PushSubscriptionTO struct {
+	Device   string `json:"device"`
+	Endpoint string `json:"endpoint"`
+}
+
+// PushSubscriptionsTO has the keys notification-service encrypts the
+// notifications with
+type PushSubscriptionsTO struct {
+	Subscriptions []PushSubscriptionKeysTO `json:"subscriptions"`
+}
+
+type PushSubscriptionKeysTO struct {
+	Device   string     `json:"device"`
+	Endpoint string     `json:"endpoint"`
+	Keys     PushKeysTO `json:"keys"`
+}
+
 func buildUserTO(u *user.User) UserTO {
 	return UserTO{
-		Id:   u.ID,
-		Name: u.Name,
+		Id:            u.ID,
+		Name:          u.Name,
+		Locale:        u.Locale,
+		ForecastStyle: u.ForecastStyle,
 		NotificationConfig: NotificationConfigTO{
 			Enabled: u.NotificationConfig.Enabled,
 			Web: WebNotificationConfigTO{
 				Enabled: u.NotificationConfig.Web.Enabled,
 				Id:      u.NotificationConfig.Web.Id,
 			},
+			Email: EmailNotificationConfigTO{
+				Enabled: u.NotificationConfig.Email.Enabled,
+				Address: u.NotificationConfig.Email.Address,
+			},
+			SMS: SMSNotificationConfigTO{
+				Enabled: u.NotificationConfig.SMS.Enabled,
+				Phone:   u.NotificationConfig.SMS.Phone,
+			},
+			Telegram: TelegramNotificationConfigTO{
+				Enabled: u.NotificationConfig.Telegram.Enabled,
+				ChatID:  u.NotificationConfig.Telegram.ChatID,
+			},
+			Webhook: WebhookNotificationConfigTO{
+				Enabled: u.NotificationConfig.Webhook.Enabled,
+				URL:     u.NotificationConfig.Webhook.URL,
+			},
+			Push: buildPushNotificationConfigTO(u.NotificationConfig.Push),
 		},
 	}
 }
+
+func buildPushNotificationConfigTO(config user.PushNotificationConfig) PushNotificationConfigTO {
+	subscriptions := make([]PushSubscriptionTO, 0, len(config.Subscriptions))
+
+	for _, subscription := range config.Subscriptions {
+		subscriptions = append(subscriptions, PushSubscriptionTO{
+			Device:   subscription.Device,
+			Endpoint: subscription.Endpoint,
+		})
+	}
+
+	return PushNotificationConfigTO{
+		Enabled:       config.Enabled,
+		Subscriptions: subscriptions,
+	}
+}
+
+func buildPushSubscriptionsTO(config user.PushNotificationConfig) PushSubscriptionsTO {
+	subscriptions := make([]PushSubscriptionKeysTO, 0, len(config.Subscriptions))
+
+	for _, subscription := range config.Subscriptions {
+		subscriptions = append(subscriptions, PushSubscriptionKeysTO{
+			Device:   subscription.Device,
+			Endpoint: subscription.Endpoint,
+			Keys: PushKeysTO{
+				P256dh: subscription.P256dh,
+				Auth:   subscription.Auth,
+			},
+		})
+	}
+
+	return PushSubscriptionsTO{Subscriptions: subscriptions}
+}
+
+// Validate checks the fields needed before the request reaches the service
+func (r CreateUserRequestTO) Validate() []FieldError {
+	return validateRequired(nil, "name", r.Name)
+}
+
+func (r LinkTelegramRequestTO) Validate() []FieldError {
+	return validateRequired(nil, "code", r.Code)
+}
+
+func (r SetTelegramNotificationsRequestTO) Validate() []FieldError {
+	if r.Enabled == nil {
+		return []FieldError{{Field: "enabled", Message: "enabled is required"}}
+	}
+
+	return nil
+}
+
+func (r PushSubscriptionRequestTO) Validate() []FieldError {
+	errs := validateRequired(nil, "endpoint", r.Endpoint)
+	errs = validateRequired(errs, "keys.p256dh", r.Keys.P256dh)
+	return validateRequired(errs, "keys.auth", r.Keys.Auth)
+}
@@ -2,8 +2,8 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/fgouvea/weather/user-service/user"
 	"github.com/go-chi/chi/v5"
@@ -12,8 +12,19 @@ import (
 
 type UserProcessor interface {
 	Find(id string) (*user.User, error)
-	Create(name, webNotificationId string) (*user.User, error)
+	Create(name, webNotificationId, locale string) (*user.User, error)
 	OptOutOfNotifications(id string) error
+	SetLocale(id, locale string) error
+	SetForecastStyle(id, style string) error
+	SetEmail(id, address string) error
+	SetPhone(id, phone string) error
+	CreateTelegramLinkingCode(id string) (string, error)
+	LinkTelegram(code string, chatID int64) (*user.User, error)
+	FindByTelegramChat(chatID int64) (*user.User, error)
+	SetTelegramNotifications(chatID int64, enabled bool) (*user.User, error)
+	SetWebhook(id, webhookURL string) (string, error)
+	RegisterPushSubscription(id string, subscription user.PushSubscription) error
+	UnregisterPushSubscription(id, device, endpoint string) error
 }
 
 type UserHandler struct {
@@ -27,22 +38,14 @@ func (h *UserHandler) FindUser(w http.ResponseWriter, r *http.Request) {
 	result, err := h.Service.Find(userID)
 
 	if err != nil {
-		if errors.Is(err, user.ErrUserNotFound) {
-			h.Logger.Info("user not found", zap.String("userID", userID))
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-
-		h.Logger.Error("error finding user", zap.String("userID", userID), zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, &h.Logger, "error finding user", err, zap.String("userID", userID))
 		return
 	}
 
 	responseBody, err := json.Marshal(buildUserTO(result))
 
 	if err != nil {
-		h.Logger.Error("error writing find response", zap.String("userID", userID), zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, &h.Logger, "error writing find response", err, zap.String("userID", userID))
 		return
 	}
 
@@ -57,24 +60,26 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&body)
 
 	if err != nil {
-		h.Logger.Error("error reading create body", zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusBadRequest)
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, &h.Logger, errs)
 		return
 	}
 
-	result, err := h.Service.Create(body.Name, body.WebNotificationID)
+	result, err := h.Service.Create(body.Name, body.WebNotificationID, body.Locale)
 
 	if err != nil {
-		h.Logger.Error("error creating user", zap.String("userName", body.Name), zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, &h.Logger, "error creating user", err, zap.String("userName", body.Name), zap.String("locale", body.Locale))
 		return
 	}
 
 	responseBody, err := json.Marshal(buildUserTO(result))
 
 	if err != nil {
-		h.Logger.Error("error writing create response", zap.String("userID", result.ID), zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, &h.Logger, "error writing create response", err, zap.String("userID", result.ID))
 		return
 	}
 
@@ -90,14 +95,7 @@ func (h *UserHandler) OutOutOfNotifications(w http.ResponseWriter, r *http.Reque
 	err := h.Service.OptOutOfNotifications(userID)
 
 	if err != nil {
-		if errors.Is(err, user.ErrUserNotFound) {
-			h.Logger.Info("user not found", zap.String("userID", userID))
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-
-		h.Logger.Error("error disabling user notifications", zap.String("userID", userID), zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, &h.Logger, "error disabling user notifications", err, zap.String("userID", userID))
 		return
 	}
 
@@ -105,3 +103,372 @@ func (h *UserHandler) OutOutOfNotifications(w http.ResponseWriter, r *http.Reque
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func (h *UserHandler) SetLocale(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	var body SetLocaleRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	err = h.Service.SetLocale(userID, body.Locale)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error setting user locale", err, zap.String("userID", userID), zap.String("locale", body.Locale))
+		return
+	}
+
+	h.Logger.Info("set locale for user", zap.String("userID", userID), zap.String("locale", body.Locale))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *UserHandler) SetForecastStyle(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	var body SetForecastStyleRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	err = h.Service.SetForecastStyle(userID, body.ForecastStyle)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error setting user forecast style", err, zap.String("userID", userID), zap.String("forecastStyle", body.ForecastStyle))
+		return
+	}
+
+	h.Logger.Info("set forecast style for user", zap.String("userID", userID), zap.String("forecastStyle", body.ForecastStyle))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *UserHandler) SetEmail(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	var body SetEmailRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	err = h.Service.SetEmail(userID, body.Address)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error setting user email", err, zap.String("userID", userID))
+		return
+	}
+
+	h.Logger.Info("set email for user", zap.String("userID", userID), zap.Bool("enabled", body.Address != ""))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *UserHandler) SetPhone(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	var body SetPhoneRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	err = h.Service.SetPhone(userID, body.Phone)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error setting user phone", err, zap.String("userID", userID))
+		return
+	}
+
+	h.Logger.Info("set phone for user", zap.String("userID", userID), zap.Bool("enabled", body.Phone != ""))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *UserHandler) SetWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	var body SetWebhookRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	secret, err := h.Service.SetWebhook(userID, body.URL)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error setting user webhook", err, zap.String("userID", userID), zap.String("url", body.URL))
+		return
+	}
+
+	h.Logger.Info("set webhook for user", zap.String("userID", userID), zap.String("url", body.URL))
+
+	if secret == "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	responseBody, err := json.Marshal(WebhookSecretTO{Secret: secret})
+
+	if err != nil {
+		writeError(w, &h.Logger, "error writing webhook response", err, zap.String("userID", userID))
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(responseBody)
+}
+
+func (h *UserHandler) RegisterPushSubscription(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	device := chi.URLParam(r, "device")
+
+	var body PushSubscriptionRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, &h.Logger, errs)
+		return
+	}
+
+	err = h.Service.RegisterPushSubscription(userID, user.PushSubscription{
+		Device:   device,
+		Endpoint: body.Endpoint,
+		P256dh:   body.Keys.P256dh,
+		Auth:     body.Keys.Auth,
+	})
+
+	if err != nil {
+		writeError(w, &h.Logger, "error registering push subscription", err, zap.String("userID", userID), zap.String("device", device))
+		return
+	}
+
+	h.Logger.Info("registered push subscription", zap.String("userID", userID), zap.String("device", device))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UnregisterPushSubscription removes the subscription of the device. The
+// endpoint query parameter, when given, must match the subscription.
+func (h *UserHandler) UnregisterPushSubscription(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	device := chi.URLParam(r, "device")
+	endpoint := r.URL.Query().Get("endpoint")
+
+	err := h.Service.UnregisterPushSubscription(userID, device, endpoint)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error unregistering push subscription", err, zap.String("userID", userID), zap.String("device", device))
+		return
+	}
+
+	h.Logger.Info("unregistered push subscription", zap.String("userID", userID), zap.String("device", device))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *UserHandler) CreateTelegramLinkingCode(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	code, err := h.Service.CreateTelegramLinkingCode(userID)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error creating telegram linking code", err, zap.String("userID", userID))
+		return
+	}
+
+	responseBody, err := json.Marshal(TelegramLinkingCodeTO{Code: code})
+
+	if err != nil {
+		writeError(w, &h.Logger, "error writing linking code response", err, zap.String("userID", userID))
+		return
+	}
+
+	h.Logger.Info("telegram linking code created", zap.String("userID", userID))
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(responseBody)
+}
+
+func (h *UserHandler) LinkTelegram(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := h.readChatID(w, r)
+
+	if !ok {
+		return
+	}
+
+	var body LinkTelegramRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, &h.Logger, errs)
+		return
+	}
+
+	result, err := h.Service.LinkTelegram(body.Code, chatID)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error linking telegram chat", err, zap.Int64("chatID", chatID))
+		return
+	}
+
+	responseBody, err := json.Marshal(buildUserTO(result))
+
+	if err != nil {
+		writeError(w, &h.Logger, "error writing link response", err, zap.String("userID", result.ID))
+		return
+	}
+
+	h.Logger.Info("telegram chat linked", zap.String("userID", result.ID), zap.Int64("chatID", chatID))
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(responseBody)
+}
+
+// FindWebhookSecret gives notification-service the secret the webhook
+// notifications of the user are signed with, kept out of FindUser
+func (h *UserHandler) FindWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	result, err := h.Service.Find(userID)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error finding user webhook secret", err, zap.String("userID", userID))
+		return
+	}
+
+	responseBody, err := json.Marshal(WebhookSecretTO{Secret: result.NotificationConfig.Webhook.Secret})
+
+	if err != nil {
+		writeError(w, &h.Logger, "error writing webhook secret response", err, zap.String("userID", userID))
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(responseBody)
+}
+
+// FindPushSubscriptions gives notification-service the keys of the push
+// subscriptions of the user, kept out of FindUser
+func (h *UserHandler) FindPushSubscriptions(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+
+	result, err := h.Service.Find(userID)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error finding user push subscriptions", err, zap.String("userID", userID))
+		return
+	}
+
+	responseBody, err := json.Marshal(buildPushSubscriptionsTO(result.NotificationConfig.Push))
+
+	if err != nil {
+		writeError(w, &h.Logger, "error writing push subscriptions response", err, zap.String("userID", userID))
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(responseBody)
+}
+
+func (h *UserHandler) FindByTelegramChat(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := h.readChatID(w, r)
+
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.FindByTelegramChat(chatID)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error finding user by telegram chat", err, zap.Int64("chatID", chatID))
+		return
+	}
+
+	responseBody, err := json.Marshal(buildUserTO(result))
+
+	if err != nil {
+		writeError(w, &h.Logger, "error writing find response", err, zap.String("userID", result.ID))
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(responseBody)
+}
+
+// SetTelegramNotifications turns the notifications sent to the chat on or off,
+// as the bot does for /parar and /voltar
+func (h *UserHandler) SetTelegramNotifications(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := h.readChatID(w, r)
+
+	if !ok {
+		return
+	}
+
+	var body SetTelegramNotificationsRequestTO
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, &h.Logger, err)
+		return
+	}
+
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, &h.Logger, errs)
+		return
+	}
+
+	result, err := h.Service.SetTelegramNotifications(chatID, *body.Enabled)
+
+	if err != nil {
+		writeError(w, &h.Logger, "error setting telegram notifications", err, zap.Int64("chatID", chatID))
+		return
+	}
+
+	responseBody, err := json.Marshal(buildUserTO(result))
+
+	if err != nil {
+		writeError(w, &h.Logger, "error writing telegram notifications response", err, zap.String("userID", result.ID))
+		return
+	}
+
+	h.Logger.Info("telegram notifications set", zap.String("userID", result.ID), zap.Int64("chatID", chatID), zap.Bool("enabled", *body.Enabled))
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(responseBody)
+}
+
+func (h *UserHandler) readChatID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	chatID, err := strconv.ParseInt(chi.URLParam(r, "chatID"), 10, 64)
+
+	if err != nil || chatID == 0 {
+		writeInvalid(w, &h.Logger, []FieldError{{Field: "chatID", Message: "chatID must be a telegram chat id"}})
+		return 0, false
+	}
+
+	return chatID, true
+}
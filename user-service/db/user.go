@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/fgouvea/weather/user-service/user"
 	_ "github.com/lib/pq"
@@ -39,14 +40,27 @@ func NewUserRepository(host, port, user, password, database string) (*UserReposi
 }
 
 func (r *UserRepository) Find(id string) (*user.User, error) {
+	return r.findOne("id = $1", id)
+}
+
+func (r *UserRepository) FindByTelegramLinkingCode(code string) (*user.User, error) {
+	return r.findOne("notification_config->'Telegram'->>'LinkingCode' = $1", code)
+}
+
+func (r *UserRepository) FindByTelegramChatID(chatID int64) (*user.User, error) {
+	return r.findOne("notification_config->'Telegram'->>'ChatID' = $1", strconv.FormatInt(chatID, 10))
+}
+
+func (r *UserRepository) findOne(condition string, arg any) (*user.User, error) {
 	query := `
-	SELECT id, name, notification_config FROM weather.Users
-	WHERE id = $1;
+	SELECT id, name, locale, forecast_style, notification_config FROM weather.Users
+	WHERE ` + condition + `
+	LIMIT 1;
 	`
 
-	var userID, name, rawNotificationConfig string
+	var userID, name, locale, forecastStyle, rawNotificationConfig string
 
-	err := r.DbConnection.QueryRow(query, id).Scan(&userID, &name, &rawNotificationConfig)
+	err := r.DbConnection.QueryRow(query, arg).Scan(&userID, &name, &locale, &forecastStyle, &rawNotificationConfig)
 
 	if err == sql.ErrNoRows {
 		return nil, user.ErrUserNotFound
@@ -60,24 +74,28 @@ func (r *UserRepository) Find(id string) (*user.User, error) {
 	err = json.Unmarshal([]byte(rawNotificationConfig), &notificationConfig)
 
 	if err != nil {
-		return nil, fmt.Errorf("error reading notification config: %w", ErrExecuteQuery, err)
+		return nil, fmt.Errorf("%w: error reading notification config: %w", ErrExecuteQuery, err)
 	}
 
 	return &user.User{
 		ID:                 userID,
 		Name:               name,
+		Locale:             locale,
+		ForecastStyle:      forecastStyle,
 		NotificationConfig: notificationConfig,
 	}, nil
 }
 
 func (r *UserRepository) Save(u *user.User) error {
 	query := `
-	INSERT INTO weather.Users (id, name, notification_config)
-	VALUES ($1, $2, $3)
+	INSERT INTO weather.Users (id, name, notification_config, locale, forecast_style)
+	VALUES ($1, $2, $3, $4, $5)
 	ON CONFLICT(id)
 	DO UPDATE SET
 		name = $2,
-		notification_config = $3;
+		notification_config = $3,
+		locale = $4,
+		forecast_style = $5;
 	`
 
 	notificationConfig, err := json.Marshal(u.NotificationConfig)
@@ -86,7 +104,7 @@ func (r *UserRepository) Save(u *user.User) error {
 		return err
 	}
 
-	_, err = r.DbConnection.Query(query, u.ID, u.Name, notificationConfig)
+	_, err = r.DbConnection.Query(query, u.ID, u.Name, notificationConfig, u.Locale, u.ForecastStyle)
 
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
@@ -13,22 +13,28 @@ import (
 )
 
 type AppConfig struct {
-	Port       string
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBDatabase string
+	Port string
+	// InternalPort serves the routes only other services call, which must not
+	// be reachable from outside
+	InternalPort  string
+	InternalToken string `json:"-"`
+	DBHost        string
+	DBPort        string
+	DBUser        string
+	DBPassword    string
+	DBDatabase    string
 }
 
 func readConfigFromEnv() AppConfig {
 	return AppConfig{
-		Port:       fmt.Sprintf(":%s", readFromEnv("PORT", "8080")),
-		DBHost:     readFromEnv("DB_HOST", "localhost"),
-		DBPort:     readFromEnv("DB_PORT", "5432"),
-		DBUser:     readFromEnv("DB_USER", "admin"),
-		DBPassword: readFromEnv("DB_PASSWORD", "admin"),
-		DBDatabase: readFromEnv("DB_DATABASE", "weather"),
+		Port:          fmt.Sprintf(":%s", readFromEnv("PORT", "8080")),
+		InternalPort:  fmt.Sprintf(":%s", readFromEnv("INTERNAL_PORT", "8090")),
+		InternalToken: os.Getenv("INTERNAL_API_TOKEN"),
+		DBHost:        readFromEnv("DB_HOST", "localhost"),
+		DBPort:        readFromEnv("DB_PORT", "5432"),
+		DBUser:        readFromEnv("DB_USER", "admin"),
+		DBPassword:    readFromEnv("DB_PASSWORD", "admin"),
+		DBDatabase:    readFromEnv("DB_DATABASE", "weather"),
 	}
 }
 
@@ -64,6 +70,8 @@ func main() {
 	}
 
 	r := chi.NewRouter()
+	r.NotFound(api.NotFound)
+	r.MethodNotAllowed(api.MethodNotAllowed)
 
 	r.Route("/user-service", func(r chi.Router) {
 		r.Get("/health", api.Health)
@@ -72,9 +80,40 @@ func main() {
 			r.Post("/", handler.CreateUser)
 			r.Get("/{userID}", handler.FindUser)
 			r.Post("/{userID}/optout", handler.OutOutOfNotifications)
+			r.Put("/{userID}/locale", handler.SetLocale)
+			r.Put("/{userID}/forecast-style", handler.SetForecastStyle)
+			r.Put("/{userID}/email", handler.SetEmail)
+			r.Put("/{userID}/phone", handler.SetPhone)
+			r.Post("/{userID}/telegram", handler.CreateTelegramLinkingCode)
+			r.Put("/{userID}/webhook", handler.SetWebhook)
+			r.Put("/{userID}/push/{device}", handler.RegisterPushSubscription)
+			r.Delete("/{userID}/push/{device}", handler.UnregisterPushSubscription)
 		})
+
+		r.Get("/telegram/chats/{chatID}", handler.FindByTelegramChat)
+		r.Put("/telegram/chats/{chatID}", handler.LinkTelegram)
+		r.Put("/telegram/chats/{chatID}/notifications", handler.SetTelegramNotifications)
 	})
 
+	if config.InternalToken == "" {
+		panic("INTERNAL_API_TOKEN is required")
+	}
+
+	internal := chi.NewRouter()
+	internal.NotFound(api.NotFound)
+	internal.MethodNotAllowed(api.MethodNotAllowed)
+	internal.Use(api.InternalAuth(config.InternalToken))
+
+	internal.Route("/user-service/internal/user/{userID}", func(r chi.Router) {
+		r.Get("/webhook/secret", handler.FindWebhookSecret)
+		r.Get("/push", handler.FindPushSubscriptions)
+	})
+
+	go func() {
+		err := http.ListenAndServe(config.InternalPort, internal)
+		logger.Error("internal listener stopped", zap.Error(err))
+	}()
+
 	logger.Info("application started", zap.Any("config", config))
 	defer logger.Info("application shutdown")
 
@@ -5,6 +5,14 @@ type MockRepository struct {
 	FindResult User
 	FindError  error
 
+	FindByTelegramLinkingCodeCalls  []string
+	FindByTelegramLinkingCodeResult User
+	FindByTelegramLinkingCodeError  error
+
+	FindByTelegramChatIDCalls  []int64
+	FindByTelegramChatIDResult User
+	FindByTelegramChatIDError  error
+
 	SaveCalls []*User
 	SaveError error
 }
@@ -17,6 +25,16 @@ func (r *MockRepository) Find(id string) (*User, error) {
 	return &r.FindResult, r.FindError
 }
 
+func (r *MockRepository) FindByTelegramLinkingCode(code string) (*User, error) {
+	r.FindByTelegramLinkingCodeCalls = append(r.FindByTelegramLinkingCodeCalls, code)
+	return &r.FindByTelegramLinkingCodeResult, r.FindByTelegramLinkingCodeError
+}
+
+func (r *MockRepository) FindByTelegramChatID(chatID int64) (*User, error) {
+	r.FindByTelegramChatIDCalls = append(r.FindByTelegramChatIDCalls, chatID)
+	return &r.FindByTelegramChatIDResult, r.FindByTelegramChatIDError
+}
+
 func (r *MockRepository) Save(user *User) error {
 	r.SaveCalls = append(r.SaveCalls, user)
 	return r.SaveError
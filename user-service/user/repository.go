@@ -4,4 +4,14 @@ import (
 	"errors"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound             = errors.New("user not found")
+	ErrInvalidLocale            = errors.New("invalid locale")
+	ErrInvalidStyle             = errors.New("invalid forecast style")
+	ErrInvalidEmail             = errors.New("invalid email address")
+	ErrInvalidPhone             = errors.New("invalid phone number")
+	ErrInvalidLinkingCode       = errors.New("invalid telegram linking code")
+	ErrInvalidWebhookURL        = errors.New("invalid webhook url")
+	ErrInvalidPushSubscription  = errors.New("invalid push subscription")
+	ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+)
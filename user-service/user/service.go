@@ -1,8 +1,17 @@
 package user
 
 import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -14,6 +23,8 @@ type Saver interface {
 
 type Finder interface {
 	Find(id string) (*User, error)
+	FindByTelegramLinkingCode(code string) (*User, error)
+	FindByTelegramChatID(chatID int64) (*User, error)
 }
 
 type Service struct {
@@ -29,10 +40,22 @@ func NewService(saver Saver, finder Finder) *Service {
 	}
 }
 
-func (s *Service) Create(name, webNotificationId string) (*User, error) {
+func (s *Service) Create(name, webNotificationId, locale string) (*User, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	locale, err := parseLocale(locale)
+
+	if err != nil {
+		return nil, err
+	}
+
 	user := &User{
-		ID:   "USER-" + uuid.New().String(),
-		Name: name,
+		ID:            "USER-" + uuid.New().String(),
+		Name:          name,
+		Locale:        locale,
+		ForecastStyle: DefaultForecastStyle,
 		NotificationConfig: NotificationConfig{
 			Enabled: true,
 			Web: WebNotificationConfig{
@@ -42,7 +65,7 @@ func (s *Service) Create(name, webNotificationId string) (*User, error) {
 		},
 	}
 
-	err := s.save(user)
+	err = s.save(user)
 
 	if err != nil {
 		return nil, err
@@ -77,6 +100,396 @@ func (s *Service) OptOutOfNotifications(id string) error {
 	return s.save(user)
 }
 
+func (s *Service) SetLocale(id, locale string) error {
+	locale, err := parseLocale(locale)
+
+	if err != nil {
+		return err
+	}
+
+	user, err := s.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	user.Locale = locale
+
+	return s.save(user)
+}
+
+func (s *Service) SetForecastStyle(id, style string) error {
+	if !slices.Contains(ForecastStyles, style) {
+		return fmt.Errorf("%w: %s, must be one of %s", ErrInvalidStyle, style, strings.Join(ForecastStyles, ", "))
+	}
+
+	user, err := s.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	user.ForecastStyle = style
+
+	return s.save(user)
+}
+
+// SetEmail sends notifications sent by email to the address, or stops them
+// when it is empty
+func (s *Service) SetEmail(id, address string) error {
+	config := EmailNotificationConfig{}
+
+	if address != "" {
+		parsed, err := mail.ParseAddress(address)
+
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidEmail, address)
+		}
+
+		config = EmailNotificationConfig{Enabled: true, Address: parsed.Address}
+	}
+
+	user, err := s.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	user.NotificationConfig.Email = config
+
+	return s.save(user)
+}
+
+// SetPhone sends notifications sent by SMS to the phone number, or stops
+// them when it is empty
+func (s *Service) SetPhone(id, phone string) error {
+	config := SMSNotificationConfig{}
+
+	if phone != "" {
+		parsed, ok := ParsePhone(phone)
+
+		if !ok {
+			return fmt.Errorf("%w: %s, must be in E.164 format, as +5521999990000", ErrInvalidPhone, phone)
+		}
+
+		config = SMSNotificationConfig{Enabled: true, Phone: parsed}
+	}
+
+	user, err := s.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	user.NotificationConfig.SMS = config
+
+	return s.save(user)
+}
+
+// CreateTelegramLinkingCode gives the user a code to send to the bot, which
+// links the chat it is sent from. A new code replaces the previous one.
+func (s *Service) CreateTelegramLinkingCode(id string) (string, error) {
+	user, err := s.Find(id)
+
+	if err != nil {
+		return "", err
+	}
+
+	code, err := newLinkingCode()
+
+	if err != nil {
+		return "", fmt.Errorf("unexpected error creating linking code: %w", err)
+	}
+
+	user.NotificationConfig.Telegram.LinkingCode = code
+
+	err = s.save(user)
+
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// LinkTelegram sends the notifications of the user with the linking code to
+// the chat. A chat is linked to one user at a time, so a user previously
+// linked to it stops receiving them.
+func (s *Service) LinkTelegram(code string, chatID int64) (*User, error) {
+	if code == "" {
+		return nil, fmt.Errorf("%w: code is required", ErrInvalidLinkingCode)
+	}
+
+	user, err := s.Finder.FindByTelegramLinkingCode(code)
+
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLinkingCode, code)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error fetching user: %w", err)
+	}
+
+	previous, err := s.FindByTelegramChat(chatID)
+
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, err
+	}
+
+	if err == nil && previous.ID != user.ID {
+		previous.NotificationConfig.Telegram = TelegramNotificationConfig{}
+
+		err = s.save(previous)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	user.NotificationConfig.Telegram = TelegramNotificationConfig{Enabled: true, ChatID: chatID}
+
+	err = s.save(user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *Service) FindByTelegramChat(chatID int64) (*User, error) {
+	user, err := s.Finder.FindByTelegramChatID(chatID)
+
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("unexpected error fetching user: %w", err)
+	}
+
+	return user, nil
+}
+
+// SetTelegramNotifications turns the notifications sent to the chat on or
+// off, leaving the other channels of the user linked to it as they are.
+func (s *Service) SetTelegramNotifications(chatID int64, enabled bool) (*User, error) {
+	user, err := s.FindByTelegramChat(chatID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user.NotificationConfig.Telegram.Enabled = enabled
+
+	err = s.save(user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetWebhook posts the notifications of the user to the URL, signed with a
+// new secret, which is returned. An empty URL stops them.
+func (s *Service) SetWebhook(id, webhookURL string) (string, error) {
+	config := WebhookNotificationConfig{}
+
+	if webhookURL != "" {
+		parsed, err := url.Parse(webhookURL)
+
+		// The notifications and their signature would be readable over plain
+		// http
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return "", fmt.Errorf("%w: %s, must be an https url", ErrInvalidWebhookURL, webhookURL)
+		}
+
+		if !isPublicHost(parsed.Hostname()) {
+			return "", fmt.Errorf("%w: %s, must be a public address", ErrInvalidWebhookURL, webhookURL)
+		}
+
+		secret, err := newWebhookSecret()
+
+		if err != nil {
+			return "", fmt.Errorf("unexpected error creating webhook secret: %w", err)
+		}
+
+		config = WebhookNotificationConfig{Enabled: true, URL: parsed.String(), Secret: secret}
+	}
+
+	user, err := s.Find(id)
+
+	if err != nil {
+		return "", err
+	}
+
+	user.NotificationConfig.Webhook = config
+
+	err = s.save(user)
+
+	if err != nil {
+		return "", err
+	}
+
+	return config.Secret, nil
+}
+
+// RegisterPushSubscription pushes notifications to the browser of the device,
+// replacing the subscription the device had before
+func (s *Service) RegisterPushSubscription(id string, subscription PushSubscription) error {
+	err := validatePushSubscription(subscription)
+
+	if err != nil {
+		return err
+	}
+
+	user, err := s.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	// A browser subscribing again under another device name is the same device
+	subscriptions := slices.DeleteFunc(slices.Clone(user.NotificationConfig.Push.Subscriptions), func(existing PushSubscription) bool {
+		return existing.Device == subscription.Device || existing.Endpoint == subscription.Endpoint
+	})
+
+	user.NotificationConfig.Push = PushNotificationConfig{
+		Enabled:       true,
+		Subscriptions: append(subscriptions, subscription),
+	}
+
+	return s.save(user)
+}
+
+// UnregisterPushSubscription stops pushing to the device. Given an endpoint,
+// the subscription is only removed while it still has it, so an expired
+// subscription reported by the push service does not take down the one the
+// device registered after it.
+func (s *Service) UnregisterPushSubscription(id, device, endpoint string) error {
+	user, err := s.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	subscriptions := user.NotificationConfig.Push.Subscriptions
+
+	index := slices.IndexFunc(subscriptions, func(existing PushSubscription) bool {
+		return existing.Device == device && (endpoint == "" || existing.Endpoint == endpoint)
+	})
+
+	if index < 0 {
+		return fmt.Errorf("%w: %s", ErrPushSubscriptionNotFound, device)
+	}
+
+	subscriptions = slices.Delete(slices.Clone(subscriptions), index, index+1)
+
+	user.NotificationConfig.Push = PushNotificationConfig{
+		Enabled:       len(subscriptions) > 0,
+		Subscriptions: subscriptions,
+	}
+
+	return s.save(user)
+}
+
+// newLinkingCode is short enough to be typed, as in "/start K3QF7XWA"
+func newLinkingCode() (string, error) {
+	code := make([]byte, 5)
+
+	_, err := rand.Read(code)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.EncodeToString(code), nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+
+	_, err := rand.Read(secret)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+func parseLocale(locale string) (string, error) {
+	parsed, ok := ParseLocale(locale)
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s, must be one of %s", ErrInvalidLocale, locale, strings.Join(Locales, ", "))
+	}
+
+	return parsed, nil
+}
+
+// validatePushSubscription checks the endpoint is a push service, which RFC
+// 8030 requires to be https, and the keys are a P-256 public key and a
+// 16-byte secret, in base64url as browsers give them
+func validatePushSubscription(subscription PushSubscription) error {
+	if subscription.Device == "" || len(subscription.Device) > maxDeviceLength {
+		return fmt.Errorf("%w: device must have from 1 to %d characters", ErrInvalidPushSubscription, maxDeviceLength)
+	}
+
+	endpoint, err := url.Parse(subscription.Endpoint)
+
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return fmt.Errorf("%w: endpoint %s must be an https url", ErrInvalidPushSubscription, subscription.Endpoint)
+	}
+
+	if !isPublicHost(endpoint.Hostname()) {
+		return fmt.Errorf("%w: endpoint %s must be a public address", ErrInvalidPushSubscription, subscription.Endpoint)
+	}
+
+	p256dh, err := decodePushKey(subscription.P256dh)
+
+	if err != nil || len(p256dh) != 65 || p256dh[0] != 0x04 {
+		return fmt.Errorf("%w: p256dh must be an uncompressed P-256 public key", ErrInvalidPushSubscription)
+	}
+
+	auth, err := decodePushKey(subscription.Auth)
+
+	if err != nil || len(auth) != 16 {
+		return fmt.Errorf("%w: auth must have 16 bytes", ErrInvalidPushSubscription)
+	}
+
+	return nil
+}
+
+// isPublicHost refuses localhost and the addresses of the internal network,
+// which notification-service would otherwise be made to call. Names are not
+// resolved here, since they may resolve differently later, so
+// notification-service checks the address again when connecting.
+func isPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	ip, err := netip.ParseAddr(host)
+
+	if err != nil {
+		return true
+	}
+
+	ip = ip.Unmap()
+
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// decodePushKey reads base64url with or without padding
+func decodePushKey(key string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(key, "="))
+}
+
 func (s *Service) save(user *User) error {
 	err := s.Saver.Save(user)
 
@@ -12,6 +12,7 @@ func TestUserService_Create_Success(t *testing.T) {
 		name                  string
 		userName              string
 		userWebNotificationId string
+		userLocale            string
 		expectedResult        User
 	}{
 		{
@@ -19,7 +20,8 @@ func TestUserService_Create_Success(t *testing.T) {
 			userName:              "Fulano Beltrano",
 			userWebNotificationId: "123",
 			expectedResult: User{
-				Name: "Fulano Beltrano",
+				Name:   "Fulano Beltrano",
+				Locale: DefaultLocale,
 				NotificationConfig: NotificationConfig{
 					Enabled: true,
 					Web: WebNotificationConfig{
@@ -34,7 +36,25 @@ func TestUserService_Create_Success(t *testing.T) {
 			userName:              "Fulano Beltrano",
 			userWebNotificationId: "",
 			expectedResult: User{
-				Name: "Fulano Beltrano",
+				Name:   "Fulano Beltrano",
+				Locale: DefaultLocale,
+				NotificationConfig: NotificationConfig{
+					Enabled: true,
+					Web: WebNotificationConfig{
+						Enabled: false,
+						Id:      "",
+					},
+				},
+			},
+		},
+		{
+			name:                  "with locale",
+			userName:              "Fulano Beltrano",
+			userWebNotificationId: "",
+			userLocale:            "EN",
+			expectedResult: User{
+				Name:   "Fulano Beltrano",
+				Locale: "en",
 				NotificationConfig: NotificationConfig{
 					Enabled: true,
 					Web: WebNotificationConfig{
@@ -55,12 +75,14 @@ func TestUserService_Create_Success(t *testing.T) {
 
 			service := NewService(repositoryMock, repositoryMock)
 
-			result, err := service.Create(tt.userName, tt.userWebNotificationId)
+			result, err := service.Create(tt.userName, tt.userWebNotificationId, tt.userLocale)
 
 			assert.Nil(t, err)
 			assert.NotNil(t, result)
 
 			assert.Equal(t, tt.expectedResult.Name, result.Name)
+			assert.Equal(t, tt.expectedResult.Locale, result.Locale)
+			assert.Equal(t, DefaultForecastStyle, result.ForecastStyle)
 			assert.Equal(t, tt.expectedResult.NotificationConfig, result.NotificationConfig)
 
 			assert.Len(t, repositoryMock.SaveCalls, 1)
@@ -77,12 +99,26 @@ func TestUserService_Create_Error(t *testing.T) {
 
 	service := NewService(repositoryMock, repositoryMock)
 
-	result, err := service.Create("Fulano Beltrano", "123")
+	result, err := service.Create("Fulano Beltrano", "123", "")
 
 	assert.Nil(t, result)
 	assert.EqualError(t, err, "unexpected error saving user: runtime error")
 }
 
+func TestUserService_Create_InvalidLocale(t *testing.T) {
+	repositoryMock := &MockRepository{
+		SaveCalls: []*User{},
+	}
+
+	service := NewService(repositoryMock, repositoryMock)
+
+	result, err := service.Create("Fulano Beltrano", "123", "fr")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrInvalidLocale)
+	assert.Len(t, repositoryMock.SaveCalls, 0)
+}
+
 func TestUserService_Find_Success(t *testing.T) {
 	savedUser := User{
 		ID:   "USER-1",
@@ -232,3 +268,791 @@ func TestUserService_OptOutOf_Notifications(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_SetLocale(t *testing.T) {
+	tests := []struct {
+		name              string
+		locale            string
+		findError         error
+		expectedError     error
+		expectedFindCalls []string
+		expectedLocale    string
+	}{
+		{
+			name:              "success",
+			locale:            "es",
+			expectedFindCalls: []string{"USER-1"},
+			expectedLocale:    "es",
+		},
+		{
+			name:              "invalid locale",
+			locale:            "fr",
+			expectedError:     ErrInvalidLocale,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "user not found",
+			locale:            "es",
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindCalls:  []string{},
+				FindResult: User{ID: "USER-1", Name: "Fulano Beltrano", Locale: DefaultLocale},
+				FindError:  tt.findError,
+				SaveCalls:  []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			err := service.SetLocale("USER-1", tt.locale)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCalls, repositoryMock.FindCalls)
+
+			if tt.expectedError != nil {
+				assert.Len(t, repositoryMock.SaveCalls, 0)
+				return
+			}
+
+			assert.Len(t, repositoryMock.SaveCalls, 1)
+			assert.Equal(t, tt.expectedLocale, repositoryMock.SaveCalls[0].Locale)
+		})
+	}
+}
+
+func TestUserService_SetForecastStyle(t *testing.T) {
+	tests := []struct {
+		name              string
+		style             string
+		findError         error
+		expectedError     error
+		expectedFindCalls []string
+	}{
+		{
+			name:              "success",
+			style:             "detailed",
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "invalid style",
+			style:             "verbose",
+			expectedError:     ErrInvalidStyle,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "user not found",
+			style:             "detailed",
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindCalls:  []string{},
+				FindResult: User{ID: "USER-1", Name: "Fulano Beltrano", ForecastStyle: DefaultForecastStyle},
+				FindError:  tt.findError,
+				SaveCalls:  []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			err := service.SetForecastStyle("USER-1", tt.style)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCalls, repositoryMock.FindCalls)
+
+			if tt.expectedError != nil {
+				assert.Len(t, repositoryMock.SaveCalls, 0)
+				return
+			}
+
+			assert.Len(t, repositoryMock.SaveCalls, 1)
+			assert.Equal(t, tt.style, repositoryMock.SaveCalls[0].ForecastStyle)
+		})
+	}
+}
+
+func TestUserService_SetEmail(t *testing.T) {
+	tests := []struct {
+		name              string
+		address           string
+		findError         error
+		expectedConfig    EmailNotificationConfig
+		expectedError     error
+		expectedFindCalls []string
+	}{
+		{
+			name:              "success",
+			address:           "Fulano <fulano@example.com>",
+			expectedConfig:    EmailNotificationConfig{Enabled: true, Address: "fulano@example.com"},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "empty address disables email",
+			address:           "",
+			expectedConfig:    EmailNotificationConfig{},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "invalid address",
+			address:           "fulano",
+			expectedError:     ErrInvalidEmail,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "user not found",
+			address:           "fulano@example.com",
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindCalls: []string{},
+				FindResult: User{
+					ID:   "USER-1",
+					Name: "Fulano Beltrano",
+					NotificationConfig: NotificationConfig{
+						Enabled: true,
+						Email:   EmailNotificationConfig{Enabled: true, Address: "antigo@example.com"},
+					},
+				},
+				FindError: tt.findError,
+				SaveCalls: []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			err := service.SetEmail("USER-1", tt.address)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCalls, repositoryMock.FindCalls)
+
+			if tt.expectedError != nil {
+				assert.Len(t, repositoryMock.SaveCalls, 0)
+				return
+			}
+
+			assert.Len(t, repositoryMock.SaveCalls, 1)
+			assert.Equal(t, tt.expectedConfig, repositoryMock.SaveCalls[0].NotificationConfig.Email)
+		})
+	}
+}
+
+func TestUserService_SetPhone(t *testing.T) {
+	tests := []struct {
+		name              string
+		phone             string
+		findError         error
+		expectedConfig    SMSNotificationConfig
+		expectedError     error
+		expectedFindCalls []string
+	}{
+		{
+			name:              "success",
+			phone:             "+5521999990000",
+			expectedConfig:    SMSNotificationConfig{Enabled: true, Phone: "+5521999990000"},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "formatted number",
+			phone:             "+55 (21) 99999-0000",
+			expectedConfig:    SMSNotificationConfig{Enabled: true, Phone: "+5521999990000"},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "empty phone disables sms",
+			phone:             "",
+			expectedConfig:    SMSNotificationConfig{},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "without country code",
+			phone:             "21999990000",
+			expectedError:     ErrInvalidPhone,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "too long",
+			phone:             "+5521999990000123",
+			expectedError:     ErrInvalidPhone,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "user not found",
+			phone:             "+5521999990000",
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindCalls: []string{},
+				FindResult: User{
+					ID:   "USER-1",
+					Name: "Fulano Beltrano",
+					NotificationConfig: NotificationConfig{
+						Enabled: true,
+						SMS:     SMSNotificationConfig{Enabled: true, Phone: "+5511988880000"},
+					},
+				},
+				FindError: tt.findError,
+				SaveCalls: []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			err := service.SetPhone("USER-1", tt.phone)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCalls, repositoryMock.FindCalls)
+
+			if tt.expectedError != nil {
+				assert.Len(t, repositoryMock.SaveCalls, 0)
+				return
+			}
+
+			assert.Len(t, repositoryMock.SaveCalls, 1)
+			assert.Equal(t, tt.expectedConfig, repositoryMock.SaveCalls[0].NotificationConfig.SMS)
+		})
+	}
+}
+
+func TestUserService_CreateTelegramLinkingCode(t *testing.T) {
+	repositoryMock := &MockRepository{
+		FindCalls:  []string{},
+		FindResult: User{ID: "USER-1", Name: "Fulano Beltrano"},
+		SaveCalls:  []*User{},
+	}
+
+	service := NewService(repositoryMock, repositoryMock)
+
+	code, err := service.CreateTelegramLinkingCode("USER-1")
+
+	assert.Nil(t, err)
+	assert.Len(t, code, 8)
+	assert.Len(t, repositoryMock.SaveCalls, 1)
+	assert.Equal(t, code, repositoryMock.SaveCalls[0].NotificationConfig.Telegram.LinkingCode)
+
+	repositoryMock.FindError = ErrUserNotFound
+
+	_, err = service.CreateTelegramLinkingCode("USER-1")
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Len(t, repositoryMock.SaveCalls, 1)
+}
+
+func TestUserService_LinkTelegram(t *testing.T) {
+	tests := []struct {
+		name                string
+		code                string
+		findByCodeError     error
+		previousUser        User
+		findByChatError     error
+		expectedError       error
+		expectedSavedUsers  []string
+		expectedCodeLookups []string
+	}{
+		{
+			name:                "success",
+			code:                "K3QF7XWA",
+			findByChatError:     ErrUserNotFound,
+			expectedSavedUsers:  []string{"USER-1"},
+			expectedCodeLookups: []string{"K3QF7XWA"},
+		},
+		{
+			name:                "chat linked to another user",
+			code:                "K3QF7XWA",
+			previousUser:        User{ID: "USER-2", NotificationConfig: NotificationConfig{Telegram: TelegramNotificationConfig{Enabled: true, ChatID: 42}}},
+			expectedSavedUsers:  []string{"USER-2", "USER-1"},
+			expectedCodeLookups: []string{"K3QF7XWA"},
+		},
+		{
+			name:                "chat already linked to the user",
+			code:                "K3QF7XWA",
+			previousUser:        User{ID: "USER-1"},
+			expectedSavedUsers:  []string{"USER-1"},
+			expectedCodeLookups: []string{"K3QF7XWA"},
+		},
+		{
+			name:                "unknown code",
+			code:                "K3QF7XWA",
+			findByCodeError:     ErrUserNotFound,
+			expectedError:       ErrInvalidLinkingCode,
+			expectedSavedUsers:  []string{},
+			expectedCodeLookups: []string{"K3QF7XWA"},
+		},
+		{
+			name:                "empty code",
+			code:                "",
+			expectedError:       ErrInvalidLinkingCode,
+			expectedSavedUsers:  []string{},
+			expectedCodeLookups: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindByTelegramLinkingCodeCalls: []string{},
+				FindByTelegramLinkingCodeResult: User{
+					ID:                 "USER-1",
+					NotificationConfig: NotificationConfig{Enabled: true, Telegram: TelegramNotificationConfig{LinkingCode: "K3QF7XWA"}},
+				},
+				FindByTelegramLinkingCodeError: tt.findByCodeError,
+				FindByTelegramChatIDResult:     tt.previousUser,
+				FindByTelegramChatIDError:      tt.findByChatError,
+				SaveCalls:                      []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			result, err := service.LinkTelegram(tt.code, 42)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedCodeLookups, repositoryMock.FindByTelegramLinkingCodeCalls)
+
+			savedUsers := []string{}
+
+			for _, saved := range repositoryMock.SaveCalls {
+				savedUsers = append(savedUsers, saved.ID)
+			}
+
+			assert.Equal(t, tt.expectedSavedUsers, savedUsers)
+
+			if tt.expectedError != nil {
+				assert.Nil(t, result)
+				return
+			}
+
+			assert.Equal(t, TelegramNotificationConfig{Enabled: true, ChatID: 42}, result.NotificationConfig.Telegram)
+
+			if len(repositoryMock.SaveCalls) > 1 {
+				assert.Equal(t, TelegramNotificationConfig{}, repositoryMock.SaveCalls[0].NotificationConfig.Telegram)
+			}
+		})
+	}
+}
+
+func TestUserService_FindByTelegramChat(t *testing.T) {
+	repositoryMock := &MockRepository{
+		FindByTelegramChatIDResult: User{ID: "USER-1"},
+	}
+
+	service := NewService(repositoryMock, repositoryMock)
+
+	result, err := service.FindByTelegramChat(42)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "USER-1", result.ID)
+	assert.Equal(t, []int64{42}, repositoryMock.FindByTelegramChatIDCalls)
+
+	repositoryMock.FindByTelegramChatIDError = fmt.Errorf("error connecting to database")
+
+	_, err = service.FindByTelegramChat(42)
+
+	assert.EqualError(t, err, "unexpected error fetching user: error connecting to database")
+}
+
+func TestUserService_SetTelegramNotifications(t *testing.T) {
+	tests := []struct {
+		name              string
+		enabled           bool
+		findError         error
+		expectedError     error
+		expectedSaveCalls int
+	}{
+		{
+			name:              "disable",
+			enabled:           false,
+			expectedSaveCalls: 1,
+		},
+		{
+			name:              "enable",
+			enabled:           true,
+			expectedSaveCalls: 1,
+		},
+		{
+			name:              "chat not linked",
+			enabled:           false,
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedSaveCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindByTelegramChatIDResult: User{
+					ID: "USER-1",
+					NotificationConfig: NotificationConfig{
+						Enabled:  true,
+						Email:    EmailNotificationConfig{Enabled: true, Address: "fulano@example.com"},
+						Telegram: TelegramNotificationConfig{Enabled: !tt.enabled, ChatID: 42},
+					},
+				},
+				FindByTelegramChatIDError: tt.findError,
+				SaveCalls:                 []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			result, err := service.SetTelegramNotifications(42, tt.enabled)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, []int64{42}, repositoryMock.FindByTelegramChatIDCalls)
+			assert.Len(t, repositoryMock.SaveCalls, tt.expectedSaveCalls)
+
+			if tt.expectedError != nil {
+				assert.Nil(t, result)
+				return
+			}
+
+			saved := repositoryMock.SaveCalls[0].NotificationConfig
+			assert.Equal(t, TelegramNotificationConfig{Enabled: tt.enabled, ChatID: 42}, saved.Telegram)
+			assert.True(t, saved.Enabled)
+			assert.True(t, saved.Email.Enabled)
+			assert.Equal(t, repositoryMock.SaveCalls[0], result)
+		})
+	}
+}
+
+func TestUserService_SetWebhook(t *testing.T) {
+	tests := []struct {
+		name              string
+		url               string
+		findError         error
+		expectedURL       string
+		expectedError     error
+		expectedFindCalls []string
+	}{
+		{
+			name:              "success",
+			url:               "https://partner.example.com/weather",
+			expectedURL:       "https://partner.example.com/weather",
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "empty url disables webhook",
+			url:               "",
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "not https",
+			url:               "ftp://partner.example.com/weather",
+			expectedError:     ErrInvalidWebhookURL,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "plain http",
+			url:               "http://partner.example.com/weather",
+			expectedError:     ErrInvalidWebhookURL,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "relative url",
+			url:               "/weather",
+			expectedError:     ErrInvalidWebhookURL,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "localhost",
+			url:               "https://localhost:8090/user-service/internal",
+			expectedError:     ErrInvalidWebhookURL,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "private address",
+			url:               "https://10.0.0.5/weather",
+			expectedError:     ErrInvalidWebhookURL,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "link-local address",
+			url:               "https://169.254.169.254/latest/meta-data",
+			expectedError:     ErrInvalidWebhookURL,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "loopback ipv6",
+			url:               "https://[::1]/weather",
+			expectedError:     ErrInvalidWebhookURL,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "user not found",
+			url:               "https://partner.example.com/weather",
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindCalls: []string{},
+				FindResult: User{
+					ID:   "USER-1",
+					Name: "Fulano Beltrano",
+					NotificationConfig: NotificationConfig{
+						Enabled: true,
+						Webhook: WebhookNotificationConfig{Enabled: true, URL: "https://old.example.com", Secret: "old"},
+					},
+				},
+				FindError: tt.findError,
+				SaveCalls: []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			secret, err := service.SetWebhook("USER-1", tt.url)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCalls, repositoryMock.FindCalls)
+
+			if tt.expectedError != nil {
+				assert.Empty(t, secret)
+				assert.Len(t, repositoryMock.SaveCalls, 0)
+				return
+			}
+
+			assert.Len(t, repositoryMock.SaveCalls, 1)
+
+			config := repositoryMock.SaveCalls[0].NotificationConfig.Webhook
+
+			if tt.expectedURL == "" {
+				assert.Empty(t, secret)
+				assert.Equal(t, WebhookNotificationConfig{}, config)
+				return
+			}
+
+			assert.Len(t, secret, 64)
+			assert.Equal(t, WebhookNotificationConfig{Enabled: true, URL: tt.expectedURL, Secret: secret}, config)
+		})
+	}
+}
+
+func TestUserService_RegisterPushSubscription(t *testing.T) {
+	const (
+		p256dh = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+		auth   = "BTBZMqHH6r4Tts7J_aSIgg"
+	)
+
+	laptop := PushSubscription{Device: "laptop", Endpoint: "https://push.example.com/laptop", P256dh: p256dh, Auth: auth}
+	phone := PushSubscription{Device: "phone", Endpoint: "https://push.example.com/phone", P256dh: p256dh, Auth: auth}
+
+	tests := []struct {
+		name                  string
+		subscription          PushSubscription
+		findError             error
+		expectedSubscriptions []PushSubscription
+		expectedError         error
+		expectedFindCalls     []string
+	}{
+		{
+			name:                  "new device",
+			subscription:          phone,
+			expectedSubscriptions: []PushSubscription{laptop, phone},
+			expectedFindCalls:     []string{"USER-1"},
+		},
+		{
+			name:                  "device subscribing again",
+			subscription:          PushSubscription{Device: "laptop", Endpoint: "https://push.example.com/laptop-2", P256dh: p256dh, Auth: auth},
+			expectedSubscriptions: []PushSubscription{{Device: "laptop", Endpoint: "https://push.example.com/laptop-2", P256dh: p256dh, Auth: auth}},
+			expectedFindCalls:     []string{"USER-1"},
+		},
+		{
+			name:                  "endpoint under another device name",
+			subscription:          PushSubscription{Device: "notebook", Endpoint: laptop.Endpoint, P256dh: p256dh, Auth: auth},
+			expectedSubscriptions: []PushSubscription{{Device: "notebook", Endpoint: laptop.Endpoint, P256dh: p256dh, Auth: auth}},
+			expectedFindCalls:     []string{"USER-1"},
+		},
+		{
+			name:                  "padded keys",
+			subscription:          PushSubscription{Device: "phone", Endpoint: phone.Endpoint, P256dh: p256dh, Auth: auth + "=="},
+			expectedSubscriptions: []PushSubscription{laptop, {Device: "phone", Endpoint: phone.Endpoint, P256dh: p256dh, Auth: auth + "=="}},
+			expectedFindCalls:     []string{"USER-1"},
+		},
+		{
+			name:              "without device",
+			subscription:      PushSubscription{Endpoint: phone.Endpoint, P256dh: p256dh, Auth: auth},
+			expectedError:     ErrInvalidPushSubscription,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "endpoint not https",
+			subscription:      PushSubscription{Device: "phone", Endpoint: "http://push.example.com/phone", P256dh: p256dh, Auth: auth},
+			expectedError:     ErrInvalidPushSubscription,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "endpoint on a private address",
+			subscription:      PushSubscription{Device: "phone", Endpoint: "https://192.168.0.10/phone", P256dh: p256dh, Auth: auth},
+			expectedError:     ErrInvalidPushSubscription,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "p256dh not a public key",
+			subscription:      PushSubscription{Device: "phone", Endpoint: phone.Endpoint, P256dh: auth, Auth: auth},
+			expectedError:     ErrInvalidPushSubscription,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "auth with wrong length",
+			subscription:      PushSubscription{Device: "phone", Endpoint: phone.Endpoint, P256dh: p256dh, Auth: "c2VjcmV0"},
+			expectedError:     ErrInvalidPushSubscription,
+			expectedFindCalls: []string{},
+		},
+		{
+			name:              "user not found",
+			subscription:      phone,
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindCalls: []string{},
+				FindResult: User{
+					ID:   "USER-1",
+					Name: "Fulano Beltrano",
+					NotificationConfig: NotificationConfig{
+						Enabled: true,
+						Push:    PushNotificationConfig{Enabled: true, Subscriptions: []PushSubscription{laptop}},
+					},
+				},
+				FindError: tt.findError,
+				SaveCalls: []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			err := service.RegisterPushSubscription("USER-1", tt.subscription)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCalls, repositoryMock.FindCalls)
+
+			if tt.expectedError != nil {
+				assert.Len(t, repositoryMock.SaveCalls, 0)
+				return
+			}
+
+			assert.Len(t, repositoryMock.SaveCalls, 1)
+			assert.Equal(t, PushNotificationConfig{Enabled: true, Subscriptions: tt.expectedSubscriptions}, repositoryMock.SaveCalls[0].NotificationConfig.Push)
+		})
+	}
+}
+
+func TestUserService_UnregisterPushSubscription(t *testing.T) {
+	laptop := PushSubscription{Device: "laptop", Endpoint: "https://push.example.com/laptop"}
+	phone := PushSubscription{Device: "phone", Endpoint: "https://push.example.com/phone"}
+
+	tests := []struct {
+		name              string
+		subscriptions     []PushSubscription
+		device            string
+		endpoint          string
+		findError         error
+		expectedConfig    PushNotificationConfig
+		expectedError     error
+		expectedFindCalls []string
+	}{
+		{
+			name:              "success",
+			subscriptions:     []PushSubscription{laptop, phone},
+			device:            "laptop",
+			expectedConfig:    PushNotificationConfig{Enabled: true, Subscriptions: []PushSubscription{phone}},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "last device disables push",
+			subscriptions:     []PushSubscription{laptop},
+			device:            "laptop",
+			expectedConfig:    PushNotificationConfig{Enabled: false, Subscriptions: []PushSubscription{}},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "matching endpoint",
+			subscriptions:     []PushSubscription{laptop, phone},
+			device:            "phone",
+			endpoint:          "https://push.example.com/phone",
+			expectedConfig:    PushNotificationConfig{Enabled: true, Subscriptions: []PushSubscription{laptop}},
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "device subscribed again since",
+			subscriptions:     []PushSubscription{laptop, phone},
+			device:            "phone",
+			endpoint:          "https://push.example.com/expired",
+			expectedError:     ErrPushSubscriptionNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "unknown device",
+			subscriptions:     []PushSubscription{laptop},
+			device:            "phone",
+			expectedError:     ErrPushSubscriptionNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+		{
+			name:              "user not found",
+			device:            "laptop",
+			findError:         ErrUserNotFound,
+			expectedError:     ErrUserNotFound,
+			expectedFindCalls: []string{"USER-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositoryMock := &MockRepository{
+				FindCalls: []string{},
+				FindResult: User{
+					ID:   "USER-1",
+					Name: "Fulano Beltrano",
+					NotificationConfig: NotificationConfig{
+						Enabled: true,
+						Push:    PushNotificationConfig{Enabled: true, Subscriptions: tt.subscriptions},
+					},
+				},
+				FindError: tt.findError,
+				SaveCalls: []*User{},
+			}
+
+			service := NewService(repositoryMock, repositoryMock)
+
+			err := service.UnregisterPushSubscription("USER-1", tt.device, tt.endpoint)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCalls, repositoryMock.FindCalls)
+
+			if tt.expectedError != nil {
+				assert.Len(t, repositoryMock.SaveCalls, 0)
+				return
+			}
+
+			assert.Len(t, repositoryMock.SaveCalls, 1)
+			assert.Equal(t, tt.expectedConfig, repositoryMock.SaveCalls[0].NotificationConfig.Push)
+		})
+	}
+}
@@ -1,17 +1,117 @@
 package user
 
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultLocale is given to users created without a locale
+const DefaultLocale = "pt-BR"
+
+// Locales are the languages notifications can be written in
+var Locales = []string{"pt-BR", "en", "es"}
+
+// DefaultForecastStyle is given to users created without a forecast style
+const DefaultForecastStyle = "simple"
+
+// ForecastStyles are how much of the forecast notifications have: "simple"
+// has the temperatures and wave heights, while "detailed" adds the
+// condition, UV index, wave direction and wind.
+var ForecastStyles = []string{"simple", "detailed"}
+
+// maxDeviceLength is the longest name a device subscribed to pushes can have
+const maxDeviceLength = 64
+
+// e164 is a phone number with its country code and at most 15 digits
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
 type User struct {
 	ID                 string
 	Name               string
+	Locale             string
+	ForecastStyle      string
 	NotificationConfig NotificationConfig
 }
 
+// ParseLocale finds a supported locale regardless of case, so "PT-br" is
+// stored as "pt-BR".
+func ParseLocale(locale string) (string, bool) {
+	for _, supported := range Locales {
+		if strings.EqualFold(supported, locale) {
+			return supported, true
+		}
+	}
+
+	return "", false
+}
+
+// ParsePhone reads a phone number in E.164 format, ignoring the spaces,
+// dashes, dots and parentheses it is usually written with, so
+// "+55 (21) 99999-0000" is stored as "+5521999990000".
+func ParsePhone(phone string) (string, bool) {
+	phone = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(phone)
+
+	if !e164.MatchString(phone) {
+		return "", false
+	}
+
+	return phone, true
+}
+
 type NotificationConfig struct {
-	Enabled bool
-	Web     WebNotificationConfig
+	Enabled  bool
+	Web      WebNotificationConfig
+	Email    EmailNotificationConfig
+	SMS      SMSNotificationConfig
+	Telegram TelegramNotificationConfig
+	Webhook  WebhookNotificationConfig
+	Push     PushNotificationConfig
 }
 
 type WebNotificationConfig struct {
 	Enabled bool
 	Id      string
 }
+
+type EmailNotificationConfig struct {
+	Enabled bool
+	Address string
+}
+
+type SMSNotificationConfig struct {
+	Enabled bool
+	Phone   string
+}
+
+// TelegramNotificationConfig is the chat notifications are sent to. Until a
+// chat is linked, LinkingCode is what the user sends to the bot to link it.
+type TelegramNotificationConfig struct {
+	Enabled     bool
+	ChatID      int64
+	LinkingCode string
+}
+
+// WebhookNotificationConfig is the endpoint notifications are posted to,
+// signed with Secret so the partner can check they came from us
+type WebhookNotificationConfig struct {
+	Enabled bool
+	URL     string
+	Secret  string
+}
+
+// PushNotificationConfig is the browsers notifications are pushed to, with
+// one subscription per device of the user
+type PushNotificationConfig struct {
+	Enabled       bool
+	Subscriptions []PushSubscription
+}
+
+// PushSubscription is what a browser gives when it subscribes to pushes: the
+// endpoint at its push service and the keys of RFC 8291 the payload is
+// encrypted with. Device is how the user tells the browsers apart.
+type PushSubscription struct {
+	Device   string
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
@@ -0,0 +1,109 @@
+package alert
+
+import (
+	"fmt"
+
+	"github.com/fgouvea/weather/weather-service/user"
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+var testCity = weather.City{
+	ID:    "cptec:241",
+	Name:  "Rio de Janeiro",
+	State: "RJ",
+}
+
+type serviceMock struct {
+	saveCalls []Rule
+	saveError error
+
+	deleteCalls []string
+	deleteError error
+
+	findCalls  []string
+	findResult Rule
+	findError  error
+
+	findAllCalls  []string
+	findAllResult []Rule
+	findAllError  error
+
+	// Dates sent, as "ruleID:date"
+	sent          map[string]bool
+	wasSentError  error
+	markSentCalls []string
+	markSentError error
+
+	validateCalls  []weather.CityQuery
+	validateResult weather.City
+	validateError  error
+
+	getForecastsCalls  []int
+	getForecastsResult weather.CityForecast
+	getForecastsWaves  []weather.CityWaveForecast
+	getForecastsError  error
+
+	findUserResult user.User
+	findUserError  error
+
+	notifyCalls    []string
+	notifyPayloads []weather.Payload
+	notifyError    error
+}
+
+var _ RuleSaver = (*serviceMock)(nil)
+var _ RuleFinder = (*serviceMock)(nil)
+var _ SentTracker = (*serviceMock)(nil)
+var _ Validator = (*serviceMock)(nil)
+var _ Forecaster = (*serviceMock)(nil)
+var _ weather.UserFinder = (*serviceMock)(nil)
+var _ weather.Notifier = (*serviceMock)(nil)
+
+func (m *serviceMock) Save(rule Rule) error {
+	m.saveCalls = append(m.saveCalls, rule)
+	return m.saveError
+}
+
+func (m *serviceMock) Delete(id string) error {
+	m.deleteCalls = append(m.deleteCalls, id)
+	return m.deleteError
+}
+
+func (m *serviceMock) Find(id string) (Rule, error) {
+	m.findCalls = append(m.findCalls, id)
+	return m.findResult, m.findError
+}
+
+func (m *serviceMock) FindAll(userID, cityID string) ([]Rule, error) {
+	m.findAllCalls = append(m.findAllCalls, fmt.Sprintf("%s:%s", userID, cityID))
+	return m.findAllResult, m.findAllError
+}
+
+func (m *serviceMock) WasSent(ruleID, date string) (bool, error) {
+	return m.sent[fmt.Sprintf("%s:%s", ruleID, date)], m.wasSentError
+}
+
+func (m *serviceMock) MarkSent(ruleID, date string) error {
+	m.markSentCalls = append(m.markSentCalls, fmt.Sprintf("%s:%s", ruleID, date))
+	return m.markSentError
+}
+
+func (m *serviceMock) Validate(userID string, city weather.CityQuery) (weather.City, error) {
+	m.validateCalls = append(m.validateCalls, city)
+	return m.validateResult, m.validateError
+}
+
+func (m *serviceMock) GetForecasts(city weather.CityQuery, days int) (weather.CityForecast, []weather.CityWaveForecast, error) {
+	m.getForecastsCalls = append(m.getForecastsCalls, days)
+	return m.getForecastsResult, m.getForecastsWaves, m.getForecastsError
+}
+
+func (m *serviceMock) FindUser(id string) (user.User, error) {
+	return m.findUserResult, m.findUserError
+}
+
+func (m *serviceMock) Notify(userID string, notification weather.Notification) error {
+	m.notifyCalls = append(m.notifyCalls, notification.Text)
+	m.notifyPayloads = append(m.notifyPayloads, notification.Payload)
+	return m.notifyError
+}
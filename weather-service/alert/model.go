@@ -0,0 +1,62 @@
+package alert
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRuleNotFound     = errors.New("alert rule not found")
+	ErrInvalidRule      = errors.New("invalid alert rule")
+	ErrFailedToSave     = errors.New("failed to save alert rule")
+	ErrFailedToEvaluate = errors.New("failed to evaluate alert rules")
+)
+
+// DefaultDays looks at today and tomorrow
+const DefaultDays = 2
+
+// Rule describes the weather a user wants to be warned about in a city. Every
+// criterion set must hold on the same day for the rule to match, so separate
+// alerts take separate rules. Days is how many forecast days, starting today,
+// are checked.
+type Rule struct {
+	ID       string
+	UserID   string
+	CityID   string
+	CityName string
+	State    string
+
+	// CPTEC condition codes, such as "c" for rain. Any of them matches.
+	Conditions []string
+	// Matches when the minimum temperature drops below it, in °C
+	MinTemperature *int
+	// Matches when the maximum temperature goes above it, in °C
+	MaxTemperature *int
+	// Matches when the UV index reaches it
+	IUV *float64
+	// Matches when the waves reach it at any time of the day, in meters
+	WaveHeight *float64
+	// Matches when the wind at sea reaches it at any time of the day, in the
+	// unit of the wave forecast
+	Wind *float64
+
+	Days      int
+	CreatedAt time.Time
+}
+
+// Message is given to the "alert" template, with a match per date
+type Message struct {
+	CityName string
+	Matches  []Match
+}
+
+// Match is a day on which a rule matched
+type Match struct {
+	Rule          Rule
+	Date          string
+	Weather       string
+	Min           int
+	Max           int
+	MaxWaveHeight float64
+	HasWaves      bool
+}
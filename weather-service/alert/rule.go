@@ -0,0 +1,85 @@
+package alert
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/fgouvea/weather/weather-service/cptec"
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+// Validate checks the criteria of the rule. Days must have been defaulted
+// already.
+func (r Rule) Validate() error {
+	if len(r.Conditions) == 0 && r.MinTemperature == nil && r.MaxTemperature == nil && r.IUV == nil && r.WaveHeight == nil && r.Wind == nil {
+		return fmt.Errorf("%w: at least one criterion is required", ErrInvalidRule)
+	}
+
+	for _, code := range r.Conditions {
+		if _, exists := cptec.WeatherName[code]; !exists {
+			return fmt.Errorf("%w: unknown condition code: %s", ErrInvalidRule, code)
+		}
+	}
+
+	if r.Days < 1 || r.Days > weather.MaxForecastDays {
+		return fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidRule, weather.MaxForecastDays)
+	}
+
+	return nil
+}
+
+func (r Rule) usesWaves() bool {
+	return r.WaveHeight != nil || r.Wind != nil
+}
+
+// match checks the rule against the forecast of a day, along with the wave
+// forecast for the same day, if any. Wave criteria never match without one.
+func (r Rule) match(forecast weather.Forecast, waves *weather.CityWaveForecast) (Match, bool) {
+	if len(r.Conditions) > 0 && !slices.Contains(r.Conditions, forecast.Code) {
+		return Match{}, false
+	}
+
+	if r.MinTemperature != nil && forecast.MinTemperature >= *r.MinTemperature {
+		return Match{}, false
+	}
+
+	if r.MaxTemperature != nil && forecast.MaxTemperature <= *r.MaxTemperature {
+		return Match{}, false
+	}
+
+	if r.IUV != nil && forecast.IUV < *r.IUV {
+		return Match{}, false
+	}
+
+	match := Match{
+		Rule:    r,
+		Date:    forecast.Date,
+		Weather: forecast.Weather,
+		Min:     forecast.MinTemperature,
+		Max:     forecast.MaxTemperature,
+	}
+
+	if !r.usesWaves() {
+		return match, true
+	}
+
+	if waves == nil {
+		return Match{}, false
+	}
+
+	maxHeight := max(waves.Morning.Height, waves.Afternoon.Height, waves.Evening.Height)
+	maxWind := max(waves.Morning.Wind, waves.Afternoon.Wind, waves.Evening.Wind)
+
+	if r.WaveHeight != nil && maxHeight < *r.WaveHeight {
+		return Match{}, false
+	}
+
+	if r.Wind != nil && maxWind < *r.Wind {
+		return Match{}, false
+	}
+
+	match.HasWaves = true
+	match.MaxWaveHeight = maxHeight
+
+	return match, true
+}
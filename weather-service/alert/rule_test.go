@@ -0,0 +1,181 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		rule          Rule
+		expectedError error
+	}{
+		{
+			name: "conditions",
+			rule: Rule{Conditions: []string{"c", "pc"}, Days: 2},
+		},
+		{
+			name: "thresholds",
+			rule: Rule{MaxTemperature: intPtr(35), WaveHeight: floatPtr(2), Days: 7},
+		},
+		{
+			name:          "no criteria",
+			rule:          Rule{Days: 2},
+			expectedError: ErrInvalidRule,
+		},
+		{
+			name:          "unknown condition",
+			rule:          Rule{Conditions: []string{"chuva"}, Days: 2},
+			expectedError: ErrInvalidRule,
+		},
+		{
+			name:          "too many days",
+			rule:          Rule{Conditions: []string{"c"}, Days: 8},
+			expectedError: ErrInvalidRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.rule.Validate(), tt.expectedError)
+		})
+	}
+}
+
+func TestRule_Match(t *testing.T) {
+	day := weather.Forecast{Date: "2025-02-07", Code: "c", Weather: "Chuva", MinTemperature: 18, MaxTemperature: 36, IUV: 11}
+
+	waves := &weather.CityWaveForecast{
+		Date:      "2025-02-07",
+		Morning:   weather.WaveForecast{Height: 1.2, Wind: 4},
+		Afternoon: weather.WaveForecast{Height: 2.3, Wind: 7},
+		Evening:   weather.WaveForecast{Height: 1.8, Wind: 5},
+	}
+
+	tests := []struct {
+		name          string
+		rule          Rule
+		code          *string
+		weather       string
+		waves         *weather.CityWaveForecast
+		expectedMatch bool
+		expectedWaves bool
+	}{
+		{
+			name:          "condition",
+			rule:          Rule{Conditions: []string{"ci", "c"}},
+			expectedMatch: true,
+		},
+		{
+			name:          "other condition",
+			rule:          Rule{Conditions: []string{"cl"}},
+			expectedMatch: false,
+		},
+		{
+			name:          "condition named otherwise by the provider",
+			rule:          Rule{Conditions: []string{"c"}},
+			weather:       "Rain",
+			expectedMatch: true,
+		},
+		{
+			name:          "unknown condition",
+			rule:          Rule{Conditions: []string{"c"}},
+			code:          new(string),
+			expectedMatch: false,
+		},
+		{
+			name:          "max temperature above",
+			rule:          Rule{MaxTemperature: intPtr(35)},
+			expectedMatch: true,
+		},
+		{
+			name:          "max temperature not above",
+			rule:          Rule{MaxTemperature: intPtr(36)},
+			expectedMatch: false,
+		},
+		{
+			name:          "min temperature below",
+			rule:          Rule{MinTemperature: intPtr(19)},
+			expectedMatch: true,
+		},
+		{
+			name:          "min temperature not below",
+			rule:          Rule{MinTemperature: intPtr(18)},
+			expectedMatch: false,
+		},
+		{
+			name:          "uv index reached",
+			rule:          Rule{IUV: floatPtr(11)},
+			expectedMatch: true,
+		},
+		{
+			name:          "waves reached at any time",
+			rule:          Rule{WaveHeight: floatPtr(2)},
+			waves:         waves,
+			expectedMatch: true,
+			expectedWaves: true,
+		},
+		{
+			name:          "waves not reached",
+			rule:          Rule{WaveHeight: floatPtr(2.5)},
+			waves:         waves,
+			expectedMatch: false,
+		},
+		{
+			name:          "wind reached",
+			rule:          Rule{Wind: floatPtr(7)},
+			waves:         waves,
+			expectedMatch: true,
+			expectedWaves: true,
+		},
+		{
+			name:          "no wave forecast",
+			rule:          Rule{WaveHeight: floatPtr(0)},
+			expectedMatch: false,
+		},
+		{
+			name:          "every criterion must hold",
+			rule:          Rule{Conditions: []string{"c"}, MaxTemperature: intPtr(35), WaveHeight: floatPtr(3)},
+			waves:         waves,
+			expectedMatch: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := day
+
+			if tt.code != nil {
+				forecast.Code = *tt.code
+			}
+
+			if tt.weather != "" {
+				forecast.Weather = tt.weather
+			}
+
+			match, matched := tt.rule.match(forecast, tt.waves)
+
+			assert.Equal(t, tt.expectedMatch, matched)
+
+			if tt.expectedMatch {
+				assert.Equal(t, "2025-02-07", match.Date)
+				assert.Equal(t, tt.expectedWaves, match.HasWaves)
+			}
+
+			if tt.expectedWaves {
+				assert.Equal(t, 2.3, match.MaxWaveHeight)
+			}
+		})
+	}
+}
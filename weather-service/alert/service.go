@@ -0,0 +1,265 @@
+package alert
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/google/uuid"
+)
+
+// Validator checks a user and the city they asked for, returning the city
+// found.
+type Validator interface {
+	Validate(userID string, city weather.CityQuery) (weather.City, error)
+}
+
+type Forecaster interface {
+	GetForecasts(city weather.CityQuery, days int) (weather.CityForecast, []weather.CityWaveForecast, error)
+}
+
+type RuleSaver interface {
+	Save(rule Rule) error
+	Delete(id string) error
+}
+
+type RuleFinder interface {
+	Find(id string) (Rule, error)
+	// FindAll lists the rules of a user, optionally only those for a city
+	FindAll(userID, cityID string) ([]Rule, error)
+}
+
+// SentTracker remembers the forecast dates each rule already alerted about.
+type SentTracker interface {
+	WasSent(ruleID, date string) (bool, error)
+	MarkSent(ruleID, date string) error
+}
+
+type Service struct {
+	Saver      RuleSaver
+	Finder     RuleFinder
+	Tracker    SentTracker
+	Validator  Validator
+	Forecaster Forecaster
+	Users      weather.UserFinder
+	Renderer   weather.Renderer
+	Notifier   weather.Notifier
+}
+
+func NewService(saver RuleSaver, finder RuleFinder, tracker SentTracker, validator Validator, forecaster Forecaster, users weather.UserFinder, renderer weather.Renderer, notifier weather.Notifier) *Service {
+	return &Service{
+		Saver:      saver,
+		Finder:     finder,
+		Tracker:    tracker,
+		Validator:  validator,
+		Forecaster: forecaster,
+		Users:      users,
+		Renderer:   renderer,
+		Notifier:   notifier,
+	}
+}
+
+func (s *Service) Create(userID string, city weather.CityQuery, rule Rule) (Rule, error) {
+	if rule.Days == 0 {
+		rule.Days = DefaultDays
+	}
+
+	err := rule.Validate()
+
+	if err != nil {
+		return Rule{}, err
+	}
+
+	resolved, err := s.Validator.Validate(userID, city)
+
+	if err != nil {
+		return Rule{}, err
+	}
+
+	rule.ID = fmt.Sprintf("ALERT-%s", uuid.New())
+	rule.UserID = userID
+	rule.CityID = resolved.ID
+	rule.CityName = resolved.Name
+	rule.State = resolved.State
+	rule.CreatedAt = time.Now()
+
+	err = s.Saver.Save(rule)
+
+	if err != nil {
+		return Rule{}, fmt.Errorf("%w: %w", ErrFailedToSave, err)
+	}
+
+	return rule, nil
+}
+
+func (s *Service) Find(id string) (Rule, error) {
+	return s.Finder.Find(id)
+}
+
+func (s *Service) List(userID string) ([]Rule, error) {
+	return s.Finder.FindAll(userID, "")
+}
+
+func (s *Service) Delete(id string) error {
+	_, err := s.Finder.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Saver.Delete(id)
+}
+
+// Evaluate checks the rules of a user for a city against its forecast,
+// sending a single notification with every day that matched and was not
+// alerted about yet.
+func (s *Service) Evaluate(userID, cityID string) error {
+	rules, err := s.Finder.FindAll(userID, cityID)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToEvaluate, err)
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	days := 0
+
+	for _, rule := range rules {
+		days = max(days, rule.Days)
+	}
+
+	// The name and state find the city on another provider if the one of the
+	// ID is down
+	city := weather.CityQuery{ID: cityID, Name: rules[0].CityName, State: rules[0].State}
+
+	forecast, waveForecasts, err := s.Forecaster.GetForecasts(city, days)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToEvaluate, err)
+	}
+
+	matches, err := s.match(rules, forecast, waveForecasts)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToEvaluate, err)
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	notification, err := s.buildNotification(userID, rules[0], matches, forecast, waveForecasts)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToEvaluate, err)
+	}
+
+	err = s.Notifier.Notify(userID, notification)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToEvaluate, err)
+	}
+
+	for _, match := range matches {
+		err = s.Tracker.MarkSent(match.Rule.ID, match.Date)
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToEvaluate, err)
+		}
+	}
+
+	return nil
+}
+
+// match finds the days each rule matches on, leaving out those already sent
+func (s *Service) match(rules []Rule, forecast weather.CityForecast, waveForecasts []weather.CityWaveForecast) ([]Match, error) {
+	waves := map[string]*weather.CityWaveForecast{}
+
+	for i := range waveForecasts {
+		waves[waveForecasts[i].Date] = &waveForecasts[i]
+	}
+
+	var matches []Match
+
+	for _, rule := range rules {
+		for _, day := range forecast.Forecast[:min(rule.Days, len(forecast.Forecast))] {
+			match, matched := rule.match(day, waves[day.Date])
+
+			if !matched {
+				continue
+			}
+
+			sent, err := s.Tracker.WasSent(rule.ID, day.Date)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if !sent {
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// buildNotification lists each date once, even when several rules matched on
+// it, in the locale of the user. The rule gives the city, shared by all of
+// them.
+func (s *Service) buildNotification(userID string, rule Rule, matches []Match, forecast weather.CityForecast, waveForecasts []weather.CityWaveForecast) (weather.Notification, error) {
+	var dates []string
+	byDate := map[string]Match{}
+
+	for _, match := range matches {
+		current, exists := byDate[match.Date]
+
+		if !exists {
+			dates = append(dates, match.Date)
+		}
+
+		if !exists || (match.HasWaves && !current.HasWaves) {
+			byDate[match.Date] = match
+		}
+	}
+
+	slices.Sort(dates)
+
+	data := Message{CityName: rule.CityName}
+
+	for _, date := range dates {
+		data.Matches = append(data.Matches, byDate[date])
+	}
+
+	userEntry, err := s.Users.FindUser(userID)
+
+	if err != nil {
+		return weather.Notification{}, err
+	}
+
+	content, err := s.Renderer.Render(userEntry.Locale, "alert", data)
+
+	if err != nil {
+		return weather.Notification{}, err
+	}
+
+	var days []weather.PayloadDay
+
+	for _, day := range weather.NewPayloadDays(forecast.Forecast, waveForecasts) {
+		if _, matched := byDate[day.Forecast.Date]; matched {
+			days = append(days, day)
+		}
+	}
+
+	return weather.Notification{
+		Content: content,
+		Payload: weather.Payload{
+			Type: "alert",
+			City: weather.City{ID: rule.CityID, Name: rule.CityName, State: rule.State},
+			Days: days,
+		},
+	}, nil
+}
@@ -0,0 +1,255 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fgouvea/weather/weather-service/message"
+	"github.com/fgouvea/weather/weather-service/user"
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRenderer, _ = message.NewEmbeddedRenderer()
+
+var testForecast = weather.CityForecast{
+	Forecast: []weather.Forecast{
+		{Date: "2025-02-07", Code: "cl", Weather: "Céu Claro", MinTemperature: 22, MaxTemperature: 31},
+		{Date: "2025-02-08", Code: "c", Weather: "Chuva", MinTemperature: 21, MaxTemperature: 36},
+		{Date: "2025-02-09", Code: "c", Weather: "Chuva", MinTemperature: 20, MaxTemperature: 29},
+	},
+}
+
+var testWaves = []weather.CityWaveForecast{
+	{Date: "2025-02-08", Afternoon: weather.WaveForecast{Height: 2.4}},
+}
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name               string
+		rule               Rule
+		validateError      error
+		saveError          error
+		expectedError      error
+		expectedValidates  int
+		expectedSaveCalls  int
+		expectedDays       int
+		expectedConditions []string
+	}{
+		{
+			name:              "success",
+			rule:              Rule{Conditions: []string{"c"}},
+			expectedValidates: 1,
+			expectedSaveCalls: 1,
+			expectedDays:      DefaultDays,
+		},
+		{
+			name:              "invalid rule",
+			rule:              Rule{},
+			expectedError:     ErrInvalidRule,
+			expectedValidates: 0,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "error validating",
+			rule:              Rule{Conditions: []string{"c"}},
+			validateError:     user.ErrUserNotFound,
+			expectedError:     user.ErrUserNotFound,
+			expectedValidates: 1,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "error saving",
+			rule:              Rule{Conditions: []string{"c"}, Days: 5},
+			saveError:         errors.New("failed to connect to db"),
+			expectedError:     ErrFailedToSave,
+			expectedValidates: 1,
+			expectedSaveCalls: 1,
+			expectedDays:      5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{validateResult: testCity, validateError: tt.validateError, saveError: tt.saveError}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+			result, err := service.Create("USER-ID", weather.CityQuery{Name: "rio de janeiro"}, tt.rule)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedValidates, len(mock.validateCalls))
+			assert.Equal(t, tt.expectedSaveCalls, len(mock.saveCalls))
+
+			for _, saved := range mock.saveCalls {
+				assert.Equal(t, "USER-ID", saved.UserID)
+				assert.Equal(t, "cptec:241", saved.CityID)
+				assert.Equal(t, "Rio de Janeiro", saved.CityName)
+				assert.Equal(t, "RJ", saved.State)
+				assert.Equal(t, tt.expectedDays, saved.Days)
+			}
+
+			if tt.expectedError == nil {
+				assert.Equal(t, mock.saveCalls[0], result)
+			} else {
+				assert.Equal(t, Rule{}, result)
+			}
+		})
+	}
+}
+
+func TestService_Evaluate(t *testing.T) {
+	rain := Rule{ID: "ALERT-1", CityName: "Rio de Janeiro", Conditions: []string{"c"}, Days: 2}
+	heat := Rule{ID: "ALERT-2", CityName: "Rio de Janeiro", MaxTemperature: intPtr(35), Days: 3}
+	waves := Rule{ID: "ALERT-3", CityName: "Rio de Janeiro", WaveHeight: floatPtr(2), Days: 3}
+
+	tests := []struct {
+		name                  string
+		rules                 []Rule
+		sent                  map[string]bool
+		locale                string
+		findUserError         error
+		forecastError         error
+		notifyError           error
+		expectedError         error
+		expectedForecastCalls []int
+		expectedNotifications []string
+		expectedMarkSent      []string
+	}{
+		{
+			name:                  "no rules",
+			rules:                 nil,
+			expectedForecastCalls: nil,
+		},
+		{
+			name:                  "rule matches",
+			rules:                 []Rule{rain},
+			expectedForecastCalls: []int{2},
+			expectedNotifications: []string{"Alerta do tempo para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 36°C"},
+			expectedMarkSent:      []string{"ALERT-1:2025-02-08"},
+		},
+		{
+			name:                  "several rules on the same day",
+			rules:                 []Rule{rain, heat, waves},
+			expectedForecastCalls: []int{3},
+			expectedNotifications: []string{"Alerta do tempo para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 36°C, ondas de até 2.40m"},
+			expectedMarkSent:      []string{"ALERT-1:2025-02-08", "ALERT-2:2025-02-08", "ALERT-3:2025-02-08"},
+		},
+		{
+			name:                  "only days within the rule",
+			rules:                 []Rule{{ID: "ALERT-4", CityName: "Rio de Janeiro", Conditions: []string{"c"}, Days: 3}},
+			expectedForecastCalls: []int{3},
+			expectedNotifications: []string{"Alerta do tempo para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 36°C\n09/02/2025: Chuva, 20 - 29°C"},
+			expectedMarkSent:      []string{"ALERT-4:2025-02-08", "ALERT-4:2025-02-09"},
+		},
+		{
+			name:                  "already sent",
+			rules:                 []Rule{rain},
+			sent:                  map[string]bool{"ALERT-1:2025-02-08": true},
+			expectedForecastCalls: []int{2},
+		},
+		{
+			name:                  "nothing matches",
+			rules:                 []Rule{{ID: "ALERT-5", Conditions: []string{"ne"}, Days: 3}},
+			expectedForecastCalls: []int{3},
+		},
+		{
+			name:                  "error fetching forecast",
+			rules:                 []Rule{rain},
+			forecastError:         weather.ErrProviderUnavailable,
+			expectedError:         weather.ErrProviderUnavailable,
+			expectedForecastCalls: []int{2},
+		},
+		{
+			name:                  "error notifying is not marked as sent",
+			rules:                 []Rule{rain},
+			notifyError:           errors.New("broker down"),
+			expectedError:         ErrFailedToEvaluate,
+			expectedForecastCalls: []int{2},
+			expectedNotifications: []string{"Alerta do tempo para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 36°C"},
+		},
+		{
+			name:                  "in the locale of the user",
+			rules:                 []Rule{rain, waves},
+			locale:                "en",
+			expectedForecastCalls: []int{3},
+			expectedNotifications: []string{"Weather alert for Rio de Janeiro\n\n02/08/2025: Rain, 21 - 36°C, waves up to 2.40m"},
+			expectedMarkSent:      []string{"ALERT-1:2025-02-08", "ALERT-3:2025-02-08"},
+		},
+		{
+			name:                  "error finding user",
+			rules:                 []Rule{rain},
+			findUserError:         user.ErrUserNotFound,
+			expectedError:         user.ErrUserNotFound,
+			expectedForecastCalls: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				findAllResult:      tt.rules,
+				sent:               tt.sent,
+				findUserResult:     user.User{ID: "USER-ID", Locale: tt.locale},
+				findUserError:      tt.findUserError,
+				getForecastsResult: testForecast,
+				getForecastsWaves:  testWaves,
+				getForecastsError:  tt.forecastError,
+				notifyError:        tt.notifyError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+			err := service.Evaluate("USER-ID", "cptec:241")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, []string{"USER-ID:cptec:241"}, mock.findAllCalls)
+			assert.Equal(t, tt.expectedForecastCalls, mock.getForecastsCalls)
+			assert.Equal(t, tt.expectedNotifications, mock.notifyCalls)
+			assert.Equal(t, tt.expectedMarkSent, mock.markSentCalls)
+		})
+	}
+}
+
+func TestService_Evaluate_Payload(t *testing.T) {
+	rule := Rule{ID: "ALERT-1", CityID: "cptec:241", CityName: "Rio de Janeiro", State: "RJ", Conditions: []string{"c"}, Days: 3}
+
+	mock := &serviceMock{
+		findAllResult:      []Rule{rule},
+		getForecastsResult: testForecast,
+		getForecastsWaves:  testWaves,
+	}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+	err := service.Evaluate("USER-ID", "cptec:241")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []weather.Payload{{
+		Type: "alert",
+		City: weather.City{ID: "cptec:241", Name: "Rio de Janeiro", State: "RJ"},
+		Days: []weather.PayloadDay{
+			{Forecast: testForecast.Forecast[1], Waves: &testWaves[0]},
+			{Forecast: testForecast.Forecast[2]},
+		},
+	}}, mock.notifyPayloads)
+}
+
+func TestService_Delete(t *testing.T) {
+	mock := &serviceMock{findError: ErrRuleNotFound}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+	err := service.Delete("ALERT-1")
+
+	assert.ErrorIs(t, err, ErrRuleNotFound)
+	assert.Nil(t, mock.deleteCalls)
+
+	mock.findError = nil
+
+	err = service.Delete("ALERT-1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ALERT-1"}, mock.deleteCalls)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fgouvea/weather/weather-service/alert"
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type AlertManager interface {
+	Create(userID string, city weather.CityQuery, rule alert.Rule) (alert.Rule, error)
+	Find(id string) (alert.Rule, error)
+	List(userID string) ([]alert.Rule, error)
+	Delete(id string) error
+}
+
+type AlertHandler struct {
+	Alerts AlertManager
+	Logger *zap.Logger
+}
+
+func (h *AlertHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var body AlertRuleRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, h.Logger, err)
+		return
+	}
+
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, h.Logger, errs)
+		return
+	}
+
+	coordinates, err := buildCoordinates(body.Latitude, body.Longitude)
+
+	if err != nil {
+		writeError(w, h.Logger, "error reading coordinates", err)
+		return
+	}
+
+	city := weather.CityQuery{ID: body.CityID, Name: body.City, State: body.State, Coordinates: coordinates}
+
+	result, err := h.Alerts.Create(body.UserID, city, buildAlertRule(body))
+
+	if err != nil {
+		writeError(w, h.Logger, "error creating alert rule", err, zap.String("userID", body.UserID), zap.String("city", body.City))
+		return
+	}
+
+	h.Logger.Info("alert rule created", zap.String("ruleID", result.ID), zap.String("userID", body.UserID), zap.String("cityID", result.CityID))
+	writeJSON(w, h.Logger, http.StatusCreated, buildAlertRuleTO(result))
+}
+
+func (h *AlertHandler) Find(w http.ResponseWriter, r *http.Request) {
+	ruleID := chi.URLParam(r, "ruleID")
+
+	result, err := h.Alerts.Find(ruleID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error finding alert rule", err, zap.String("ruleID", ruleID))
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, buildAlertRuleTO(result))
+}
+
+func (h *AlertHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("userId")
+
+	if errs := validateRequired(nil, "userId", userID); len(errs) > 0 {
+		writeInvalid(w, h.Logger, errs)
+		return
+	}
+
+	result, err := h.Alerts.List(userID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error listing alert rules", err, zap.String("userID", userID))
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, buildAlertRuleListTO(result))
+}
+
+func (h *AlertHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ruleID := chi.URLParam(r, "ruleID")
+
+	err := h.Alerts.Delete(ruleID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error deleting alert rule", err, zap.String("ruleID", ruleID))
+		return
+	}
+
+	h.Logger.Info("alert rule deleted", zap.String("ruleID", ruleID))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"go.uber.org/zap"
+)
+
+type CitySearcher interface {
+	SearchCities(name string) ([]weather.City, error)
+}
+
+// CityHandler lists the cities matching a name, so clients can let users
+// pick one and use its ID from then on.
+type CityHandler struct {
+	Searcher CitySearcher
+	Logger   *zap.Logger
+}
+
+func (h *CityHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+
+	if errs := validateRequired(nil, "q", query); len(errs) > 0 {
+		writeInvalid(w, h.Logger, errs)
+		return
+	}
+
+	cities, err := h.Searcher.SearchCities(query)
+
+	if err != nil {
+		writeError(w, h.Logger, "error searching cities", err, zap.String("query", query))
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, buildCityListTO(cities))
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"go.uber.org/zap"
+)
+
+type ForecastFinder interface {
+	FindForecast(query weather.CityQuery, days int) (weather.City, weather.CityForecast, error)
+	FindWaveForecast(query weather.CityQuery, day int) (weather.City, weather.CityWaveForecast, error)
+}
+
+// ForecastHandler answers forecast queries without notifying anyone.
+// Responses may be cached by clients for as long as the forecasts are cached
+// here, and are revalidated with an ETag that changes whenever the response
+// does, as when the provider revises the forecast during the day.
+type ForecastHandler struct {
+	Finder         ForecastFinder
+	ForecastMaxAge time.Duration
+	WaveMaxAge     time.Duration
+	Logger         *zap.Logger
+}
+
+func (h *ForecastHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	query, err := buildCityQuery(params)
+
+	days := 0
+
+	if err == nil && params.Has("days") {
+		days, err = parseIntParam(params, "days")
+	}
+
+	var city weather.City
+	var forecast weather.CityForecast
+
+	if err == nil {
+		city, forecast, err = h.Finder.FindForecast(query, days)
+	}
+
+	if err != nil {
+		writeError(w, h.Logger, "error finding forecast", err, zap.String("city", query.Name), zap.String("state", query.State), zap.String("cityID", query.ID))
+		return
+	}
+
+	h.writeCached(w, r, h.ForecastMaxAge, buildForecastTO(city, forecast))
+}
+
+func (h *ForecastHandler) Waves(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	query, err := buildCityQuery(params)
+
+	day := 0
+
+	if err == nil && params.Has("day") {
+		day, err = parseIntParam(params, "day")
+	}
+
+	var city weather.City
+	var waves weather.CityWaveForecast
+
+	if err == nil {
+		city, waves, err = h.Finder.FindWaveForecast(query, day)
+	}
+
+	if err != nil {
+		writeError(w, h.Logger, "error finding forecast", err, zap.String("city", query.Name), zap.String("state", query.State), zap.String("cityID", query.ID))
+		return
+	}
+
+	h.writeCached(w, r, h.WaveMaxAge, buildWaveForecastTO(city, waves))
+}
+
+// writeCached answers with 304 Not Modified when the client already has the
+// response, identified by the hash of its body
+func (h *ForecastHandler) writeCached(w http.ResponseWriter, r *http.Request, maxAge time.Duration, body any) {
+	responseBody, err := json.Marshal(body)
+
+	if err != nil {
+		h.Logger.Error("error writing response", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	hash := fnv.New64a()
+	hash.Write(responseBody)
+	etag := fmt.Sprintf(`"%x"`, hash.Sum64())
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+
+	if matchesETag(r.Header.Values("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseBody)
+}
+
+// matchesETag tells whether the If-None-Match headers match the ETag, per RFC
+// 9110: they hold comma separated lists of tags, or "*" for any, compared
+// weakly, so W/"x" matches "x"
+func matchesETag(headers []string, etag string) bool {
+	for _, header := range headers {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// buildCityQuery reads a city from the query string, by cityId, lat and lon,
+// or city and state, as in the request bodies
+func buildCityQuery(params url.Values) (weather.CityQuery, error) {
+	latitude, err := parseFloatParam(params, "lat")
+
+	if err != nil {
+		return weather.CityQuery{}, err
+	}
+
+	longitude, err := parseFloatParam(params, "lon")
+
+	if err != nil {
+		return weather.CityQuery{}, err
+	}
+
+	coordinates, err := buildCoordinates(latitude, longitude)
+
+	if err != nil {
+		return weather.CityQuery{}, err
+	}
+
+	return weather.CityQuery{
+		ID:          params.Get("cityId"),
+		Name:        params.Get("city"),
+		State:       params.Get("state"),
+		Coordinates: coordinates,
+	}, nil
+}
+
+func parseIntParam(params url.Values, name string) (int, error) {
+	value, err := strconv.Atoi(params.Get(name))
+
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s is not a number", weather.ErrInvalidForecastDays, name)
+	}
+
+	return value, nil
+}
+
+// parseFloatParam reads an optional coordinate
+func parseFloatParam(params url.Values, name string) (*float64, error) {
+	if !params.Has(name) {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(params.Get(name), 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not a number", weather.ErrInvalidCity, name)
+	}
+
+	return &value, nil
+}
@@ -1,12 +1,363 @@
 package api
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/alert"
+	"github.com/fgouvea/weather/weather-service/revision"
+	"github.com/fgouvea/weather/weather-service/schedule"
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
 type NotifyUserRequest struct {
-	UserID string `json:"userId"`
-	City   string `json:"city"`
+	UserID    string   `json:"userId"`
+	CityID    string   `json:"cityId"`
+	City      string   `json:"city"`
+	State     string   `json:"state"`
+	Latitude  *float64 `json:"lat"`
+	Longitude *float64 `json:"lon"`
+	Days      int      `json:"days"`
+	// Style overrides the forecast style chosen by the user
+	Style string `json:"style"`
+	// Channel is where the notification is sent, every channel enabled by the
+	// user when empty
+	Channel string `json:"channel"`
 }
 
 type ScheduleRequest struct {
-	UserID string `json:"userId"`
-	City   string `json:"city"`
-	Time   string `json:"time"`
+	UserID     string   `json:"userId"`
+	CityID     string   `json:"cityId"`
+	City       string   `json:"city"`
+	State      string   `json:"state"`
+	Latitude   *float64 `json:"lat"`
+	Longitude  *float64 `json:"lon"`
+	Time       string   `json:"time"`
+	Recurrence string   `json:"recurrence"`
+	Timezone   string   `json:"timezone"`
+	Days       int      `json:"days"`
+	AlertsOnly bool     `json:"alertsOnly"`
+}
+
+type UpdateScheduleRequest struct {
+	CityID     *string  `json:"cityId"`
+	City       *string  `json:"city"`
+	State      *string  `json:"state"`
+	Latitude   *float64 `json:"lat"`
+	Longitude  *float64 `json:"lon"`
+	Time       *string  `json:"time"`
+	Days       *int     `json:"days"`
+	AlertsOnly *bool    `json:"alertsOnly"`
+}
+
+type ScheduleTO struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"userId"`
+	CityID     string   `json:"cityId,omitempty"`
+	City       string   `json:"city,omitempty"`
+	State      string   `json:"state,omitempty"`
+	Latitude   *float64 `json:"lat,omitempty"`
+	Longitude  *float64 `json:"lon,omitempty"`
+	Status     string   `json:"status"`
+	Time       string   `json:"time"`
+	Recurrence string   `json:"recurrence,omitempty"`
+	Timezone   string   `json:"timezone,omitempty"`
+	Days       int      `json:"days,omitempty"`
+	AlertsOnly bool     `json:"alertsOnly,omitempty"`
+	Attempts   int      `json:"attempts"`
+	Error      string   `json:"error,omitempty"`
+}
+
+type ScheduleListTO struct {
+	Schedules []ScheduleTO `json:"schedules"`
+	Page      int          `json:"page"`
+	PageSize  int          `json:"pageSize"`
+	Total     int          `json:"total"`
+}
+
+type AlertRuleRequest struct {
+	UserID         string   `json:"userId"`
+	CityID         string   `json:"cityId"`
+	City           string   `json:"city"`
+	State          string   `json:"state"`
+	Latitude       *float64 `json:"lat"`
+	Longitude      *float64 `json:"lon"`
+	Conditions     []string `json:"conditions"`
+	MinTemperature *int     `json:"minTemperature"`
+	MaxTemperature *int     `json:"maxTemperature"`
+	IUV            *float64 `json:"iuv"`
+	WaveHeight     *float64 `json:"waveHeight"`
+	Wind           *float64 `json:"wind"`
+	Days           int      `json:"days"`
+}
+
+type AlertRuleTO struct {
+	ID             string   `json:"id"`
+	UserID         string   `json:"userId"`
+	CityID         string   `json:"cityId"`
+	City           string   `json:"city"`
+	State          string   `json:"state,omitempty"`
+	Conditions     []string `json:"conditions,omitempty"`
+	MinTemperature *int     `json:"minTemperature,omitempty"`
+	MaxTemperature *int     `json:"maxTemperature,omitempty"`
+	IUV            *float64 `json:"iuv,omitempty"`
+	WaveHeight     *float64 `json:"waveHeight,omitempty"`
+	Wind           *float64 `json:"wind,omitempty"`
+	Days           int      `json:"days"`
+	CreatedAt      string   `json:"createdAt"`
+}
+
+type AlertRuleListTO struct {
+	Rules []AlertRuleTO `json:"rules"`
+}
+
+type SubscriptionRequest struct {
+	UserID    string   `json:"userId"`
+	CityID    string   `json:"cityId"`
+	City      string   `json:"city"`
+	State     string   `json:"state"`
+	Latitude  *float64 `json:"lat"`
+	Longitude *float64 `json:"lon"`
+	Threshold int      `json:"threshold"`
+	Days      int      `json:"days"`
+}
+
+type SubscriptionTO struct {
+	ID        string `json:"id"`
+	UserID    string `json:"userId"`
+	CityID    string `json:"cityId"`
+	City      string `json:"city"`
+	State     string `json:"state,omitempty"`
+	Threshold int    `json:"threshold"`
+	Days      int    `json:"days"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type SubscriptionListTO struct {
+	Subscriptions []SubscriptionTO `json:"subscriptions"`
+}
+
+type CityTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	UF   string `json:"uf"`
+}
+
+type CityListTO struct {
+	Cities []CityTO `json:"cities"`
+}
+
+// ForecastTO has the temperatures in °C and the CPTEC condition code of each
+// day, empty when unknown
+type ForecastTO struct {
+	City      CityTO          `json:"city"`
+	UpdatedAt string          `json:"updatedAt"`
+	Days      []ForecastDayTO `json:"days"`
+}
+
+type ForecastDayTO struct {
+	Date    string  `json:"date"`
+	Code    string  `json:"code,omitempty"`
+	Weather string  `json:"weather"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max"`
+	IUV     float64 `json:"iuv"`
+	UVRisk  string  `json:"uvRisk"`
+}
+
+// WaveForecastTO has the heights in meters
+type WaveForecastTO struct {
+	City      CityTO       `json:"city"`
+	UpdatedAt string       `json:"updatedAt"`
+	Date      string       `json:"date"`
+	Morning   WavePeriodTO `json:"morning"`
+	Afternoon WavePeriodTO `json:"afternoon"`
+	Evening   WavePeriodTO `json:"evening"`
+}
+
+type WavePeriodTO struct {
+	Swell         string  `json:"swell"`
+	Height        float64 `json:"height"`
+	Direction     string  `json:"direction"`
+	Wind          float64 `json:"wind"`
+	WindDirection string  `json:"windDirection"`
+}
+
+// buildCoordinates reads optional coordinates, which must come in pairs
+func buildCoordinates(latitude, longitude *float64) (*weather.Coordinates, error) {
+	if latitude == nil && longitude == nil {
+		return nil, nil
+	}
+
+	if latitude == nil || longitude == nil {
+		return nil, fmt.Errorf("%w: both lat and lon are required", weather.ErrInvalidCity)
+	}
+
+	return &weather.Coordinates{Latitude: *latitude, Longitude: *longitude}, nil
+}
+
+func buildScheduleTO(s schedule.Schedule) ScheduleTO {
+	var latitude, longitude *float64
+
+	if s.Coordinates != nil {
+		latitude = &s.Coordinates.Latitude
+		longitude = &s.Coordinates.Longitude
+	}
+
+	return ScheduleTO{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		CityID:     s.CityID,
+		City:       s.CityName,
+		State:      s.State,
+		Latitude:   latitude,
+		Longitude:  longitude,
+		Status:     s.Status,
+		Time:       s.Time.Format(time.RFC3339),
+		Recurrence: s.Recurrence,
+		Timezone:   s.Timezone,
+		Days:       s.Days,
+		AlertsOnly: s.AlertsOnly,
+		Attempts:   s.Attempts,
+		Error:      s.LastError,
+	}
+}
+
+func buildScheduleListTO(page schedule.Page) ScheduleListTO {
+	schedules := make([]ScheduleTO, len(page.Schedules))
+
+	for i, s := range page.Schedules {
+		schedules[i] = buildScheduleTO(s)
+	}
+
+	return ScheduleListTO{
+		Schedules: schedules,
+		Page:      page.Page,
+		PageSize:  page.PageSize,
+		Total:     page.Total,
+	}
+}
+
+func buildCityListTO(cities []weather.City) CityListTO {
+	result := make([]CityTO, len(cities))
+
+	for i, city := range cities {
+		result[i] = buildCityTO(city)
+	}
+
+	return CityListTO{Cities: result}
+}
+
+func buildCityTO(city weather.City) CityTO {
+	return CityTO{
+		ID:   city.ID,
+		Name: city.Name,
+		UF:   city.State,
+	}
+}
+
+func buildForecastTO(city weather.City, forecast weather.CityForecast) ForecastTO {
+	days := make([]ForecastDayTO, len(forecast.Forecast))
+
+	for i, day := range forecast.Forecast {
+		days[i] = ForecastDayTO{
+			Date:    day.Date,
+			Code:    day.Code,
+			Weather: day.Weather,
+			Min:     day.MinTemperature,
+			Max:     day.MaxTemperature,
+			IUV:     day.IUV,
+			UVRisk:  day.UVRisk(),
+		}
+	}
+
+	return ForecastTO{
+		City:      buildCityTO(city),
+		UpdatedAt: forecast.UpdatedAt,
+		Days:      days,
+	}
+}
+
+func buildWaveForecastTO(city weather.City, waves weather.CityWaveForecast) WaveForecastTO {
+	return WaveForecastTO{
+		City:      buildCityTO(city),
+		UpdatedAt: waves.UpdatedAt,
+		Date:      waves.Date,
+		Morning:   buildWavePeriodTO(waves.Morning),
+		Afternoon: buildWavePeriodTO(waves.Afternoon),
+		Evening:   buildWavePeriodTO(waves.Evening),
+	}
+}
+
+func buildWavePeriodTO(wave weather.WaveForecast) WavePeriodTO {
+	return WavePeriodTO{
+		Swell:         wave.Swell,
+		Height:        wave.Height,
+		Direction:     wave.WaveDirection,
+		Wind:          wave.Wind,
+		WindDirection: wave.WindDirection,
+	}
+}
+
+func buildAlertRule(body AlertRuleRequest) alert.Rule {
+	return alert.Rule{
+		Conditions:     body.Conditions,
+		MinTemperature: body.MinTemperature,
+		MaxTemperature: body.MaxTemperature,
+		IUV:            body.IUV,
+		WaveHeight:     body.WaveHeight,
+		Wind:           body.Wind,
+		Days:           body.Days,
+	}
+}
+
+func buildAlertRuleTO(rule alert.Rule) AlertRuleTO {
+	return AlertRuleTO{
+		ID:             rule.ID,
+		UserID:         rule.UserID,
+		CityID:         rule.CityID,
+		City:           rule.CityName,
+		State:          rule.State,
+		Conditions:     rule.Conditions,
+		MinTemperature: rule.MinTemperature,
+		MaxTemperature: rule.MaxTemperature,
+		IUV:            rule.IUV,
+		WaveHeight:     rule.WaveHeight,
+		Wind:           rule.Wind,
+		Days:           rule.Days,
+		CreatedAt:      rule.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func buildAlertRuleListTO(rules []alert.Rule) AlertRuleListTO {
+	result := make([]AlertRuleTO, len(rules))
+
+	for i, rule := range rules {
+		result[i] = buildAlertRuleTO(rule)
+	}
+
+	return AlertRuleListTO{Rules: result}
+}
+
+func buildSubscriptionTO(subscription revision.Subscription) SubscriptionTO {
+	return SubscriptionTO{
+		ID:        subscription.ID,
+		UserID:    subscription.UserID,
+		CityID:    subscription.CityID,
+		City:      subscription.CityName,
+		State:     subscription.State,
+		Threshold: subscription.Threshold,
+		Days:      subscription.Days,
+		CreatedAt: subscription.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func buildSubscriptionListTO(subscriptions []revision.Subscription) SubscriptionListTO {
+	result := make([]SubscriptionTO, len(subscriptions))
+
+	for i, subscription := range subscriptions {
+		result[i] = buildSubscriptionTO(subscription)
+	}
+
+	return SubscriptionListTO{Subscriptions: result}
 }
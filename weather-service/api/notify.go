@@ -4,11 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/fgouvea/weather/weather-service/weather"
 	"go.uber.org/zap"
 )
 
 type WeatherNotifier interface {
-	NotifyUser(userID string, city string) error
+	NotifyUser(userID string, city weather.CityQuery, days int, style, channel string) error
 }
 
 type WeatherHandler struct {
@@ -21,16 +22,23 @@ func (h *WeatherHandler) NotifyUser(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&body)
 
 	if err != nil {
-		h.Logger.Error("error reading request body", zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusBadRequest)
+		writeMalformed(w, h.Logger, err)
 		return
 	}
 
-	err = h.Notifier.NotifyUser(body.UserID, body.City)
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, h.Logger, errs)
+		return
+	}
+
+	coordinates, err := buildCoordinates(body.Latitude, body.Longitude)
+
+	if err == nil {
+		err = h.Notifier.NotifyUser(body.UserID, weather.CityQuery{ID: body.CityID, Name: body.City, State: body.State, Coordinates: coordinates}, body.Days, body.Style, body.Channel)
+	}
 
 	if err != nil {
-		h.Logger.Error("error notifying user", zap.String("userID", body.UserID), zap.String("city", body.City), zap.String("error", err.Error()))
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, h.Logger, "error notifying user", err, zap.String("userID", body.UserID), zap.String("city", body.City), zap.String("state", body.State))
 		return
 	}
 
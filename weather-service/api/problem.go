@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fgouvea/weather/weather-service/alert"
+	"github.com/fgouvea/weather/weather-service/revision"
+	"github.com/fgouvea/weather/weather-service/schedule"
+	"github.com/fgouvea/weather/weather-service/user"
+	"github.com/fgouvea/weather/weather-service/weather"
+	"go.uber.org/zap"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 error response. Type tells errors apart regardless
+// of Detail, which is written for people.
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is a field of the request that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type problemType struct {
+	err    error
+	status int
+	name   string
+	title  string
+}
+
+// problemTypes maps the errors of every package to a response. The first one
+// matching is used, and errors matching none are internal errors.
+var problemTypes = []problemType{
+	{user.ErrUserNotFound, http.StatusNotFound, "user-not-found", "User not found"},
+	{weather.ErrCityNotFound, http.StatusNotFound, "city-not-found", "City not found"},
+	{weather.ErrWavesNotFound, http.StatusNotFound, "waves-not-found", "Wave forecast not found"},
+	{schedule.ErrScheduleNotFound, http.StatusNotFound, "schedule-not-found", "Schedule not found"},
+	{alert.ErrRuleNotFound, http.StatusNotFound, "alert-rule-not-found", "Alert rule not found"},
+	{revision.ErrSubscriptionNotFound, http.StatusNotFound, "subscription-not-found", "Forecast subscription not found"},
+	{weather.ErrMultipleCities, http.StatusConflict, "multiple-cities", "Ambiguous city, a state or city ID is needed"},
+	{schedule.ErrScheduleNotActive, http.StatusConflict, "schedule-not-active", "Schedule is no longer active"},
+	{weather.ErrInvalidCity, http.StatusBadRequest, "invalid-city", "Invalid city"},
+	{weather.ErrInvalidForecastDays, http.StatusBadRequest, "invalid-forecast-days", "Invalid number of forecast days"},
+	{weather.ErrInvalidForecastStyle, http.StatusBadRequest, "invalid-forecast-style", "Invalid forecast style"},
+	{schedule.ErrScheduleInThePast, http.StatusBadRequest, "schedule-in-the-past", "Schedule time is in the past"},
+	{schedule.ErrInvalidRecurrence, http.StatusBadRequest, "invalid-recurrence", "Invalid recurrence"},
+	{schedule.ErrInvalidStatus, http.StatusBadRequest, "invalid-status", "Invalid schedule status"},
+	{alert.ErrInvalidRule, http.StatusBadRequest, "invalid-alert-rule", "Invalid alert rule"},
+	{revision.ErrInvalidSubscription, http.StatusBadRequest, "invalid-subscription", "Invalid forecast subscription"},
+	{weather.ErrProviderUnavailable, http.StatusServiceUnavailable, "provider-unavailable", "Weather provider unavailable"},
+}
+
+func problemTypeURI(name string) string {
+	return "urn:weather:problem:" + name
+}
+
+// problemFor builds the response to an error. Internal errors keep their
+// detail out of the response, since it may expose how the service works.
+func problemFor(err error) Problem {
+	for _, known := range problemTypes {
+		if errors.Is(err, known.err) {
+			return Problem{
+				Type:   problemTypeURI(known.name),
+				Title:  known.title,
+				Status: known.status,
+				Detail: err.Error(),
+			}
+		}
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+	}
+}
+
+func writeProblem(w http.ResponseWriter, problem Problem) {
+	body, _ := json.Marshal(problem)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	w.Write(body)
+}
+
+// writeError logs the error, as an error only when it is not the client's
+// fault, and answers with its problem
+func writeError(w http.ResponseWriter, logger *zap.Logger, message string, err error, fields ...zap.Field) {
+	problem := problemFor(err)
+	fields = append(fields, zap.Error(err))
+
+	if problem.Status >= http.StatusInternalServerError {
+		logger.Error(message, fields...)
+	} else {
+		logger.Info(message, fields...)
+	}
+
+	writeProblem(w, problem)
+}
+
+// writeInvalid answers a request that failed validation before reaching the
+// services
+func writeInvalid(w http.ResponseWriter, logger *zap.Logger, errs []FieldError) {
+	logger.Info("invalid request", zap.Any("errors", errs))
+
+	writeProblem(w, Problem{
+		Type:   problemTypeURI("invalid-request"),
+		Title:  "Invalid request",
+		Status: http.StatusBadRequest,
+		Errors: errs,
+	})
+}
+
+// writeMalformed answers a request whose body could not be read
+func writeMalformed(w http.ResponseWriter, logger *zap.Logger, err error) {
+	logger.Info("error reading request body", zap.Error(err))
+
+	writeProblem(w, Problem{
+		Type:   problemTypeURI("malformed-request"),
+		Title:  "Malformed request body",
+		Status: http.StatusBadRequest,
+		Detail: err.Error(),
+	})
+}
+
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusNotFound),
+		Status: http.StatusNotFound,
+		Detail: "no route for " + r.URL.Path,
+	})
+}
+
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusMethodNotAllowed),
+		Status: http.StatusMethodNotAllowed,
+		Detail: r.Method + " is not allowed on " + r.URL.Path,
+	})
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+func writeJSON(w http.ResponseWriter, logger *zap.Logger, status int, body any) {
+	responseBody, err := json.Marshal(body)
+
+	if err != nil {
+		logger.Error("error writing response", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(responseBody)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fgouvea/weather/weather-service/revision"
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type SubscriptionManager interface {
+	Subscribe(userID string, city weather.CityQuery, subscription revision.Subscription) (revision.Subscription, error)
+	Find(id string) (revision.Subscription, error)
+	List(userID string) ([]revision.Subscription, error)
+	Unsubscribe(id string) error
+}
+
+type RevisionHandler struct {
+	Subscriptions SubscriptionManager
+	Logger        *zap.Logger
+}
+
+func (h *RevisionHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var body SubscriptionRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		writeMalformed(w, h.Logger, err)
+		return
+	}
+
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, h.Logger, errs)
+		return
+	}
+
+	coordinates, err := buildCoordinates(body.Latitude, body.Longitude)
+
+	if err != nil {
+		writeError(w, h.Logger, "error reading coordinates", err)
+		return
+	}
+
+	city := weather.CityQuery{ID: body.CityID, Name: body.City, State: body.State, Coordinates: coordinates}
+
+	result, err := h.Subscriptions.Subscribe(body.UserID, city, revision.Subscription{Threshold: body.Threshold, Days: body.Days})
+
+	if err != nil {
+		writeError(w, h.Logger, "error subscribing to forecast revisions", err, zap.String("userID", body.UserID), zap.String("city", body.City))
+		return
+	}
+
+	h.Logger.Info("subscribed to forecast revisions", zap.String("subscriptionID", result.ID), zap.String("userID", body.UserID), zap.String("cityID", result.CityID))
+	writeJSON(w, h.Logger, http.StatusCreated, buildSubscriptionTO(result))
+}
+
+func (h *RevisionHandler) Find(w http.ResponseWriter, r *http.Request) {
+	subscriptionID := chi.URLParam(r, "subscriptionID")
+
+	result, err := h.Subscriptions.Find(subscriptionID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error finding subscription", err, zap.String("subscriptionID", subscriptionID))
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, buildSubscriptionTO(result))
+}
+
+func (h *RevisionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("userId")
+
+	if errs := validateRequired(nil, "userId", userID); len(errs) > 0 {
+		writeInvalid(w, h.Logger, errs)
+		return
+	}
+
+	result, err := h.Subscriptions.List(userID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error listing subscriptions", err, zap.String("userID", userID))
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, buildSubscriptionListTO(result))
+}
+
+func (h *RevisionHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	subscriptionID := chi.URLParam(r, "subscriptionID")
+
+	err := h.Subscriptions.Unsubscribe(subscriptionID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error deleting subscription", err, zap.String("subscriptionID", subscriptionID))
+		return
+	}
+
+	h.Logger.Info("unsubscribed from forecast revisions", zap.String("subscriptionID", subscriptionID))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,16 +2,23 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/fgouvea/weather/weather-service/schedule"
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
 
 type WeatherScheduler interface {
-	Schedule(userID, cityName string, scheduleTime time.Time) error
+	Schedule(userID string, city weather.CityQuery, scheduleTime time.Time, days int, alertsOnly bool) (schedule.Schedule, error)
+	ScheduleRecurring(userID string, city weather.CityQuery, recurrence, timezone string, days int, alertsOnly bool) (schedule.Schedule, error)
+	Find(id string) (schedule.Schedule, error)
+	List(filter schedule.Filter) (schedule.Page, error)
+	Update(id string, update schedule.Update) (schedule.Schedule, error)
+	Cancel(id string) error
 }
 
 type ScheduleHandler struct {
@@ -24,33 +31,163 @@ func (h *ScheduleHandler) Schedule(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&body)
 
 	if err != nil {
-		h.Logger.Error("error reading request body", zap.Error(err))
-		w.WriteHeader(http.StatusBadRequest)
+		writeMalformed(w, h.Logger, err)
 		return
 	}
 
-	scheduleTime, err := time.Parse(time.RFC3339, body.Time)
+	if errs := body.Validate(); len(errs) > 0 {
+		writeInvalid(w, h.Logger, errs)
+		return
+	}
+
+	coordinates, err := buildCoordinates(body.Latitude, body.Longitude)
+
+	if err != nil {
+		writeError(w, h.Logger, "error reading coordinates", err)
+		return
+	}
+
+	city := weather.CityQuery{ID: body.CityID, Name: body.City, State: body.State, Coordinates: coordinates}
+
+	var result schedule.Schedule
+
+	if body.Recurrence != "" {
+		result, err = h.Scheduler.ScheduleRecurring(body.UserID, city, body.Recurrence, body.Timezone, body.Days, body.AlertsOnly)
+	} else {
+		var scheduleTime time.Time
+		scheduleTime, err = time.Parse(time.RFC3339, body.Time)
+
+		if err != nil {
+			writeInvalid(w, h.Logger, invalidField("time", "time must be an RFC 3339 date"))
+			return
+		}
+
+		result, err = h.Scheduler.Schedule(body.UserID, city, scheduleTime, body.Days, body.AlertsOnly)
+	}
+
+	if err != nil {
+		writeError(w, h.Logger, "error scheduling weather info", err, zap.String("userID", body.UserID), zap.String("city", body.City))
+		return
+	}
+
+	h.Logger.Info("weather info scheduled", zap.String("scheduleID", result.ID), zap.String("userID", body.UserID), zap.String("city", body.City))
+	writeJSON(w, h.Logger, http.StatusCreated, buildScheduleTO(result))
+}
+
+func (h *ScheduleHandler) Find(w http.ResponseWriter, r *http.Request) {
+	scheduleID := chi.URLParam(r, "scheduleID")
+
+	result, err := h.Scheduler.Find(scheduleID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error finding schedule", err, zap.String("scheduleID", scheduleID))
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, buildScheduleTO(result))
+}
+
+func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, err := readIntParam(query.Get("page"))
+
+	if err != nil {
+		writeInvalid(w, h.Logger, invalidField("page", "page must be a number"))
+		return
+	}
+
+	pageSize, err := readIntParam(query.Get("pageSize"))
+
+	if err != nil {
+		writeInvalid(w, h.Logger, invalidField("pageSize", "pageSize must be a number"))
+		return
+	}
+
+	filter := schedule.Filter{
+		UserID:   query.Get("userId"),
+		Status:   query.Get("status"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	result, err := h.Scheduler.List(filter)
+
+	if err != nil {
+		writeError(w, h.Logger, "error listing schedules", err, zap.String("userID", filter.UserID), zap.String("status", filter.Status))
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, buildScheduleListTO(result))
+}
+
+func (h *ScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	scheduleID := chi.URLParam(r, "scheduleID")
+
+	var body UpdateScheduleRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
 
 	if err != nil {
-		h.Logger.Error("error reading schedule time", zap.Error(err))
-		w.WriteHeader(http.StatusBadRequest)
+		writeMalformed(w, h.Logger, err)
 		return
 	}
 
-	err = h.Scheduler.Schedule(body.UserID, body.City, scheduleTime)
+	coordinates, err := buildCoordinates(body.Latitude, body.Longitude)
 
 	if err != nil {
-		status := http.StatusInternalServerError
+		writeError(w, h.Logger, "error reading coordinates", err)
+		return
+	}
+
+	update := schedule.Update{
+		CityID:      body.CityID,
+		CityName:    body.City,
+		State:       body.State,
+		Coordinates: coordinates,
+		Days:        body.Days,
+		AlertsOnly:  body.AlertsOnly,
+	}
+
+	if body.Time != nil {
+		scheduleTime, err := time.Parse(time.RFC3339, *body.Time)
 
-		if errors.Is(schedule.ErrScheduleInThePast, err) {
-			status = http.StatusBadRequest
+		if err != nil {
+			writeInvalid(w, h.Logger, invalidField("time", "time must be an RFC 3339 date"))
+			return
 		}
 
-		h.Logger.Error("error scheduling weather info", zap.String("userID", body.UserID), zap.String("city", body.City), zap.Error(err))
-		w.WriteHeader(status)
+		update.Time = &scheduleTime
+	}
+
+	result, err := h.Scheduler.Update(scheduleID, update)
+
+	if err != nil {
+		writeError(w, h.Logger, "error updating schedule", err, zap.String("scheduleID", scheduleID))
+		return
+	}
+
+	h.Logger.Info("schedule updated", zap.String("scheduleID", scheduleID))
+	writeJSON(w, h.Logger, http.StatusOK, buildScheduleTO(result))
+}
+
+func (h *ScheduleHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	scheduleID := chi.URLParam(r, "scheduleID")
+
+	err := h.Scheduler.Cancel(scheduleID)
+
+	if err != nil {
+		writeError(w, h.Logger, "error cancelling schedule", err, zap.String("scheduleID", scheduleID))
 		return
 	}
 
-	h.Logger.Info("weather info scheduled", zap.String("userID", body.UserID), zap.String("city", body.City))
-	w.WriteHeader(http.StatusOK)
+	h.Logger.Info("schedule cancelled", zap.String("scheduleID", scheduleID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func readIntParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
 }
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// notificationChannels are the senders of notification-service a forecast
+// can be sent through
+var notificationChannels = []string{"web", "email", "sms", "telegram", "webhook", "push"}
+
+// Validate checks the fields needed before the request reaches the services,
+// which still validate what depends on their own rules
+func (r NotifyUserRequest) Validate() []FieldError {
+	errs := validateRequired(nil, "userId", r.UserID)
+	errs = validateCity(errs, r.CityID, r.City, r.Latitude, r.Longitude)
+
+	if r.Channel != "" && !slices.Contains(notificationChannels, r.Channel) {
+		errs = append(errs, FieldError{Field: "channel", Message: fmt.Sprintf("channel must be one of %s", strings.Join(notificationChannels, ", "))})
+	}
+
+	return errs
+}
+
+func (r ScheduleRequest) Validate() []FieldError {
+	errs := validateRequired(nil, "userId", r.UserID)
+	errs = validateCity(errs, r.CityID, r.City, r.Latitude, r.Longitude)
+
+	if strings.TrimSpace(r.Time) == "" && strings.TrimSpace(r.Recurrence) == "" {
+		errs = append(errs, FieldError{Field: "time", Message: "time or recurrence is required"})
+	}
+
+	return errs
+}
+
+func (r AlertRuleRequest) Validate() []FieldError {
+	errs := validateRequired(nil, "userId", r.UserID)
+	return validateCity(errs, r.CityID, r.City, r.Latitude, r.Longitude)
+}
+
+func (r SubscriptionRequest) Validate() []FieldError {
+	errs := validateRequired(nil, "userId", r.UserID)
+	return validateCity(errs, r.CityID, r.City, r.Latitude, r.Longitude)
+}
+
+func validateRequired(errs []FieldError, field, value string) []FieldError {
+	if strings.TrimSpace(value) == "" {
+		errs = append(errs, FieldError{Field: field, Message: field + " is required"})
+	}
+
+	return errs
+}
+
+// validateCity requires one of the ways of finding a city: its ID, its name
+// or its coordinates
+func validateCity(errs []FieldError, cityID, city string, latitude, longitude *float64) []FieldError {
+	if (latitude == nil) != (longitude == nil) {
+		field := "lat"
+
+		if longitude == nil {
+			field = "lon"
+		}
+
+		return append(errs, FieldError{Field: field, Message: "lat and lon must be sent together"})
+	}
+
+	if strings.TrimSpace(cityID) == "" && strings.TrimSpace(city) == "" && latitude == nil {
+		errs = append(errs, FieldError{Field: "city", Message: "cityId, city or lat and lon are required"})
+	}
+
+	return errs
+}
+
+func invalidField(field, message string) []FieldError {
+	return []FieldError{{Field: field, Message: message}}
+}
@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrBackend = errors.New("cache backend error")
+
+// Backend stores serialized values for a limited time.
+type Backend interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Date(2025, 2, 7, 10, 0, 0, 0, time.UTC)
+
+	cache := NewLRU(2)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Hour)
+
+	value, found, err := cache.Get("a")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("1"), value)
+
+	// "b" is now the least recently used entry
+	cache.Set("c", []byte("3"), time.Hour)
+
+	_, found, _ = cache.Get("b")
+	assert.False(t, found)
+
+	_, found, _ = cache.Get("c")
+	assert.True(t, found)
+
+	// Overwriting refreshes the value and the expiry
+	cache.Set("c", []byte("4"), time.Hour)
+	value, _, _ = cache.Get("c")
+	assert.Equal(t, []byte("4"), value)
+
+	now = now.Add(time.Minute)
+
+	_, found, _ = cache.Get("a")
+	assert.False(t, found)
+	assert.Equal(t, 1, cache.order.Len())
+
+	_, found, _ = cache.Get("c")
+	assert.True(t, found)
+}
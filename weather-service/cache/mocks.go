@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+var runtimeError = errors.New("runtime error")
+
+var testCity = weather.City{
+	ID:    "241",
+	Name:  "Rio de Janeiro",
+	State: "RJ",
+}
+
+var testForecast = weather.CityForecast{
+	UpdatedAt: "2025-02-07",
+	Forecast: []weather.Forecast{
+		{Date: "2025-02-07", Weather: "Chuva", MinTemperature: 22, MaxTemperature: 31, IUV: 11},
+	},
+}
+
+var testWaveForecast = weather.CityWaveForecast{
+	Date:    "2025-02-07",
+	Morning: weather.WaveForecast{Swell: "Fraca", Height: 0.5},
+}
+
+type providerMock struct {
+	mu sync.Mutex
+
+	// When set, calls block until it is closed
+	release chan struct{}
+
+	// When set, calls panic with it once released
+	panicValue any
+
+	findCityCalls  []string
+	findCityResult weather.City
+	findCityError  error
+
+	searchCitiesCalls  []string
+	searchCitiesResult []weather.City
+	searchCitiesError  error
+
+	getForecastCalls  []string
+	getForecastResult weather.CityForecast
+	getForecastError  error
+
+	getExtendedForecastCalls  []string
+	getExtendedForecastResult weather.CityForecast
+	getExtendedForecastError  error
+
+	getWaveForecastCalls  []string
+	getWaveForecastResult weather.CityWaveForecast
+	getWaveForecastError  error
+}
+
+var _ weather.Provider = (*providerMock)(nil)
+
+func (m *providerMock) FindCity(name, state string) (weather.City, error) {
+	m.record(&m.findCityCalls, fmt.Sprintf("%s|%s", name, state))
+	return m.findCityResult, m.findCityError
+}
+
+func (m *providerMock) FindCityByID(id string) (weather.City, error) {
+	m.record(&m.findCityCalls, "id:"+id)
+	return m.findCityResult, m.findCityError
+}
+
+func (m *providerMock) SearchCities(name string) ([]weather.City, error) {
+	m.record(&m.searchCitiesCalls, name)
+	return m.searchCitiesResult, m.searchCitiesError
+}
+
+func (m *providerMock) FindCityByCoordinates(latitude, longitude float64) (weather.City, error) {
+	m.record(&m.findCityCalls, fmt.Sprintf("%f,%f", latitude, longitude))
+	return m.findCityResult, m.findCityError
+}
+
+func (m *providerMock) GetForecast(id string) (weather.CityForecast, error) {
+	m.record(&m.getForecastCalls, id)
+	return m.getForecastResult, m.getForecastError
+}
+
+func (m *providerMock) GetExtendedForecast(id string) (weather.CityForecast, error) {
+	m.record(&m.getExtendedForecastCalls, id)
+	return m.getExtendedForecastResult, m.getExtendedForecastError
+}
+
+func (m *providerMock) GetWaveForecast(id string) (weather.CityWaveForecast, error) {
+	m.record(&m.getWaveForecastCalls, id)
+	return m.getWaveForecastResult, m.getWaveForecastError
+}
+
+func (m *providerMock) GetWaveForecastRange(id string, days int) ([]weather.CityWaveForecast, error) {
+	m.record(&m.getWaveForecastCalls, fmt.Sprintf("%s:%d", id, days))
+	return []weather.CityWaveForecast{m.getWaveForecastResult}, m.getWaveForecastError
+}
+
+func (m *providerMock) record(calls *[]string, arg string) {
+	m.mu.Lock()
+	*calls = append(*calls, arg)
+	m.mu.Unlock()
+
+	if m.release != nil {
+		<-m.release
+	}
+
+	if m.panicValue != nil {
+		panic(m.panicValue)
+	}
+}
+
+type backendMock struct {
+	getError error
+	setError error
+	setCalls []string
+}
+
+var _ Backend = (*backendMock)(nil)
+
+func (m *backendMock) Get(key string) ([]byte, bool, error) {
+	return nil, false, m.getError
+}
+
+func (m *backendMock) Set(key string, value []byte, ttl time.Duration) error {
+	m.setCalls = append(m.setCalls, key)
+	return m.setError
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"golang.org/x/sync/singleflight"
+)
+
+// Bump when the cached types change, so old entries are not decoded into the
+// new ones
+const keyVersion = "v1"
+
+const (
+	endpointCity     = "city"
+	endpointSearch   = "search"
+	endpointForecast = "forecast"
+	endpointExtended = "extended"
+	endpointWave     = "wave"
+)
+
+// Metrics holds hit, miss and backend error counts, keyed as
+// "<provider>.<endpoint>.<hits|misses|errors>". Published under /debug/vars.
+var Metrics = expvar.NewMap("weather_cache")
+
+type TTL struct {
+	City     time.Duration
+	Forecast time.Duration
+	Wave     time.Duration
+}
+
+// Provider caches the responses of a weather.Provider. Concurrent misses for
+// the same key are merged into a single request, and errors are never cached.
+// A failing backend is skipped, so the cache can only make things faster.
+type Provider struct {
+	Name     string
+	Provider weather.Provider
+	Backend  Backend
+	TTL      TTL
+
+	group singleflight.Group
+}
+
+var _ weather.Provider = (*Provider)(nil)
+
+func NewProvider(name string, provider weather.Provider, backend Backend, ttl TTL) *Provider {
+	return &Provider{
+		Name:     name,
+		Provider: provider,
+		Backend:  backend,
+		TTL:      ttl,
+	}
+}
+
+func (p *Provider) FindCity(name, state string) (weather.City, error) {
+	key := fmt.Sprintf("%s|%s", weather.NormalizeName(name), weather.NormalizeName(state))
+
+	return cached(p, endpointCity, key, p.TTL.City, func() (weather.City, error) {
+		return p.Provider.FindCity(name, state)
+	})
+}
+
+func (p *Provider) FindCityByID(id string) (weather.City, error) {
+	return cached(p, endpointCity, "id:"+id, p.TTL.City, func() (weather.City, error) {
+		return p.Provider.FindCityByID(id)
+	})
+}
+
+func (p *Provider) SearchCities(name string) ([]weather.City, error) {
+	return cached(p, endpointSearch, weather.NormalizeName(name), p.TTL.City, func() ([]weather.City, error) {
+		return p.Provider.SearchCities(name)
+	})
+}
+
+func (p *Provider) FindCityByCoordinates(latitude, longitude float64) (weather.City, error) {
+	key := fmt.Sprintf("%.4f,%.4f", latitude, longitude)
+
+	return cached(p, endpointCity, key, p.TTL.City, func() (weather.City, error) {
+		return p.Provider.FindCityByCoordinates(latitude, longitude)
+	})
+}
+
+func (p *Provider) GetForecast(id string) (weather.CityForecast, error) {
+	return cached(p, endpointForecast, id, p.TTL.Forecast, func() (weather.CityForecast, error) {
+		return p.Provider.GetForecast(id)
+	})
+}
+
+func (p *Provider) GetExtendedForecast(id string) (weather.CityForecast, error) {
+	return cached(p, endpointExtended, id, p.TTL.Forecast, func() (weather.CityForecast, error) {
+		return p.Provider.GetExtendedForecast(id)
+	})
+}
+
+func (p *Provider) GetWaveForecast(id string) (weather.CityWaveForecast, error) {
+	return cached(p, endpointWave, id, p.TTL.Wave, func() (weather.CityWaveForecast, error) {
+		return p.Provider.GetWaveForecast(id)
+	})
+}
+
+func (p *Provider) GetWaveForecastRange(id string, days int) ([]weather.CityWaveForecast, error) {
+	key := fmt.Sprintf("%s:%d", id, days)
+
+	return cached(p, endpointWave, key, p.TTL.Wave, func() ([]weather.CityWaveForecast, error) {
+		return p.Provider.GetWaveForecastRange(id, days)
+	})
+}
+
+func cached[T any](p *Provider, endpoint, id string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	key := fmt.Sprintf("weather:%s:%s:%s:%s", keyVersion, p.Name, endpoint, id)
+
+	var result T
+
+	if value, found := p.get(endpoint, key); found && json.Unmarshal(value, &result) == nil {
+		p.count(endpoint, "hits")
+		return result, nil
+	}
+
+	p.count(endpoint, "misses")
+
+	shared, err, _ := p.group.Do(key, func() (any, error) {
+		fetched, err := fetch()
+
+		if err != nil {
+			return fetched, err
+		}
+
+		if value, err := json.Marshal(fetched); err == nil {
+			if err := p.Backend.Set(key, value, ttl); err != nil {
+				p.count(endpoint, "errors")
+			}
+		}
+
+		return fetched, nil
+	})
+
+	return shared.(T), err
+}
+
+func (p *Provider) get(endpoint, key string) ([]byte, bool) {
+	value, found, err := p.Backend.Get(key)
+
+	if err != nil {
+		p.count(endpoint, "errors")
+		return nil, false
+	}
+
+	return value, found
+}
+
+func (p *Provider) count(endpoint, metric string) {
+	Metrics.Add(fmt.Sprintf("%s.%s.%s", p.Name, endpoint, metric), 1)
+}
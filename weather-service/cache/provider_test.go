@@ -0,0 +1,240 @@
+package cache
+
+import (
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTTL = TTL{City: time.Hour, Forecast: time.Minute, Wave: time.Minute}
+
+func metric(name string) int64 {
+	value, ok := Metrics.Get(name).(*expvar.Int)
+
+	if !ok {
+		return 0
+	}
+
+	return value.Value()
+}
+
+func TestProvider_FindCity(t *testing.T) {
+	mock := &providerMock{findCityResult: testCity}
+	provider := NewProvider("find-city", mock, NewLRU(10), testTTL)
+
+	city, err := provider.FindCity("São Paulo", "SP")
+	assert.Nil(t, err)
+	assert.Equal(t, testCity, city)
+
+	// Names differing only in case, accents and spaces share an entry
+	city, err = provider.FindCity(" sao  paulo", "sp")
+	assert.Nil(t, err)
+	assert.Equal(t, testCity, city)
+
+	// But not across states
+	city, err = provider.FindCity("São Paulo", "")
+	assert.Nil(t, err)
+	assert.Equal(t, testCity, city)
+
+	// Nor with lookups by ID
+	city, err = provider.FindCityByID("244")
+	assert.Nil(t, err)
+	assert.Equal(t, testCity, city)
+
+	assert.Equal(t, []string{"São Paulo|SP", "São Paulo|", "id:244"}, mock.findCityCalls)
+	assert.Equal(t, int64(1), metric("find-city.city.hits"))
+	assert.Equal(t, int64(3), metric("find-city.city.misses"))
+}
+
+func TestProvider_SearchCities(t *testing.T) {
+	mock := &providerMock{searchCitiesResult: []weather.City{testCity}}
+	provider := NewProvider("search-cities", mock, NewLRU(10), testTTL)
+
+	cities, err := provider.SearchCities("Rio")
+	assert.Nil(t, err)
+	assert.Equal(t, []weather.City{testCity}, cities)
+
+	cities, err = provider.SearchCities("rio")
+	assert.Nil(t, err)
+	assert.Equal(t, []weather.City{testCity}, cities)
+
+	assert.Equal(t, []string{"Rio"}, mock.searchCitiesCalls)
+	assert.Equal(t, int64(1), metric("search-cities.search.hits"))
+}
+
+func TestProvider_GetForecast(t *testing.T) {
+	tests := []struct {
+		name          string
+		mock          *providerMock
+		backend       Backend
+		ttl           TTL
+		expected      weather.CityForecast
+		expectedError error
+		expectedCalls []string
+	}{
+		{
+			name:          "caches",
+			mock:          &providerMock{getForecastResult: testForecast},
+			backend:       NewLRU(10),
+			ttl:           testTTL,
+			expected:      testForecast,
+			expectedCalls: []string{"241"},
+		},
+		{
+			name:          "does not cache errors",
+			mock:          &providerMock{getForecastError: runtimeError},
+			backend:       NewLRU(10),
+			ttl:           testTTL,
+			expectedError: runtimeError,
+			expectedCalls: []string{"241", "241"},
+		},
+		{
+			name:          "disabled endpoint",
+			mock:          &providerMock{getForecastResult: testForecast},
+			backend:       NewLRU(10),
+			ttl:           TTL{City: time.Hour},
+			expected:      testForecast,
+			expectedCalls: []string{"241", "241"},
+		},
+		{
+			name:          "failing backend",
+			mock:          &providerMock{getForecastResult: testForecast},
+			backend:       &backendMock{getError: ErrBackend, setError: ErrBackend},
+			ttl:           testTTL,
+			expected:      testForecast,
+			expectedCalls: []string{"241", "241"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := NewProvider(test.name, test.mock, test.backend, test.ttl)
+
+			for range 2 {
+				result, err := provider.GetForecast("241")
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.Equal(t, test.expected, result)
+			}
+
+			assert.Equal(t, test.expectedCalls, test.mock.getForecastCalls)
+		})
+	}
+}
+
+func TestProvider_GetWaveForecast(t *testing.T) {
+	mock := &providerMock{getWaveForecastResult: testWaveForecast}
+	backend := NewLRU(10)
+
+	provider := NewProvider("waves", mock, backend, testTTL)
+
+	_, err := provider.GetWaveForecast("241")
+	assert.Nil(t, err)
+
+	// Providers sharing a backend do not share entries
+	other := NewProvider("other-waves", mock, backend, testTTL)
+
+	result, err := other.GetWaveForecast("241")
+	assert.Nil(t, err)
+	assert.Equal(t, testWaveForecast, result)
+
+	result, err = provider.GetWaveForecast("241")
+	assert.Nil(t, err)
+	assert.Equal(t, testWaveForecast, result)
+
+	assert.Equal(t, []string{"241", "241"}, mock.getWaveForecastCalls)
+}
+
+func TestProvider_ConcurrentMisses(t *testing.T) {
+	const callers = 10
+
+	mock := &providerMock{getForecastResult: testForecast, release: make(chan struct{})}
+	provider := NewProvider("concurrent", mock, NewLRU(10), testTTL)
+
+	var wg sync.WaitGroup
+	results := make([]weather.CityForecast, callers)
+
+	for i := range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			results[i], _ = provider.GetForecast("241")
+		}()
+	}
+
+	// Wait for every caller to miss before letting the request finish
+	assert.Eventually(t, func() bool {
+		return metric("concurrent.forecast.misses") == callers
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	close(mock.release)
+	wg.Wait()
+
+	assert.Len(t, mock.getForecastCalls, 1)
+
+	for _, result := range results {
+		assert.Equal(t, testForecast, result)
+	}
+}
+
+func TestProvider_ConcurrentMissesPanic(t *testing.T) {
+	const callers = 10
+
+	mock := &providerMock{panicValue: "provider panic", release: make(chan struct{})}
+	provider := NewProvider("concurrent-panic", mock, NewLRU(10), testTTL)
+
+	var wg sync.WaitGroup
+	panicked := make([]bool, callers)
+
+	for i := range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { panicked[i] = recover() != nil }()
+			provider.GetForecast("241")
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return metric("concurrent-panic.forecast.misses") == callers
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	close(mock.release)
+	wg.Wait()
+
+	// Every caller sees the panic instead of waiting forever
+	for _, result := range panicked {
+		assert.True(t, result)
+	}
+
+	// And the key is free for the next request
+	mock.mu.Lock()
+	mock.panicValue = nil
+	mock.getForecastResult = testForecast
+	mock.mu.Unlock()
+
+	result, err := provider.GetForecast("241")
+	assert.Nil(t, err)
+	assert.Equal(t, testForecast, result)
+}
+
+func TestProvider_GetWaveForecastRange(t *testing.T) {
+	mock := &providerMock{getWaveForecastResult: testWaveForecast}
+	provider := NewProvider("wave-range", mock, NewLRU(10), testTTL)
+
+	for _, days := range []int{2, 2, 3} {
+		result, err := provider.GetWaveForecastRange("241", days)
+		assert.Nil(t, err)
+		assert.Equal(t, []weather.CityWaveForecast{testWaveForecast}, result)
+	}
+
+	// Each range is cached on its own
+	assert.Equal(t, []string{"241:2", "241:3"}, mock.getWaveForecastCalls)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisConfig struct {
+	Address  string
+	Password string
+	DB       int
+	Timeout  time.Duration
+}
+
+// Redis is a Backend on a pool of Redis connections, authenticated and set to
+// the configured database as they are dialed.
+type Redis struct {
+	Client *redis.Client
+}
+
+var _ Backend = (*Redis)(nil)
+
+func NewRedis(config RedisConfig) *Redis {
+	return &Redis{
+		Client: redis.NewClient(&redis.Options{
+			Addr:         config.Address,
+			Password:     config.Password,
+			DB:           config.DB,
+			DialTimeout:  config.Timeout,
+			ReadTimeout:  config.Timeout,
+			WriteTimeout: config.Timeout,
+		}),
+	}
+}
+
+func (r *Redis) Get(key string) ([]byte, bool, error) {
+	value, err := r.Client.Get(context.Background(), key).Bytes()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", ErrBackend, err)
+	}
+
+	return value, true, nil
+}
+
+func (r *Redis) Set(key string, value []byte, ttl time.Duration) error {
+	err := r.Client.Set(context.Background(), key, value, ttl).Err()
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBackend, err)
+	}
+
+	return nil
+}
+
+func (r *Redis) Close() error {
+	return r.Client.Close()
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis answers GET and SET from a map, ignoring expiry, and accepts the
+// AUTH and SELECT sent as connections are dialed. It speaks RESP2 only, as
+// Redis before version 6 did.
+type fakeRedis struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	conns    []net.Conn
+	values   map[string]string
+	commands [][]string
+}
+
+func startFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	server := &fakeRedis{listener: listener, values: map[string]string{}}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			server.mu.Lock()
+			server.conns = append(server.conns, conn)
+			server.mu.Unlock()
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		args, err := readCommand(reader)
+
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args)
+
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			if value, exists := s.values[args[1]]; exists {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				io.WriteString(conn, "$-1\r\n")
+			}
+		case "SET":
+			s.values[args[1]] = args[2]
+			io.WriteString(conn, "+OK\r\n")
+		case "AUTH", "SELECT", "CLIENT":
+			io.WriteString(conn, "+OK\r\n")
+		default:
+			io.WriteString(conn, "-ERR unknown command\r\n")
+		}
+
+		s.mu.Unlock()
+	}
+}
+
+// drop closes every connection, as a restart of the server would
+func (s *fakeRedis) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+
+	s.conns = nil
+}
+
+// sent returns the commands received with the given name
+func (s *fakeRedis) sent(name string) [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var commands [][]string
+
+	for _, command := range s.commands {
+		if strings.EqualFold(command[0], name) {
+			commands = append(commands, command)
+		}
+	}
+
+	return commands
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+
+	if err != nil {
+		return nil, err
+	}
+
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, count)
+
+	for i := range args {
+		line, err = reader.ReadString('\n')
+
+		if err != nil {
+			return nil, err
+		}
+
+		length, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		value := make([]byte, length+2)
+
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return nil, err
+		}
+
+		args[i] = string(value[:length])
+	}
+
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	server := startFakeRedis(t)
+
+	redis := NewRedis(RedisConfig{Address: server.listener.Addr().String(), Timeout: time.Second})
+	defer redis.Close()
+
+	_, found, err := redis.Get("key")
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	err = redis.Set("key", []byte("line\r\nbreak"), 1500*time.Millisecond)
+	assert.Nil(t, err)
+
+	value, found, err := redis.Get("key")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("line\r\nbreak"), value)
+
+	assert.Equal(t, [][]string{{"set", "key", "line\r\nbreak", "px", "1500"}}, server.sent("SET"))
+	assert.Empty(t, server.sent("AUTH"))
+	assert.Empty(t, server.sent("SELECT"))
+}
+
+func TestRedis_AuthAndSelect(t *testing.T) {
+	server := startFakeRedis(t)
+
+	redis := NewRedis(RedisConfig{Address: server.listener.Addr().String(), Password: "secret", DB: 2, Timeout: time.Second})
+	defer redis.Close()
+
+	_, _, err := redis.Get("key")
+	assert.Nil(t, err)
+
+	assert.Equal(t, [][]string{{"auth", "secret"}}, server.sent("AUTH"))
+	assert.Equal(t, [][]string{{"select", "2"}}, server.sent("SELECT"))
+}
+
+func TestRedis_Reconnect(t *testing.T) {
+	server := startFakeRedis(t)
+
+	redis := NewRedis(RedisConfig{Address: server.listener.Addr().String(), Password: "secret", Timeout: time.Second})
+	defer redis.Close()
+
+	assert.Nil(t, redis.Set("key", []byte("value"), time.Minute))
+
+	server.drop()
+
+	value, found, err := redis.Get("key")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("value"), value)
+
+	// The new connection is authenticated as well
+	assert.Len(t, server.sent("AUTH"), 2)
+}
+
+func TestRedis_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	redis := NewRedis(RedisConfig{Address: address, Timeout: time.Second})
+	defer redis.Close()
+
+	_, _, err = redis.Get("key")
+	assert.ErrorIs(t, err, ErrBackend)
+
+	err = redis.Set("key", []byte("value"), time.Minute)
+	assert.ErrorIs(t, err, ErrBackend)
+}
@@ -6,61 +6,160 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/fgouvea/weather/weather-service/weather"
 	"golang.org/x/net/html/charset"
 )
 
+// CPTEC serves wave forecasts for today and the next 5 days
+const maxWaveDays = 6
+
 const (
-	getCitiesURL  = "/XML/listaCidades?city=%s"
-	getWeatherURL = "/XML/cidade/%s/previsao.xml"
-	getWaveURL    = "/XML/cidade/%s/dia/%d/ondas.xml"
+	getCitiesURL   = "/XML/listaCidades?city=%s"
+	getLatLonURL   = "/XML/cidade/7dias/%s/%s/previsaoLatLon.xml"
+	getWeatherURL  = "/XML/cidade/%s/previsao.xml"
+	getExtendedURL = "/XML/cidade/%s/estendida.xml"
+	getWaveURL     = "/XML/cidade/%s/dia/%d/ondas.xml"
+	getStationURL  = "/XML/estacao/%s/condicoesAtuais.xml"
 )
 
 var ErrReadingResponse = errors.New("error reading api response")
-var ErrFetchingResponse = errors.New("error fetching cptec response")
+var ErrFetchingResponse = fmt.Errorf("%w: error fetching cptec response", weather.ErrProviderUnavailable)
+var ErrInvalidWaveDays = errors.New("invalid number of wave forecast days")
 
 type Client struct {
 	Client *http.Client
 
-	getCitiesURL  string
-	getWeatherURL string
-	getWaveURL    string
+	getCitiesURL   string
+	getLatLonURL   string
+	getWeatherURL  string
+	getExtendedURL string
+	getWaveURL     string
+	getStationURL  string
 }
 
 func NewClient(httpClient *http.Client, basePath string) *Client {
 	return &Client{
 		Client: httpClient,
 
-		getCitiesURL:  fmt.Sprintf("%s%s", basePath, getCitiesURL),
-		getWeatherURL: fmt.Sprintf("%s%s", basePath, getWeatherURL),
-		getWaveURL:    fmt.Sprintf("%s%s", basePath, getWaveURL),
+		getCitiesURL:   fmt.Sprintf("%s%s", basePath, getCitiesURL),
+		getLatLonURL:   fmt.Sprintf("%s%s", basePath, getLatLonURL),
+		getWeatherURL:  fmt.Sprintf("%s%s", basePath, getWeatherURL),
+		getExtendedURL: fmt.Sprintf("%s%s", basePath, getExtendedURL),
+		getWaveURL:     fmt.Sprintf("%s%s", basePath, getWaveURL),
+		getStationURL:  fmt.Sprintf("%s%s", basePath, getStationURL),
+	}
+}
+
+func (c *Client) FindCity(name, state string) (weather.City, error) {
+	parsedResponse, err := c.listCities(name)
+
+	if err != nil {
+		return weather.City{}, err
+	}
+
+	parsedCity, err := chooseCity(parsedResponse, name, state)
+
+	if err != nil {
+		return weather.City{}, err
 	}
+
+	return buildCity(parsedCity), nil
+}
+
+// FindCityByID reads the name and state of the city from its forecast, as
+// CPTEC has no other way of looking a city up by ID.
+func (c *Client) FindCityByID(id string) (weather.City, error) {
+	url := fmt.Sprintf(c.getWeatherURL, url.PathEscape(id))
+
+	var parsedResponse CityForecastTO
+
+	err := getFromAPI(c, url, &parsedResponse)
+
+	if err != nil {
+		return weather.City{}, err
+	}
+
+	if parsedResponse.Name == "" || parsedResponse.Name == "null" {
+		return weather.City{}, weather.ErrCityNotFound
+	}
+
+	return weather.City{
+		ID:    id,
+		Name:  parsedResponse.Name,
+		State: parsedResponse.State,
+	}, nil
 }
 
-func (c *Client) FindCity(name string) (weather.City, error) {
-	url := fmt.Sprintf(c.getCitiesURL, url.QueryEscape(name))
+func (c *Client) SearchCities(name string) ([]weather.City, error) {
+	parsedResponse, err := c.listCities(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cities := make([]weather.City, len(parsedResponse.Cities))
+
+	for i, city := range parsedResponse.Cities {
+		cities[i] = buildCity(city)
+	}
+
+	return cities, nil
+}
+
+// listCities searches without accents, which CPTEC does not always match
+func (c *Client) listCities(name string) (CitiesResponseTO, error) {
+	url := fmt.Sprintf(c.getCitiesURL, url.QueryEscape(weather.NormalizeName(name)))
 
 	var parsedResponse CitiesResponseTO
 
 	err := getFromAPI(c, url, &parsedResponse)
 
+	return parsedResponse, err
+}
+
+// FindCityByCoordinates finds the city CPTEC forecasts for at the given
+// coordinates. That forecast carries only the name and state of the city, so
+// its ID comes from searching for both.
+func (c *Client) FindCityByCoordinates(latitude, longitude float64) (weather.City, error) {
+	latLonURL := fmt.Sprintf(c.getLatLonURL, strconv.FormatFloat(latitude, 'f', -1, 64), strconv.FormatFloat(longitude, 'f', -1, 64))
+
+	var forecastResponse CityForecastTO
+
+	err := getFromAPI(c, latLonURL, &forecastResponse)
+
 	if err != nil {
 		return weather.City{}, err
 	}
 
-	parsedCity, err := chooseCity(parsedResponse, name)
+	if forecastResponse.Name == "" || forecastResponse.Name == "null" {
+		return weather.City{}, weather.ErrCityNotFound
+	}
+
+	var citiesResponse CitiesResponseTO
+
+	citiesURL := fmt.Sprintf(c.getCitiesURL, url.QueryEscape(weather.NormalizeName(forecastResponse.Name)))
+
+	err = getFromAPI(c, citiesURL, &citiesResponse)
 
 	if err != nil {
 		return weather.City{}, err
 	}
 
-	return buildCity(parsedCity), nil
+	for _, city := range citiesResponse.Cities {
+		if weather.SameName(city.Name, forecastResponse.Name) && strings.EqualFold(city.State, forecastResponse.State) {
+			return buildCity(city), nil
+		}
+	}
+
+	return weather.City{}, weather.ErrCityNotFound
 }
 
 func (c *Client) GetForecast(id string) (weather.CityForecast, error) {
-	url := fmt.Sprintf(c.getWeatherURL, id)
+	url := fmt.Sprintf(c.getWeatherURL, url.PathEscape(id))
 
 	var parsedResponse CityForecastTO
 
@@ -83,8 +182,67 @@ func (c *Client) GetForecast(id string) (weather.CityForecast, error) {
 	return forecast, nil
 }
 
+func (c *Client) GetExtendedForecast(id string) (weather.CityForecast, error) {
+	url := fmt.Sprintf(c.getExtendedURL, url.PathEscape(id))
+
+	var parsedResponse CityExtendedForecastTO
+
+	err := getFromAPI(c, url, &parsedResponse)
+
+	if err != nil {
+		return weather.CityForecast{}, err
+	}
+
+	if parsedResponse.Name == "null" {
+		return weather.CityForecast{}, weather.ErrCityNotFound
+	}
+
+	return buildCityExtendedForecast(parsedResponse)
+}
+
 func (c *Client) GetWaveForecast(id string) (weather.CityWaveForecast, error) {
-	url := fmt.Sprintf(c.getWaveURL, id, 0)
+	return c.getWaveForecast(id, 0)
+}
+
+// GetWaveForecastRange fetches the days concurrently. Days CPTEC has no
+// forecast for yet cut the range short, unless it is today, in which case
+// the city is not on the coast.
+func (c *Client) GetWaveForecastRange(id string, days int) ([]weather.CityWaveForecast, error) {
+	if days < 1 || days > maxWaveDays {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidWaveDays, days)
+	}
+
+	forecasts := make([]weather.CityWaveForecast, days)
+	errs := make([]error, days)
+
+	var wg sync.WaitGroup
+
+	for day := range days {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			forecasts[day], errs[day] = c.getWaveForecast(id, day)
+		}()
+	}
+
+	wg.Wait()
+
+	for day, err := range errs {
+		if day > 0 && errors.Is(err, weather.ErrCityNotFound) {
+			return forecasts[:day], nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return forecasts, nil
+}
+
+func (c *Client) getWaveForecast(id string, day int) (weather.CityWaveForecast, error) {
+	url := fmt.Sprintf(c.getWaveURL, url.PathEscape(id), day)
 
 	var parsedResponse CityWaveForecastTO
 
@@ -107,6 +265,32 @@ func (c *Client) GetWaveForecast(id string) (weather.CityWaveForecast, error) {
 	return forecast, nil
 }
 
+// GetCurrentConditions reads the latest report of the airport station with the
+// given ICAO code.
+func (c *Client) GetCurrentConditions(station string) (weather.CurrentConditions, error) {
+	url := fmt.Sprintf(c.getStationURL, url.PathEscape(strings.ToUpper(station)))
+
+	var parsedResponse CurrentConditionsTO
+
+	err := getFromAPI(c, url, &parsedResponse)
+
+	if err != nil {
+		return weather.CurrentConditions{}, err
+	}
+
+	if parsedResponse.Station == "" || parsedResponse.Station == "null" {
+		return weather.CurrentConditions{}, weather.ErrStationNotFound
+	}
+
+	conditions, err := buildCurrentConditions(parsedResponse)
+
+	if err != nil {
+		return weather.CurrentConditions{}, fmt.Errorf("%w: %w", ErrReadingResponse, err)
+	}
+
+	return conditions, nil
+}
+
 func getFromAPI[T any](c *Client, url string, parsedResponse *T) error {
 	response, err := c.Client.Get(url)
 
@@ -114,6 +298,8 @@ func getFromAPI[T any](c *Client, url string, parsedResponse *T) error {
 		return fmt.Errorf("%w: %w", ErrFetchingResponse, err)
 	}
 
+	defer response.Body.Close()
+
 	if response.StatusCode != http.StatusOK {
 		return fmt.Errorf("%w: unexpected status code: %d", ErrFetchingResponse, response.StatusCode)
 	}
@@ -130,20 +316,36 @@ func getFromAPI[T any](c *Client, url string, parsedResponse *T) error {
 	return nil
 }
 
-func chooseCity(response CitiesResponseTO, name string) (CityTO, error) {
-	if len(response.Cities) == 0 {
+// chooseCity narrows the cities down to the given state, if any, and then to
+// the ones named exactly like the search, ignoring case and accents.
+func chooseCity(response CitiesResponseTO, name, state string) (CityTO, error) {
+	var candidates []CityTO
+
+	for _, city := range response.Cities {
+		if state == "" || strings.EqualFold(city.State, state) {
+			candidates = append(candidates, city)
+		}
+	}
+
+	if len(candidates) == 0 {
 		return CityTO{}, weather.ErrCityNotFound
 	}
 
-	if len(response.Cities) == 1 {
-		return response.Cities[0], nil
+	if len(candidates) == 1 {
+		return candidates[0], nil
 	}
 
-	for _, city := range response.Cities {
-		if strings.ToLower(city.Name) == strings.ToLower(name) {
-			return city, nil
+	var matches []CityTO
+
+	for _, city := range candidates {
+		if weather.SameName(city.Name, name) {
+			matches = append(matches, city)
 		}
 	}
 
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
 	return CityTO{}, weather.ErrMultipleCities
 }
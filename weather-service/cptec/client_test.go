@@ -14,6 +14,7 @@ import (
 func TestClient_Find(t *testing.T) {
 	tests := []struct {
 		name              string
+		state             string
 		cptecResponseCode int
 		cptecResponse     string
 		expectedResult    weather.City
@@ -55,6 +56,44 @@ func TestClient_Find(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:              "match ignoring accents",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='UTF-8'?><cidades><cidade><nome>Test City Other</nome><uf>XY</uf><id>456</id></cidade><cidade><nome>Tést Cíty</nome><uf>XY</uf><id>123</id></cidade></cidades>",
+			expectedResult: weather.City{
+				ID:    "123",
+				Name:  "Tést Cíty",
+				State: "XY",
+			},
+			expectedError: nil,
+		},
+		{
+			name:              "same name in multiple states",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidades><cidade><nome>Test City</nome><uf>XY</uf><id>123</id></cidade><cidade><nome>Test City</nome><uf>ZW</uf><id>456</id></cidade></cidades>",
+			expectedResult:    weather.City{},
+			expectedError:     weather.ErrMultipleCities,
+		},
+		{
+			name:              "state picks among multiple cities",
+			state:             "zw",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidades><cidade><nome>Test City</nome><uf>XY</uf><id>123</id></cidade><cidade><nome>Test City</nome><uf>ZW</uf><id>456</id></cidade></cidades>",
+			expectedResult: weather.City{
+				ID:    "456",
+				Name:  "Test City",
+				State: "ZW",
+			},
+			expectedError: nil,
+		},
+		{
+			name:              "no city in state",
+			state:             "AB",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidades><cidade><nome>Test City</nome><uf>XY</uf><id>123</id></cidade></cidades>",
+			expectedResult:    weather.City{},
+			expectedError:     weather.ErrCityNotFound,
+		},
 		{
 			name:              "cptec api returns error",
 			cptecResponseCode: 500,
@@ -83,7 +122,7 @@ func TestClient_Find(t *testing.T) {
 
 			client := NewClient(server.Client(), server.URL)
 
-			result, err := client.FindCity("test city")
+			result, err := client.FindCity("Test Cíty", tt.state)
 
 			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
 			assert.Equal(t, tt.expectedResult, result)
@@ -108,6 +147,7 @@ func TestClient_GetForecast(t *testing.T) {
 				Forecast: []weather.Forecast{
 					{
 						Date:           "2025-02-08",
+						Code:           "pn",
 						Weather:        "Parcialmente Nublado",
 						MaxTemperature: 33,
 						MinTemperature: 21,
@@ -115,6 +155,7 @@ func TestClient_GetForecast(t *testing.T) {
 					},
 					{
 						Date:           "2025-02-09",
+						Code:           "c",
 						Weather:        "Chuva",
 						MaxTemperature: 33,
 						MinTemperature: 21,
@@ -122,6 +163,7 @@ func TestClient_GetForecast(t *testing.T) {
 					},
 					{
 						Date:           "2025-02-10",
+						Code:           "pn",
 						Weather:        "Parcialmente Nublado",
 						MaxTemperature: 35,
 						MinTemperature: 21,
@@ -181,6 +223,81 @@ func TestClient_GetForecast(t *testing.T) {
 	}
 }
 
+func TestClient_GetExtendedForecast(t *testing.T) {
+	tests := []struct {
+		name              string
+		cptecResponseCode int
+		cptecResponse     string
+		expectedResult    weather.CityForecast
+		expectedError     error
+	}{
+		{
+			name:              "success",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>Test City</nome><uf>XY</uf><atualizacao>2025-02-07</atualizacao><previsao><dia>2025-02-12</dia><tempo>ps</tempo><maxima>34</maxima><minima>22</minima></previsao><previsao><dia>2025-02-13</dia><tempo>xyz</tempo><maxima>30</maxima><minima>20</minima></previsao></cidade>",
+			expectedResult: weather.CityForecast{
+				UpdatedAt: "2025-02-07",
+				Forecast: []weather.Forecast{
+					{
+						Date:           "2025-02-12",
+						Code:           "ps",
+						Weather:        "Predomínio de Sol",
+						MaxTemperature: 34,
+						MinTemperature: 22,
+					},
+					{
+						Date:           "2025-02-13",
+						Weather:        "Desconhecido",
+						MaxTemperature: 30,
+						MinTemperature: 20,
+					},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:              "city not found",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>null</nome><uf>null</uf><atualizacao>null</atualizacao><previsao><dia>null</dia><tempo>null</tempo><maxima>null</maxima><minima>null</minima></previsao></cidade>",
+			expectedResult:    weather.CityForecast{},
+			expectedError:     weather.ErrCityNotFound,
+		},
+		{
+			name:              "invalid temperature",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>Test City</nome><uf>XY</uf><atualizacao>2025-02-07</atualizacao><previsao><dia>2025-02-12</dia><tempo>ps</tempo><maxima>x</maxima><minima>22</minima></previsao></cidade>",
+			expectedResult:    weather.CityForecast{},
+			expectedError:     ErrReadingResponse,
+		},
+		{
+			name:              "cptec api returns error",
+			cptecResponseCode: 500,
+			cptecResponse:     "Internal Server Error",
+			expectedResult:    weather.CityForecast{},
+			expectedError:     ErrFetchingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/XML/cidade/123/estendida.xml", r.URL.String())
+				w.WriteHeader(tt.cptecResponseCode)
+				w.Write([]byte(tt.cptecResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			result, err := client.GetExtendedForecast("123")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
 func TestClient_GetWaveForecast(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -262,3 +379,366 @@ func TestClient_GetWaveForecast(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetWaveForecastRange(t *testing.T) {
+	const waveResponse = "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>Rio de Janeiro</nome><uf>RJ</uf><atualizacao>07-02-2025</atualizacao><manha><dia>%02d-02-2025 12h Z</dia><agitacao>Fraco</agitacao><altura>0.5</altura><direcao>SE</direcao><vento>4.1</vento><vento_dir>ENE</vento_dir></manha><tarde><dia>%02d-02-2025 18h Z</dia><agitacao>Fraco</agitacao><altura>0.5</altura><direcao>SE</direcao><vento>5.2</vento><vento_dir>E</vento_dir></tarde><noite><dia>%02d-02-2025 21h Z</dia><agitacao>Fraco</agitacao><altura>0.5</altura><direcao>SE</direcao><vento>5.3</vento><vento_dir>E</vento_dir></noite></cidade>"
+	const notFoundResponse = "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>undefined</nome><uf>undefined</uf><atualizacao>00/00/0000 00:00:00</atualizacao></cidade>"
+
+	tests := []struct {
+		name          string
+		days          int
+		availableDays int
+		failingDay    int
+		expectedDates []string
+		expectedError error
+	}{
+		{
+			name:          "all days",
+			days:          3,
+			availableDays: 6,
+			failingDay:    -1,
+			expectedDates: []string{"2025-02-07", "2025-02-08", "2025-02-09"},
+		},
+		{
+			name:          "fewer days available",
+			days:          6,
+			availableDays: 2,
+			failingDay:    -1,
+			expectedDates: []string{"2025-02-07", "2025-02-08"},
+		},
+		{
+			name:          "city not found",
+			days:          2,
+			availableDays: 0,
+			failingDay:    -1,
+			expectedError: weather.ErrCityNotFound,
+		},
+		{
+			name:          "cptec api returns error for one day",
+			days:          4,
+			availableDays: 6,
+			failingDay:    2,
+			expectedError: ErrFetchingResponse,
+		},
+		{
+			name:          "too many days",
+			days:          7,
+			expectedError: ErrInvalidWaveDays,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var day int
+				fmt.Sscanf(r.URL.String(), "/XML/cidade/123/dia/%d/ondas.xml", &day)
+
+				switch {
+				case day == tt.failingDay:
+					w.WriteHeader(http.StatusInternalServerError)
+				case day >= tt.availableDays:
+					w.Write([]byte(notFoundResponse))
+				default:
+					w.Write([]byte(fmt.Sprintf(waveResponse, 7+day, 7+day, 7+day)))
+				}
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			result, err := client.GetWaveForecastRange("123", tt.days)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+
+			var dates []string
+			for _, forecast := range result {
+				dates = append(dates, forecast.Date)
+			}
+
+			assert.Equal(t, tt.expectedDates, dates)
+		})
+	}
+}
+
+func TestClient_GetCurrentConditions(t *testing.T) {
+	tests := []struct {
+		name              string
+		cptecResponseCode int
+		cptecResponse     string
+		expectedResult    weather.CurrentConditions
+		expectedError     error
+	}{
+		{
+			name:              "success",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><metar><codigo>SBRJ</codigo><atualizacao>07/02/2025 12:00:00</atualizacao><pressao>1012</pressao><temperatura>28</temperatura><tempo>ps</tempo><tempo_desc>Predomínio de Sol</tempo_desc><umidade>70</umidade><vento_dir>140</vento_dir><vento_int>15</vento_int><visibilidade>>10000</visibilidade></metar>",
+			expectedResult: weather.CurrentConditions{
+				Station:       "SBRJ",
+				UpdatedAt:     "2025-02-07T12:00:00",
+				Weather:       "Predomínio de Sol",
+				Temperature:   28,
+				Pressure:      1012,
+				Humidity:      70,
+				WindSpeed:     15,
+				WindDirection: 140,
+				Visibility:    10000,
+			},
+			expectedError: nil,
+		},
+		{
+			name:              "station not found",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><metar><codigo>null</codigo></metar>",
+			expectedResult:    weather.CurrentConditions{},
+			expectedError:     weather.ErrStationNotFound,
+		},
+		{
+			name:              "invalid temperature",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><metar><codigo>SBRJ</codigo><atualizacao>07/02/2025 12:00:00</atualizacao><pressao>1012</pressao><temperatura>x</temperatura><tempo>ps</tempo><umidade>70</umidade><vento_dir>140</vento_dir><vento_int>15</vento_int><visibilidade>9000</visibilidade></metar>",
+			expectedResult:    weather.CurrentConditions{},
+			expectedError:     ErrReadingResponse,
+		},
+		{
+			name:              "cptec api returns error",
+			cptecResponseCode: 500,
+			cptecResponse:     "Internal Server Error",
+			expectedResult:    weather.CurrentConditions{},
+			expectedError:     ErrFetchingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/XML/estacao/SBRJ/condicoesAtuais.xml", r.URL.String())
+				w.WriteHeader(tt.cptecResponseCode)
+				w.Write([]byte(tt.cptecResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			result, err := client.GetCurrentConditions("sbrj")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_FindCityByCoordinates(t *testing.T) {
+	const latLonResponse = "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>Santa Maria</nome><uf>RS</uf><atualizacao>2025-02-07</atualizacao></cidade>"
+	const citiesResponse = "<?xml version='1.0' encoding='ISO-8859-1'?><cidades><cidade><nome>Santa Maria</nome><uf>DF</uf><id>4600</id></cidade><cidade><nome>Santa Maria</nome><uf>RS</uf><id>4599</id></cidade></cidades>"
+
+	tests := []struct {
+		name           string
+		latLonCode     int
+		latLonResponse string
+		citiesResponse string
+		expectedResult weather.City
+		expectedError  error
+	}{
+		{
+			name:           "success",
+			latLonCode:     200,
+			latLonResponse: latLonResponse,
+			citiesResponse: citiesResponse,
+			expectedResult: weather.City{ID: "4599", Name: "Santa Maria", State: "RS"},
+		},
+		{
+			name:           "no city at coordinates",
+			latLonCode:     200,
+			latLonResponse: "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>null</nome><uf>null</uf></cidade>",
+			expectedError:  weather.ErrCityNotFound,
+		},
+		{
+			name:           "city missing from search",
+			latLonCode:     200,
+			latLonResponse: latLonResponse,
+			citiesResponse: "<?xml version='1.0' encoding='ISO-8859-1'?><cidades><cidade><nome>Santa Maria</nome><uf>DF</uf><id>4600</id></cidade></cidades>",
+			expectedError:  weather.ErrCityNotFound,
+		},
+		{
+			name:          "cptec api returns error",
+			latLonCode:    500,
+			expectedError: ErrFetchingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.String() {
+				case "/XML/cidade/7dias/-29.6842/-53.8069/previsaoLatLon.xml":
+					w.WriteHeader(tt.latLonCode)
+					w.Write([]byte(tt.latLonResponse))
+				// Searched like the names typed by users
+				case "/XML/listaCidades?city=santa+maria":
+					w.Write([]byte(tt.citiesResponse))
+				default:
+					t.Errorf("unexpected request: %s", r.URL.String())
+				}
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			result, err := client.FindCityByCoordinates(-29.6842, -53.8069)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_FindCityByID(t *testing.T) {
+	tests := []struct {
+		name              string
+		cptecResponseCode int
+		cptecResponse     string
+		expectedResult    weather.City
+		expectedError     error
+	}{
+		{
+			name:              "success",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>Test City</nome><uf>XY</uf><atualizacao>2025-02-07</atualizacao></cidade>",
+			expectedResult:    weather.City{ID: "123", Name: "Test City", State: "XY"},
+		},
+		{
+			name:              "city not found",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidade><nome>null</nome><uf>null</uf></cidade>",
+			expectedError:     weather.ErrCityNotFound,
+		},
+		{
+			name:              "cptec api returns error",
+			cptecResponseCode: 500,
+			cptecResponse:     "Internal Server Error",
+			expectedError:     ErrFetchingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/XML/cidade/123/previsao.xml", r.URL.String())
+				w.WriteHeader(tt.cptecResponseCode)
+				w.Write([]byte(tt.cptecResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			result, err := client.FindCityByID("123")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_EscapesCityID(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(client *Client) error
+		expectedPath string
+	}{
+		{
+			name: "forecast",
+			call: func(client *Client) error {
+				_, err := client.GetForecast("12/3?a")
+				return err
+			},
+			expectedPath: "/XML/cidade/12%2F3%3Fa/previsao.xml",
+		},
+		{
+			name: "extended forecast",
+			call: func(client *Client) error {
+				_, err := client.GetExtendedForecast("12/3?a")
+				return err
+			},
+			expectedPath: "/XML/cidade/12%2F3%3Fa/estendida.xml",
+		},
+		{
+			name: "wave forecast",
+			call: func(client *Client) error {
+				_, err := client.GetWaveForecast("12/3?a")
+				return err
+			},
+			expectedPath: "/XML/cidade/12%2F3%3Fa/dia/0/ondas.xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.expectedPath, r.URL.EscapedPath())
+				assert.Empty(t, r.URL.RawQuery)
+				w.WriteHeader(http.StatusNotFound)
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			assert.NotNil(t, tt.call(client))
+		})
+	}
+}
+
+func TestClient_SearchCities(t *testing.T) {
+	tests := []struct {
+		name              string
+		cptecResponseCode int
+		cptecResponse     string
+		expectedResult    []weather.City
+		expectedError     error
+	}{
+		{
+			name:              "success",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='UTF-8'?><cidades><cidade><nome>São Paulo</nome><uf>SP</uf><id>244</id></cidade><cidade><nome>São Paulo de Olivença</nome><uf>AM</uf><id>5003</id></cidade></cidades>",
+			expectedResult: []weather.City{
+				{ID: "244", Name: "São Paulo", State: "SP"},
+				{ID: "5003", Name: "São Paulo de Olivença", State: "AM"},
+			},
+		},
+		{
+			name:              "no cities",
+			cptecResponseCode: 200,
+			cptecResponse:     "<?xml version='1.0' encoding='ISO-8859-1'?><cidades></cidades>",
+			expectedResult:    []weather.City{},
+		},
+		{
+			name:              "cptec api returns error",
+			cptecResponseCode: 500,
+			cptecResponse:     "Internal Server Error",
+			expectedError:     ErrFetchingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/XML/listaCidades?city=sao+paulo", r.URL.String())
+				w.WriteHeader(tt.cptecResponseCode)
+				w.Write([]byte(tt.cptecResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL)
+
+			result, err := client.SearchCities("São Paulo")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
@@ -52,13 +52,16 @@ func buildForecast(to ForecastTO) (weather.Forecast, error) {
 		return weather.Forecast{}, fmt.Errorf("failed to read IUV: %s", to.IUV)
 	}
 
-	fullWeatherName, exists := WeatherName[to.Weather]
+	code := to.Weather
+	fullWeatherName, exists := WeatherName[code]
 	if !exists {
+		code = ""
 		fullWeatherName = UnknownWeather
 	}
 
 	return weather.Forecast{
 		Date:           to.Date,
+		Code:           code,
 		Weather:        fullWeatherName,
 		MinTemperature: min,
 		MaxTemperature: max,
@@ -66,6 +69,52 @@ func buildForecast(to ForecastTO) (weather.Forecast, error) {
 	}, nil
 }
 
+func buildCityExtendedForecast(to CityExtendedForecastTO) (weather.CityForecast, error) {
+	forecast := make([]weather.Forecast, len(to.Forecast))
+
+	for i, forecastTO := range to.Forecast {
+		parsedForecast, err := buildExtendedForecast(forecastTO)
+
+		if err != nil {
+			return weather.CityForecast{}, fmt.Errorf("%w: %w", ErrReadingResponse, err)
+		}
+
+		forecast[i] = parsedForecast
+	}
+
+	return weather.CityForecast{
+		UpdatedAt: to.Date,
+		Forecast:  forecast,
+	}, nil
+}
+
+func buildExtendedForecast(to ExtendedForecastTO) (weather.Forecast, error) {
+	min, err := strconv.Atoi(to.MinTemperature)
+	if err != nil {
+		return weather.Forecast{}, fmt.Errorf("failed to read min temp: %s", to.MinTemperature)
+	}
+
+	max, err := strconv.Atoi(to.MaxTemperature)
+	if err != nil {
+		return weather.Forecast{}, fmt.Errorf("failed to read max temp: %s", to.MaxTemperature)
+	}
+
+	code := to.Weather
+	fullWeatherName, exists := WeatherName[code]
+	if !exists {
+		code = ""
+		fullWeatherName = UnknownWeather
+	}
+
+	return weather.Forecast{
+		Date:           to.Date,
+		Code:           code,
+		Weather:        fullWeatherName,
+		MinTemperature: min,
+		MaxTemperature: max,
+	}, nil
+}
+
 func buildCityWaveForecast(to CityWaveForecastTO) (weather.CityWaveForecast, error) {
 	morning, err := buildWaveForecast(to.Morning)
 	if err != nil {
@@ -121,6 +170,70 @@ func buildWaveForecast(to WaveForecastTO) (weather.WaveForecast, error) {
 	}, nil
 }
 
+func buildCurrentConditions(to CurrentConditionsTO) (weather.CurrentConditions, error) {
+	pressure, err := readInt("pressure", to.Pressure)
+	if err != nil {
+		return weather.CurrentConditions{}, err
+	}
+
+	temperature, err := readInt("temperature", to.Temperature)
+	if err != nil {
+		return weather.CurrentConditions{}, err
+	}
+
+	humidity, err := readInt("humidity", to.Humidity)
+	if err != nil {
+		return weather.CurrentConditions{}, err
+	}
+
+	windDirection, err := readInt("wind direction", to.WindDirection)
+	if err != nil {
+		return weather.CurrentConditions{}, err
+	}
+
+	windSpeed, err := readInt("wind speed", to.WindSpeed)
+	if err != nil {
+		return weather.CurrentConditions{}, err
+	}
+
+	// Unlimited visibility is reported as ">10000"
+	visibility, err := readInt("visibility", strings.TrimPrefix(to.Visibility, ">"))
+	if err != nil {
+		return weather.CurrentConditions{}, err
+	}
+
+	updatedAt, err := time.Parse("02/01/2006 15:04:05", to.UpdatedAt)
+	if err != nil {
+		return weather.CurrentConditions{}, fmt.Errorf("failed to parse update date: %w", err)
+	}
+
+	fullWeatherName, exists := WeatherName[to.Weather]
+	if !exists {
+		fullWeatherName = UnknownWeather
+	}
+
+	return weather.CurrentConditions{
+		Station:       to.Station,
+		UpdatedAt:     updatedAt.Format("2006-01-02T15:04:05"),
+		Weather:       fullWeatherName,
+		Temperature:   temperature,
+		Pressure:      pressure,
+		Humidity:      humidity,
+		WindSpeed:     windSpeed,
+		WindDirection: windDirection,
+		Visibility:    visibility,
+	}, nil
+}
+
+func readInt(name, value string) (int, error) {
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %s", name, value)
+	}
+
+	return parsed, nil
+}
+
 func fixDate(brDate string) (string, error) {
 	date, err := time.Parse("02-01-2006", brDate)
 
@@ -29,6 +29,22 @@ type ForecastTO struct {
 	IUV            string `xml:"iuv"`
 }
 
+// CityExtendedForecastTO is the 7 day forecast, which has no UV index
+type CityExtendedForecastTO struct {
+	XMLName  xml.Name             `xml:"cidade"`
+	Name     string               `xml:"nome"`
+	State    string               `xml:"uf"`
+	Date     string               `xml:"atualizacao"`
+	Forecast []ExtendedForecastTO `xml:"previsao"`
+}
+
+type ExtendedForecastTO struct {
+	Date           string `xml:"dia"`
+	Weather        string `xml:"tempo"`
+	MaxTemperature string `xml:"maxima"`
+	MinTemperature string `xml:"minima"`
+}
+
 type CityWaveForecastTO struct {
 	XMLName   xml.Name       `xml:"cidade"`
 	Name      string         `xml:"nome"`
@@ -48,6 +64,20 @@ type WaveForecastTO struct {
 	WindDirection string `xml:"vento_dir"`
 }
 
+// CurrentConditionsTO is the latest METAR report of an airport station
+type CurrentConditionsTO struct {
+	XMLName       xml.Name `xml:"metar"`
+	Station       string   `xml:"codigo"`
+	UpdatedAt     string   `xml:"atualizacao"`
+	Pressure      string   `xml:"pressao"`
+	Temperature   string   `xml:"temperatura"`
+	Weather       string   `xml:"tempo"`
+	Humidity      string   `xml:"umidade"`
+	WindDirection string   `xml:"vento_dir"`
+	WindSpeed     string   `xml:"vento_int"`
+	Visibility    string   `xml:"visibilidade"`
+}
+
 var WeatherName = map[string]string{
 	"ec":  "Encoberto com Chuvas Isoladas",
 	"ci":  "Chuvas Isoladas",
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/fgouvea/weather/weather-service/alert"
+)
+
+const alertRuleColumns = "id, user_id, city_id, city_name, state, conditions, min_temperature, max_temperature, iuv, wave_height, wind, days, created_at"
+
+// AlertRepository stores alert rules and the forecast dates already alerted
+// about, sharing the connection of another repository.
+type AlertRepository struct {
+	DbConnection *sql.DB
+}
+
+func NewAlertRepository(dbConnection *sql.DB) *AlertRepository {
+	return &AlertRepository{
+		DbConnection: dbConnection,
+	}
+}
+
+func scanAlertRule(row scanner) (alert.Rule, error) {
+	var rule alert.Rule
+	var conditions string
+	var minTemperature, maxTemperature sql.NullInt64
+	var iuv, waveHeight, wind sql.NullFloat64
+
+	err := row.Scan(&rule.ID, &rule.UserID, &rule.CityID, &rule.CityName, &rule.State, &conditions, &minTemperature, &maxTemperature, &iuv, &waveHeight, &wind, &rule.Days, &rule.CreatedAt)
+
+	if err != nil {
+		return alert.Rule{}, err
+	}
+
+	if conditions != "" {
+		rule.Conditions = strings.Split(conditions, ",")
+	}
+
+	rule.MinTemperature = intFromNull(minTemperature)
+	rule.MaxTemperature = intFromNull(maxTemperature)
+	rule.IUV = floatFromNull(iuv)
+	rule.WaveHeight = floatFromNull(waveHeight)
+	rule.Wind = floatFromNull(wind)
+
+	return rule, nil
+}
+
+func (r *AlertRepository) Save(rule alert.Rule) error {
+	query := `
+	INSERT INTO weather.AlertRules (` + alertRuleColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+	`
+
+	_, err := r.DbConnection.Exec(query,
+		rule.ID, rule.UserID, rule.CityID, rule.CityName, rule.State, strings.Join(rule.Conditions, ","),
+		nullFromInt(rule.MinTemperature), nullFromInt(rule.MaxTemperature),
+		nullFromFloat(rule.IUV), nullFromFloat(rule.WaveHeight), nullFromFloat(rule.Wind),
+		rule.Days, rule.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r *AlertRepository) Delete(id string) error {
+	_, err := r.DbConnection.Exec(`DELETE FROM weather.AlertRules WHERE id = $1;`, id)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r *AlertRepository) Find(id string) (alert.Rule, error) {
+	query := `
+	SELECT ` + alertRuleColumns + ` FROM weather.AlertRules
+	WHERE id = $1;
+	`
+
+	result, err := scanAlertRule(r.DbConnection.QueryRow(query, id))
+
+	if err == sql.ErrNoRows {
+		return alert.Rule{}, alert.ErrRuleNotFound
+	}
+
+	if err != nil {
+		return alert.Rule{}, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return result, nil
+}
+
+func (r *AlertRepository) FindAll(userID, cityID string) ([]alert.Rule, error) {
+	query := `
+	SELECT ` + alertRuleColumns + ` FROM weather.AlertRules
+	WHERE user_id = $1 AND ($2 = '' OR city_id = $2)
+	ORDER BY created_at, id;
+	`
+
+	rows, err := r.DbConnection.Query(query, userID, cityID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	result := []alert.Rule{}
+
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+		}
+
+		result = append(result, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return result, nil
+}
+
+func (r *AlertRepository) WasSent(ruleID, date string) (bool, error) {
+	var sent bool
+
+	err := r.DbConnection.QueryRow(`SELECT EXISTS (SELECT 1 FROM weather.AlertsSent WHERE rule_id = $1 AND forecast_date = $2);`, ruleID, date).Scan(&sent)
+
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return sent, nil
+}
+
+func (r *AlertRepository) MarkSent(ruleID, date string) error {
+	query := `
+	INSERT INTO weather.AlertsSent (rule_id, forecast_date)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;
+	`
+
+	_, err := r.DbConnection.Exec(query, ruleID, date)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func intFromNull(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+
+	result := int(value.Int64)
+	return &result
+}
+
+func floatFromNull(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Float64
+}
+
+func nullFromInt(value *int) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(*value), Valid: true}
+}
+
+func nullFromFloat(value *float64) sql.NullFloat64 {
+	if value == nil {
+		return sql.NullFloat64{}
+	}
+
+	return sql.NullFloat64{Float64: *value, Valid: true}
+}
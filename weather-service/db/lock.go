@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// AdvisoryLocker implements schedule.Locker with a session-level Postgres
+// advisory lock. The lock lives as long as the dedicated connection holding
+// it: every Acquire while held pings that connection to check the lock was not
+// lost, and if the holder dies its session ends and the lock is freed for the
+// others.
+type AdvisoryLocker struct {
+	DbConnection *sql.DB
+	Key          int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewAdvisoryLocker(dbConnection *sql.DB, name string) *AdvisoryLocker {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+
+	return &AdvisoryLocker{
+		DbConnection: dbConnection,
+		Key:          int64(hash.Sum64()),
+	}
+}
+
+func (l *AdvisoryLocker) Acquire() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ctx := context.Background()
+
+	if l.conn != nil {
+		err := l.conn.PingContext(ctx)
+
+		if err == nil {
+			return true, nil
+		}
+
+		// Discard the connection instead of returning it to the pool, so a
+		// session that is somehow still alive cannot keep the lock
+		l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		l.conn.Close()
+		l.conn = nil
+
+		return false, fmt.Errorf("%w: lost lock connection: %w", ErrExecuteQuery, err)
+	}
+
+	conn, err := l.DbConnection.Conn(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrConnectDB, err)
+	}
+
+	var acquired bool
+
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1);", l.Key).Scan(&acquired)
+
+	if err != nil || !acquired {
+		conn.Close()
+
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+		}
+
+		return false, nil
+	}
+
+	l.conn = conn
+
+	return true, nil
+}
+
+func (l *AdvisoryLocker) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	defer func() {
+		l.conn.Close()
+		l.conn = nil
+	}()
+
+	_, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1);", l.Key)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/fgouvea/weather/weather-service/revision"
+)
+
+const subscriptionColumns = "id, user_id, city_id, city_name, state, threshold, days, created_at"
+
+// RevisionRepository stores forecast revision subscriptions and, for each of
+// them and each date, the forecast last sent to the subscriber, sharing the connection of another
+// repository.
+type RevisionRepository struct {
+	DbConnection *sql.DB
+}
+
+func NewRevisionRepository(dbConnection *sql.DB) *RevisionRepository {
+	return &RevisionRepository{
+		DbConnection: dbConnection,
+	}
+}
+
+func scanSubscription(row scanner) (revision.Subscription, error) {
+	var subscription revision.Subscription
+
+	err := row.Scan(&subscription.ID, &subscription.UserID, &subscription.CityID, &subscription.CityName, &subscription.State, &subscription.Threshold, &subscription.Days, &subscription.CreatedAt)
+
+	return subscription, err
+}
+
+func (r *RevisionRepository) Save(subscription revision.Subscription) error {
+	query := `
+	INSERT INTO weather.RevisionSubscriptions (` + subscriptionColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	_, err := r.DbConnection.Exec(query,
+		subscription.ID, subscription.UserID, subscription.CityID, subscription.CityName, subscription.State,
+		subscription.Threshold, subscription.Days, subscription.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r *RevisionRepository) Delete(id string) error {
+	_, err := r.DbConnection.Exec(`DELETE FROM weather.RevisionSubscriptions WHERE id = $1;`, id)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r *RevisionRepository) Find(id string) (revision.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + ` FROM weather.RevisionSubscriptions
+	WHERE id = $1;
+	`
+
+	result, err := scanSubscription(r.DbConnection.QueryRow(query, id))
+
+	if err == sql.ErrNoRows {
+		return revision.Subscription{}, revision.ErrSubscriptionNotFound
+	}
+
+	if err != nil {
+		return revision.Subscription{}, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return result, nil
+}
+
+func (r *RevisionRepository) FindAll(userID string) ([]revision.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + ` FROM weather.RevisionSubscriptions
+	WHERE user_id = $1
+	ORDER BY created_at, id;
+	`
+
+	return r.findSubscriptions(query, userID)
+}
+
+func (r *RevisionRepository) FindByCity(cityID string) ([]revision.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + ` FROM weather.RevisionSubscriptions
+	WHERE city_id = $1
+	ORDER BY created_at, id;
+	`
+
+	return r.findSubscriptions(query, cityID)
+}
+
+func (r *RevisionRepository) findSubscriptions(query string, args ...any) ([]revision.Subscription, error) {
+	rows, err := r.DbConnection.Query(query, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	result := []revision.Subscription{}
+
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+		}
+
+		result = append(result, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return result, nil
+}
+
+func (r *RevisionRepository) FindCities() ([]string, error) {
+	rows, err := r.DbConnection.Query(`SELECT DISTINCT city_id FROM weather.RevisionSubscriptions ORDER BY city_id;`)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	result := []string{}
+
+	for rows.Next() {
+		var cityID string
+
+		if err := rows.Scan(&cityID); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+		}
+
+		result = append(result, cityID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return result, nil
+}
+
+func (r *RevisionRepository) FindSnapshots(subscriptionID string) ([]revision.Snapshot, error) {
+	query := `
+	SELECT subscription_id, forecast_date, updated_at, code, weather, min_temperature, max_temperature
+	FROM weather.RevisionSnapshots
+	WHERE subscription_id = $1
+	ORDER BY forecast_date;
+	`
+
+	rows, err := r.DbConnection.Query(query, subscriptionID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	result := []revision.Snapshot{}
+
+	for rows.Next() {
+		var snapshot revision.Snapshot
+
+		err := rows.Scan(&snapshot.SubscriptionID, &snapshot.Date, &snapshot.UpdatedAt, &snapshot.Code, &snapshot.Weather, &snapshot.Min, &snapshot.Max)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+		}
+
+		result = append(result, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return result, nil
+}
+
+// SaveSnapshots replaces the snapshots of the given dates and drops those of
+// dates before them, which are in the past and will not be fetched again.
+func (r *RevisionRepository) SaveSnapshots(subscriptionID string, snapshots []revision.Snapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := r.DbConnection.Begin()
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM weather.RevisionSnapshots WHERE subscription_id = $1 AND forecast_date < $2;`, subscriptionID, snapshots[0].Date)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	query := `
+	INSERT INTO weather.RevisionSnapshots (subscription_id, forecast_date, updated_at, code, weather, min_temperature, max_temperature)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (subscription_id, forecast_date) DO UPDATE
+	SET updated_at = EXCLUDED.updated_at, code = EXCLUDED.code, weather = EXCLUDED.weather,
+		min_temperature = EXCLUDED.min_temperature, max_temperature = EXCLUDED.max_temperature;
+	`
+
+	for _, snapshot := range snapshots {
+		_, err = tx.Exec(query, subscriptionID, snapshot.Date, snapshot.UpdatedAt, snapshot.Code, snapshot.Weather, snapshot.Min, snapshot.Max)
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return nil
+}
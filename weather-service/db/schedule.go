@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/fgouvea/weather/weather-service/schedule"
+	"github.com/fgouvea/weather/weather-service/weather"
 	_ "github.com/lib/pq"
 )
 
@@ -15,6 +16,8 @@ var (
 	ErrExecuteQuery = errors.New("error executing query")
 )
 
+const scheduleColumns = "id, user_id, city_name, status, time, recurrence, timezone, days, attempts, last_error, claimed_at, latitude, longitude, city_id, state, alerts_only"
+
 type ScheduleRepository struct {
 	DbConnection *sql.DB
 }
@@ -38,47 +41,147 @@ func NewScheduleRepository(host, port, user, password, database string) (*Schedu
 	}, nil
 }
 
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSchedule(row scanner) (schedule.Schedule, error) {
+	var scheduleID, userID, cityName, status, recurrence, timezone, lastError, cityID, state string
+	var scheduleTime time.Time
+	var days, attempts int
+	var alertsOnly bool
+	var claimedAt sql.NullTime
+	var latitude, longitude sql.NullFloat64
+
+	err := row.Scan(&scheduleID, &userID, &cityName, &status, &scheduleTime, &recurrence, &timezone, &days, &attempts, &lastError, &claimedAt, &latitude, &longitude, &cityID, &state, &alertsOnly)
+
+	if err != nil {
+		return schedule.Schedule{}, err
+	}
+
+	var coordinates *weather.Coordinates
+
+	if latitude.Valid && longitude.Valid {
+		coordinates = &weather.Coordinates{Latitude: latitude.Float64, Longitude: longitude.Float64}
+	}
+
+	return schedule.Schedule{
+		ID:          scheduleID,
+		UserID:      userID,
+		CityID:      cityID,
+		CityName:    cityName,
+		State:       state,
+		Coordinates: coordinates,
+		Status:      status,
+		Time:        scheduleTime,
+		Recurrence:  recurrence,
+		Timezone:    timezone,
+		Days:        days,
+		AlertsOnly:  alertsOnly,
+		Attempts:    attempts,
+		LastError:   lastError,
+		ClaimedAt:   claimedAt.Time,
+	}, nil
+}
+
+func scanSchedules(rows *sql.Rows) ([]schedule.Schedule, error) {
+	defer rows.Close()
+
+	result := []schedule.Schedule{}
+
+	for rows.Next() {
+		schdl, err := scanSchedule(rows)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+		}
+
+		result = append(result, schdl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	return result, nil
+}
+
 func (r *ScheduleRepository) Find(id string) (schedule.Schedule, error) {
 	query := `
-	SELECT id, user_id, city_name, status, time FROM weather.Schedules
+	SELECT ` + scheduleColumns + ` FROM weather.Schedules
 	WHERE id = $1;
 	`
 
-	var scheduleID, userID, cityName, status string
-	var scheduleTime time.Time
-
-	err := r.DbConnection.QueryRow(query, id).Scan(&scheduleID, &userID, &cityName, &status, &scheduleTime)
+	result, err := scanSchedule(r.DbConnection.QueryRow(query, id))
 
 	if err == sql.ErrNoRows {
-		return schedule.Schedule{}, errors.New("could not find schedule")
+		return schedule.Schedule{}, schedule.ErrScheduleNotFound
 	}
 
 	if err != nil {
 		return schedule.Schedule{}, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
 	}
 
-	return schedule.Schedule{
-		ID:       scheduleID,
-		UserID:   userID,
-		CityName: cityName,
-		Status:   status,
-		Time:     scheduleTime,
-	}, nil
+	return result, nil
+}
+
+func (r *ScheduleRepository) FindAll(filter schedule.Filter) ([]schedule.Schedule, int, error) {
+	where := `
+	WHERE ($1 = '' OR user_id = $1) AND ($2 = '' OR status = $2)
+	`
+
+	var total int
+
+	err := r.DbConnection.QueryRow(`SELECT COUNT(*) FROM weather.Schedules`+where, filter.UserID, filter.Status).Scan(&total)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	query := `SELECT ` + scheduleColumns + ` FROM weather.Schedules` + where + `
+	ORDER BY time, id
+	LIMIT $3 OFFSET $4;
+	`
+
+	rows, err := r.DbConnection.Query(query, filter.UserID, filter.Status, filter.PageSize, (filter.Page-1)*filter.PageSize)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
+	}
+
+	result, err := scanSchedules(rows)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, total, nil
 }
 
 func (r *ScheduleRepository) Save(s schedule.Schedule) error {
 	query := `
-	INSERT INTO weather.Schedules (id, user_id, city_name, status, time)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO weather.Schedules (` + scheduleColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	ON CONFLICT(id)
 	DO UPDATE SET
 		user_id = $2,
 		city_name = $3,
 		status = $4,
-		time = $5;
+		time = $5,
+		recurrence = $6,
+		timezone = $7,
+		days = $8,
+		attempts = $9,
+		last_error = $10,
+		claimed_at = $11,
+		latitude = $12,
+		longitude = $13,
+		city_id = $14,
+		state = $15,
+		alerts_only = $16;
 	`
 
-	_, err := r.DbConnection.Query(query, s.ID, s.UserID, s.CityName, s.Status, s.Time)
+	_, err := r.DbConnection.Exec(query, scheduleValues(s)...)
 
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrExecuteQuery, err)
@@ -87,48 +190,95 @@ func (r *ScheduleRepository) Save(s schedule.Schedule) error {
 	return nil
 }
 
-func (r *ScheduleRepository) FindAllBefore(t time.Time) ([]schedule.Schedule, error) {
+func (r *ScheduleRepository) SaveIfStatus(s schedule.Schedule, status string) (bool, error) {
 	query := `
-	SELECT id, user_id, city_name, status, time FROM weather.Schedules
-	WHERE status = 'active' AND time < $1;
+	UPDATE weather.Schedules SET
+		user_id = $2,
+		city_name = $3,
+		status = $4,
+		time = $5,
+		recurrence = $6,
+		timezone = $7,
+		days = $8,
+		attempts = $9,
+		last_error = $10,
+		claimed_at = $11,
+		latitude = $12,
+		longitude = $13,
+		city_id = $14,
+		state = $15,
+		alerts_only = $16
+	WHERE id = $1 AND status = $17;
 	`
 
-	rows, err := r.DbConnection.Query(query, t)
+	result, err := r.DbConnection.Exec(query, append(scheduleValues(s), status)...)
 
-	if err == sql.ErrNoRows {
-		return []schedule.Schedule{}, nil
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
 	}
 
+	affected, err := result.RowsAffected()
+
 	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
 	}
 
-	defer rows.Close()
+	return affected > 0, nil
+}
 
-	var result []schedule.Schedule
+func scheduleValues(s schedule.Schedule) []any {
+	claimedAt := sql.NullTime{Time: s.ClaimedAt, Valid: !s.ClaimedAt.IsZero()}
 
-	for rows.Next() {
-		var scheduleID, userID, cityName, status string
-		var scheduleTime time.Time
+	var latitude, longitude sql.NullFloat64
 
-		rows.Scan(&scheduleID, &userID, &cityName, &status, &scheduleTime)
+	if s.Coordinates != nil {
+		latitude = sql.NullFloat64{Float64: s.Coordinates.Latitude, Valid: true}
+		longitude = sql.NullFloat64{Float64: s.Coordinates.Longitude, Valid: true}
+	}
 
-		schdl := schedule.Schedule{
-			ID:       scheduleID,
-			UserID:   userID,
-			CityName: cityName,
-			Status:   status,
-			Time:     scheduleTime,
-		}
+	return []any{s.ID, s.UserID, s.CityName, s.Status, s.Time, s.Recurrence, s.Timezone, s.Days, s.Attempts, s.LastError, claimedAt, latitude, longitude, s.CityID, s.State, s.AlertsOnly}
+}
 
-		result = append(result, schdl)
+// ClaimDue moves up to limit active schedules due at the given time to
+// processing and returns them. Rows locked by a concurrent claim are skipped,
+// so each schedule is claimed by exactly one caller.
+func (r *ScheduleRepository) ClaimDue(now time.Time, limit int) ([]schedule.Schedule, error) {
+	query := `
+	UPDATE weather.Schedules SET status = 'processing', attempts = attempts + 1, claimed_at = $1
+	WHERE id IN (
+		SELECT id FROM weather.Schedules
+		WHERE status = 'active' AND time <= $1
+		ORDER BY time
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + scheduleColumns + `;
+	`
+
+	rows, err := r.DbConnection.Query(query, now, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
 	}
 
+	return scanSchedules(rows)
+}
+
+func (r *ScheduleRepository) FindStuck(claimedBefore time.Time, limit int) ([]schedule.Schedule, error) {
+	query := `
+	SELECT ` + scheduleColumns + ` FROM weather.Schedules
+	WHERE status = 'processing' AND claimed_at < $1
+	ORDER BY claimed_at
+	LIMIT $2;
+	`
+
+	rows, err := r.DbConnection.Query(query, claimedBefore, limit)
+
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrExecuteQuery, err)
 	}
 
-	return result, nil
+	return scanSchedules(rows)
 }
 
 func (r *ScheduleRepository) Close() {
@@ -0,0 +1,270 @@
+package message
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultLocale is used for users without a locale, or with one that has no
+// templates.
+const DefaultLocale = "pt-BR"
+
+var (
+	ErrLoadingTemplates = errors.New("error loading message templates")
+	ErrUnknownTemplate  = errors.New("unknown message template")
+	ErrRendering        = errors.New("error rendering message")
+)
+
+//go:embed templates
+var embedded embed.FS
+
+// Content is a message written for each kind of channel
+type Content struct {
+	Text     string
+	Markdown string
+	HTML     string
+}
+
+// locale is the wording of every message in one language. Its locale.json
+// sets how dates are written and translates the names that reach the
+// templates in Portuguese, such as weather conditions.
+type locale struct {
+	templates    *template.Template
+	html         *htmltemplate.Template
+	dateLayout   string
+	translations map[string]string
+}
+
+type localeFile struct {
+	DateLayout   string            `json:"dateLayout"`
+	Translations map[string]string `json:"translations"`
+}
+
+// Renderer writes notifications from template files. Each locale is a
+// directory, named after it, holding a locale.json and, for each message, a
+// .tmpl with its plain text, a .md.tmpl with its Markdown and a .html.tmpl
+// with its HTML. The HTML is escaped with html/template.
+type Renderer struct {
+	locales map[string]*locale
+	names   []string
+}
+
+// NewEmbeddedRenderer uses the templates built into the binary.
+func NewEmbeddedRenderer() (*Renderer, error) {
+	templates, err := fs.Sub(embedded, "templates")
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadingTemplates, err)
+	}
+
+	return NewRenderer(templates)
+}
+
+// NewRenderer loads every locale found at the root of fsys, which must
+// include DefaultLocale.
+func NewRenderer(fsys fs.FS) (*Renderer, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadingTemplates, err)
+	}
+
+	renderer := &Renderer{locales: map[string]*locale{}}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		loaded, err := loadLocale(fsys, entry.Name())
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrLoadingTemplates, entry.Name(), err)
+		}
+
+		renderer.locales[entry.Name()] = loaded
+		renderer.names = append(renderer.names, entry.Name())
+	}
+
+	if _, exists := renderer.locales[DefaultLocale]; !exists {
+		return nil, fmt.Errorf("%w: missing default locale %s", ErrLoadingTemplates, DefaultLocale)
+	}
+
+	slices.Sort(renderer.names)
+
+	return renderer, nil
+}
+
+func loadLocale(fsys fs.FS, name string) (*locale, error) {
+	raw, err := fs.ReadFile(fsys, path.Join(name, "locale.json"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var file localeFile
+
+	err = json.Unmarshal(raw, &file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &locale{
+		dateLayout:   file.DateLayout,
+		translations: file.Translations,
+	}
+
+	funcs := map[string]any{
+		"date": loaded.formatDate,
+		"t":    loaded.translate,
+	}
+
+	loaded.templates = template.New(name).Funcs(funcs)
+	loaded.html = htmltemplate.New(name).Funcs(funcs)
+
+	files, err := fs.Glob(fsys, path.Join(name, "*.tmpl"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		raw, err := fs.ReadFile(fsys, file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasSuffix(file, ".html.tmpl") {
+			_, err = loaded.html.New(path.Base(file)).Parse(string(raw))
+		} else {
+			_, err = loaded.templates.New(path.Base(file)).Parse(string(raw))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return loaded, nil
+}
+
+// Locales lists the locales loaded
+func (r *Renderer) Locales() []string {
+	return slices.Clone(r.names)
+}
+
+// Render executes the templates of a message in the given locale. A locale
+// without templates of its own falls back to another of the same language,
+// so "en-US" is written in "en", and then to DefaultLocale. The plain text is
+// required, while a missing Markdown or HTML template falls back to it.
+func (r *Renderer) Render(localeName, name string, data any) (Content, error) {
+	selected := r.locale(localeName)
+
+	tmpl := selected.templates.Lookup(name + ".tmpl")
+
+	if tmpl == nil {
+		return Content{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	text, err := execute(tmpl, name, data)
+
+	if err != nil {
+		return Content{}, err
+	}
+
+	content := Content{Text: text, Markdown: text, HTML: textToHTML(text)}
+
+	if markdown := selected.templates.Lookup(name + ".md.tmpl"); markdown != nil {
+		content.Markdown, err = execute(markdown, name, data)
+
+		if err != nil {
+			return Content{}, err
+		}
+	}
+
+	if html := selected.html.Lookup(name + ".html.tmpl"); html != nil {
+		content.HTML, err = execute(html, name, data)
+
+		if err != nil {
+			return Content{}, err
+		}
+	}
+
+	return content, nil
+}
+
+// executor is satisfied by both text and HTML templates
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+func execute(tmpl executor, name string, data any) (string, error) {
+	var buffer strings.Builder
+
+	err := tmpl.Execute(&buffer, data)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrRendering, name, err)
+	}
+
+	// Template files end with a newline that is not part of the message
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// textToHTML is the HTML of a message without an HTML template
+func textToHTML(text string) string {
+	return strings.ReplaceAll(htmltemplate.HTMLEscapeString(text), "\n", "<br>\n")
+}
+
+func (r *Renderer) locale(name string) *locale {
+	for _, candidate := range r.names {
+		if strings.EqualFold(candidate, name) {
+			return r.locales[candidate]
+		}
+	}
+
+	language, _, _ := strings.Cut(name, "-")
+
+	for _, candidate := range r.names {
+		candidateLanguage, _, _ := strings.Cut(candidate, "-")
+
+		if language != "" && strings.EqualFold(candidateLanguage, language) {
+			return r.locales[candidate]
+		}
+	}
+
+	return r.locales[DefaultLocale]
+}
+
+// formatDate writes an ISO date in the layout of the locale
+func (l *locale) formatDate(date string) string {
+	d, err := time.Parse("2006-01-02", date)
+
+	if err != nil || l.dateLayout == "" {
+		return date
+	}
+
+	return d.Format(l.dateLayout)
+}
+
+// translate looks a name up in the locale, keeping it as is when there is no
+// translation
+func (l *locale) translate(name string) string {
+	if translated, exists := l.translations[name]; exists {
+		return translated
+	}
+
+	return name
+}
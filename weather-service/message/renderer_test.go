@@ -0,0 +1,196 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTemplates() fstest.MapFS {
+	return fstest.MapFS{
+		"pt-BR/locale.json":   {Data: []byte(`{"dateLayout": "02/01/2006", "translations": {}}`)},
+		"pt-BR/greeting.tmpl": {Data: []byte("Olá {{.}}, hoje é {{date \"2025-02-07\"}} e o tempo é {{t \"Chuva\"}}\n")},
+		"en/locale.json":      {Data: []byte(`{"dateLayout": "01/02/2006", "translations": {"Chuva": "Rain"}}`)},
+		"en/greeting.tmpl":    {Data: []byte("Hello {{.}}, today is {{date \"2025-02-07\"}} and the weather is {{t \"Chuva\"}}\n")},
+		"en-GB/locale.json":   {Data: []byte(`{"dateLayout": "02/01/2006", "translations": {"Chuva": "Rain"}}`)},
+		"en-GB/greeting.tmpl": {Data: []byte("Hello {{.}}, today is {{date \"2025-02-07\"}} and the weather is {{t \"Neve\"}}\n")},
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         string
+		template       string
+		expectedError  error
+		expectedResult string
+	}{
+		{
+			name:           "default locale",
+			locale:         "pt-BR",
+			template:       "greeting",
+			expectedResult: "Olá Fulano, hoje é 07/02/2025 e o tempo é Chuva",
+		},
+		{
+			name:           "empty locale",
+			locale:         "",
+			template:       "greeting",
+			expectedResult: "Olá Fulano, hoje é 07/02/2025 e o tempo é Chuva",
+		},
+		{
+			name:           "translated",
+			locale:         "en",
+			template:       "greeting",
+			expectedResult: "Hello Fulano, today is 02/07/2025 and the weather is Rain",
+		},
+		{
+			name:           "case insensitive",
+			locale:         "EN-gb",
+			template:       "greeting",
+			expectedResult: "Hello Fulano, today is 07/02/2025 and the weather is Neve",
+		},
+		{
+			name:           "same language",
+			locale:         "en-US",
+			template:       "greeting",
+			expectedResult: "Hello Fulano, today is 02/07/2025 and the weather is Rain",
+		},
+		{
+			name:           "unknown locale",
+			locale:         "fr",
+			template:       "greeting",
+			expectedResult: "Olá Fulano, hoje é 07/02/2025 e o tempo é Chuva",
+		},
+		{
+			name:          "unknown template",
+			locale:        "en",
+			template:      "farewell",
+			expectedError: ErrUnknownTemplate,
+		},
+	}
+
+	renderer, err := NewRenderer(testTemplates())
+
+	assert.Nil(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderer.Render(tt.locale, tt.template, "Fulano")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result.Text)
+		})
+	}
+}
+
+func TestRenderer_Render_Formats(t *testing.T) {
+	templates := testTemplates()
+	templates["pt-BR/greeting.md.tmpl"] = &fstest.MapFile{Data: []byte("Olá *{{.}}*\n")}
+	templates["pt-BR/greeting.html.tmpl"] = &fstest.MapFile{Data: []byte("<p>Olá <b>{{.}}</b></p>\n")}
+
+	renderer, err := NewRenderer(templates)
+
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name           string
+		locale         string
+		data           string
+		expectedResult Content
+	}{
+		{
+			name:   "every format",
+			locale: "pt-BR",
+			data:   "Fulano",
+			expectedResult: Content{
+				Text:     "Olá Fulano, hoje é 07/02/2025 e o tempo é Chuva",
+				Markdown: "Olá *Fulano*",
+				HTML:     "<p>Olá <b>Fulano</b></p>",
+			},
+		},
+		{
+			name:   "escaped html",
+			locale: "pt-BR",
+			data:   "<Fulano>",
+			expectedResult: Content{
+				Text:     "Olá <Fulano>, hoje é 07/02/2025 e o tempo é Chuva",
+				Markdown: "Olá *<Fulano>*",
+				HTML:     "<p>Olá <b>&lt;Fulano&gt;</b></p>",
+			},
+		},
+		{
+			name:   "text only",
+			locale: "en",
+			data:   "<Fulano>",
+			expectedResult: Content{
+				Text:     "Hello <Fulano>, today is 02/07/2025 and the weather is Rain",
+				Markdown: "Hello <Fulano>, today is 02/07/2025 and the weather is Rain",
+				HTML:     "Hello &lt;Fulano&gt;, today is 02/07/2025 and the weather is Rain",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderer.Render(tt.locale, "greeting", tt.data)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestNewRenderer_Error(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates fstest.MapFS
+	}{
+		{
+			name:      "missing default locale",
+			templates: fstest.MapFS{"en/locale.json": {Data: []byte(`{}`)}, "en/greeting.tmpl": {Data: []byte("Hello")}},
+		},
+		{
+			name:      "missing locale file",
+			templates: fstest.MapFS{"pt-BR/greeting.tmpl": {Data: []byte("Olá")}},
+		},
+		{
+			name:      "malformed template",
+			templates: fstest.MapFS{"pt-BR/locale.json": {Data: []byte(`{}`)}, "pt-BR/greeting.tmpl": {Data: []byte("Olá {{.")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRenderer(tt.templates)
+
+			assert.ErrorIs(t, err, ErrLoadingTemplates)
+		})
+	}
+}
+
+// Every locale built in must have every message of the default one
+func TestNewEmbeddedRenderer(t *testing.T) {
+	renderer, err := NewEmbeddedRenderer()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"en", "es", "pt-BR"}, renderer.Locales())
+
+	expected := renderer.locales[DefaultLocale].templates.Templates()
+
+	for _, name := range renderer.Locales() {
+		for _, tmpl := range expected {
+			assert.NotNil(t, renderer.locales[name].templates.Lookup(tmpl.Name()), fmt.Sprintf("%s is missing %s", name, tmpl.Name()))
+		}
+
+		for _, tmpl := range renderer.locales[DefaultLocale].html.Templates() {
+			if tmpl.Name() == DefaultLocale {
+				continue
+			}
+
+			assert.NotNil(t, renderer.locales[name].html.Lookup(tmpl.Name()), fmt.Sprintf("%s is missing %s", name, tmpl.Name()))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package notification
+
+import "github.com/fgouvea/weather/weather-service/weather"
+
+type PayloadTO struct {
+	Type       string        `json:"type"`
+	City       CityTO        `json:"city"`
+	Conditions *ConditionsTO `json:"conditions,omitempty"`
+	Days       []DayTO       `json:"days"`
+}
+
+type CityTO struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state,omitempty"`
+}
+
+type ConditionsTO struct {
+	Station       string `json:"station"`
+	UpdatedAt     string `json:"updatedAt"`
+	Weather       string `json:"weather"`
+	Temperature   int    `json:"temperature"`
+	Pressure      int    `json:"pressure"`
+	Humidity      int    `json:"humidity"`
+	WindSpeed     int    `json:"windSpeed"`
+	WindDirection int    `json:"windDirection"`
+	Visibility    int    `json:"visibility"`
+}
+
+type DayTO struct {
+	ForecastTO
+	Waves    *WavesTO    `json:"waves,omitempty"`
+	Previous *ForecastTO `json:"previous,omitempty"`
+}
+
+// ForecastTO has the CPTEC condition code, empty when unknown, the
+// temperatures in °C and the risk category of the UV index
+type ForecastTO struct {
+	Date    string  `json:"date"`
+	Code    string  `json:"code,omitempty"`
+	Weather string  `json:"weather"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max"`
+	IUV     float64 `json:"iuv"`
+	UVRisk  string  `json:"uvRisk,omitempty"`
+}
+
+type WavesTO struct {
+	Morning   WavePeriodTO `json:"morning"`
+	Afternoon WavePeriodTO `json:"afternoon"`
+	Evening   WavePeriodTO `json:"evening"`
+}
+
+type WavePeriodTO struct {
+	Swell         string  `json:"swell"`
+	Height        float64 `json:"height"`
+	Direction     string  `json:"direction"`
+	Wind          float64 `json:"wind"`
+	WindDirection string  `json:"windDirection"`
+}
+
+func BuildPayloadTO(payload weather.Payload) PayloadTO {
+	result := PayloadTO{
+		Type: payload.Type,
+		City: CityTO{ID: payload.City.ID, Name: payload.City.Name, State: payload.City.State},
+		Days: make([]DayTO, len(payload.Days)),
+	}
+
+	if payload.Conditions != nil {
+		conditions := ConditionsTO(*payload.Conditions)
+		result.Conditions = &conditions
+	}
+
+	for i, day := range payload.Days {
+		result.Days[i] = DayTO{ForecastTO: buildForecastTO(day.Forecast)}
+
+		if day.Waves != nil {
+			result.Days[i].Waves = &WavesTO{
+				Morning:   buildWavePeriodTO(day.Waves.Morning),
+				Afternoon: buildWavePeriodTO(day.Waves.Afternoon),
+				Evening:   buildWavePeriodTO(day.Waves.Evening),
+			}
+		}
+
+		if day.Previous != nil {
+			previous := buildForecastTO(*day.Previous)
+			// Revisions do not keep the previous UV index
+			previous.UVRisk = ""
+			result.Days[i].Previous = &previous
+		}
+	}
+
+	return result
+}
+
+func buildForecastTO(forecast weather.Forecast) ForecastTO {
+	return ForecastTO{
+		Date:    forecast.Date,
+		Code:    forecast.Code,
+		Weather: forecast.Weather,
+		Min:     forecast.MinTemperature,
+		Max:     forecast.MaxTemperature,
+		IUV:     forecast.IUV,
+		UVRisk:  forecast.UVRisk(),
+	}
+}
+
+func buildWavePeriodTO(wave weather.WaveForecast) WavePeriodTO {
+	return WavePeriodTO{
+		Swell:         wave.Swell,
+		Height:        wave.Height,
+		Direction:     wave.WaveDirection,
+		Wind:          wave.Wind,
+		WindDirection: wave.WindDirection,
+	}
+}
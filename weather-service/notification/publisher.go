@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -13,10 +15,20 @@ var ErrChannel = errors.New("failed to open channel")
 var ErrQueue = errors.New("failed to declare queue")
 var ErrPublish = errors.New("failed to publish message")
 
+// Notification is the message read by notification-service. ID is unique to
+// each notification, so the deliveries retried after a failure can be told
+// from new ones with the same content. Content is the plain text, which every
+// channel can send, and each channel picks the representation it supports
+// among the others. Without a channel,
+// notification-service sends it through every channel enabled by the user.
 type Notification struct {
-	UserID  string `json:"userId"`
-	Content string `json:"content"`
-	Channel string `json:"channel"`
+	ID       string    `json:"id"`
+	UserID   string    `json:"userId"`
+	Content  string    `json:"content"`
+	Markdown string    `json:"markdown"`
+	HTML     string    `json:"html"`
+	Payload  PayloadTO `json:"payload"`
+	Channel  string    `json:"channel,omitempty"`
 }
 
 type Publisher struct {
@@ -58,14 +70,18 @@ func NewPublisher(brokerURL, queueName string) (*Publisher, error) {
 	}, nil
 }
 
-func (p *Publisher) Notify(userId, content string) error {
-	notification := Notification{
-		UserID:  userId,
-		Content: content,
-		Channel: "web",
+func (p *Publisher) Notify(userId string, notification weather.Notification) error {
+	message := Notification{
+		ID:       fmt.Sprintf("NOTIFICATION-%s", uuid.New()),
+		UserID:   userId,
+		Content:  notification.Text,
+		Markdown: notification.Markdown,
+		HTML:     notification.HTML,
+		Payload:  BuildPayloadTO(notification.Payload),
+		Channel:  notification.Channel,
 	}
 
-	body, err := json.Marshal(notification)
+	body, err := json.Marshal(message)
 
 	if err != nil {
 		return fmt.Errorf("%w: failed to encode notification", ErrPublish)
@@ -78,6 +94,7 @@ func (p *Publisher) Notify(userId, content string) error {
 		false,        // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
+			MessageId:   message.ID,
 			Body:        []byte(body),
 		},
 	)
@@ -0,0 +1,254 @@
+package openmeteo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+const (
+	searchPath   = "/v1/search?name=%s&count=10&language=pt&format=json"
+	forecastPath = "/v1/forecast?latitude=%s&longitude=%s&daily=weather_code,temperature_2m_max,temperature_2m_min,uv_index_max&timezone=auto&forecast_days=%d"
+	marinePath   = "/v1/marine?latitude=%s&longitude=%s&hourly=wave_height,wave_direction&timezone=auto&forecast_days=%d"
+
+	forecastDays         = 4
+	extendedForecastDays = 7
+)
+
+var ErrReadingResponse = errors.New("error reading api response")
+var ErrFetchingResponse = fmt.Errorf("%w: error fetching open-meteo response", weather.ErrProviderUnavailable)
+
+// Client talks to Open-Meteo compatible APIs. As these have no forecast by
+// city, the city IDs it hands out are the coordinates of the city.
+type Client struct {
+	Client *http.Client
+
+	searchURL   string
+	forecastURL string
+	marineURL   string
+}
+
+func NewClient(httpClient *http.Client, geocodingHost, forecastHost, marineHost string) *Client {
+	return &Client{
+		Client: httpClient,
+
+		searchURL:   fmt.Sprintf("%s%s", geocodingHost, searchPath),
+		forecastURL: fmt.Sprintf("%s%s", forecastHost, forecastPath),
+		marineURL:   fmt.Sprintf("%s%s", marineHost, marinePath),
+	}
+}
+
+func (c *Client) FindCity(name, state string) (weather.City, error) {
+	parsedResponse, err := c.search(name)
+
+	if err != nil {
+		return weather.City{}, err
+	}
+
+	location, err := chooseLocation(parsedResponse, name, state)
+
+	if err != nil {
+		return weather.City{}, err
+	}
+
+	return buildCity(location), nil
+}
+
+// FindCityByCoordinates needs no request, as the IDs are coordinates already.
+// Without reverse geocoding the city is named after them as well.
+func (c *Client) FindCityByCoordinates(latitude, longitude float64) (weather.City, error) {
+	id := buildID(latitude, longitude)
+
+	return weather.City{
+		ID:   id,
+		Name: strings.ReplaceAll(id, ",", ", "),
+	}, nil
+}
+
+// FindCityByID names the city after its coordinates, like
+// FindCityByCoordinates.
+func (c *Client) FindCityByID(id string) (weather.City, error) {
+	latitude, longitude, err := parseID(id)
+
+	if err != nil {
+		return weather.City{}, err
+	}
+
+	return weather.City{
+		ID:   id,
+		Name: fmt.Sprintf("%s, %s", latitude, longitude),
+	}, nil
+}
+
+func (c *Client) SearchCities(name string) ([]weather.City, error) {
+	parsedResponse, err := c.search(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cities := make([]weather.City, len(parsedResponse.Results))
+
+	for i, location := range parsedResponse.Results {
+		cities[i] = buildCity(location)
+	}
+
+	return cities, nil
+}
+
+func (c *Client) search(name string) (GeocodingResponseTO, error) {
+	url := fmt.Sprintf(c.searchURL, url.QueryEscape(name))
+
+	var parsedResponse GeocodingResponseTO
+
+	err := getFromAPI(c, url, &parsedResponse)
+
+	return parsedResponse, err
+}
+
+func (c *Client) GetForecast(id string) (weather.CityForecast, error) {
+	return c.getForecast(id, forecastDays)
+}
+
+func (c *Client) GetExtendedForecast(id string) (weather.CityForecast, error) {
+	return c.getForecast(id, extendedForecastDays)
+}
+
+func (c *Client) getForecast(id string, days int) (weather.CityForecast, error) {
+	latitude, longitude, err := parseID(id)
+
+	if err != nil {
+		return weather.CityForecast{}, err
+	}
+
+	url := fmt.Sprintf(c.forecastURL, latitude, longitude, days)
+
+	var parsedResponse ForecastResponseTO
+
+	err = getFromAPI(c, url, &parsedResponse)
+
+	if err != nil {
+		return weather.CityForecast{}, err
+	}
+
+	return buildCityForecast(parsedResponse)
+}
+
+func (c *Client) GetWaveForecast(id string) (weather.CityWaveForecast, error) {
+	forecasts, err := c.GetWaveForecastRange(id, 1)
+
+	if err != nil {
+		return weather.CityWaveForecast{}, err
+	}
+
+	return forecasts[0], nil
+}
+
+func (c *Client) GetWaveForecastRange(id string, days int) ([]weather.CityWaveForecast, error) {
+	latitude, longitude, err := parseID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf(c.marineURL, latitude, longitude, days)
+
+	var parsedResponse MarineResponseTO
+
+	err = getFromAPI(c, url, &parsedResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCityWaveForecasts(parsedResponse)
+}
+
+func getFromAPI[T any](c *Client, url string, parsedResponse *T) error {
+	response, err := c.Client.Get(url)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFetchingResponse, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status code: %d", ErrFetchingResponse, response.StatusCode)
+	}
+
+	err = json.NewDecoder(response.Body).Decode(parsedResponse)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrReadingResponse, err)
+	}
+
+	return nil
+}
+
+// chooseLocation narrows the results down to the given state, either a UF or
+// the name of the state, and then to the ones named like the search.
+func chooseLocation(response GeocodingResponseTO, name, state string) (LocationTO, error) {
+	var candidates []LocationTO
+
+	for _, location := range response.Results {
+		if state == "" || inState(location, state) {
+			candidates = append(candidates, location)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return LocationTO{}, weather.ErrCityNotFound
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	// Results come sorted by relevance, so the first exact match is the one
+	for _, location := range candidates {
+		if weather.SameName(location.Name, name) {
+			return location, nil
+		}
+	}
+
+	return LocationTO{}, weather.ErrMultipleCities
+}
+
+func inState(location LocationTO, state string) bool {
+	if weather.SameName(location.Admin1, state) {
+		return true
+	}
+
+	stateName, isUF := StateName[strings.ToUpper(state)]
+
+	return isUF && location.CountryCode == "BR" && weather.SameName(location.Admin1, stateName)
+}
+
+func buildID(latitude, longitude float64) string {
+	return fmt.Sprintf("%s,%s", strconv.FormatFloat(latitude, 'f', 4, 64), strconv.FormatFloat(longitude, 'f', 4, 64))
+}
+
+func parseID(id string) (string, string, error) {
+	latitude, longitude, found := strings.Cut(id, ",")
+
+	if !found {
+		return "", "", weather.ErrCityNotFound
+	}
+
+	if _, err := strconv.ParseFloat(latitude, 64); err != nil {
+		return "", "", weather.ErrCityNotFound
+	}
+
+	if _, err := strconv.ParseFloat(longitude, 64); err != nil {
+		return "", "", weather.ErrCityNotFound
+	}
+
+	return latitude, longitude, nil
+}
@@ -0,0 +1,368 @@
+package openmeteo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_FindCity(t *testing.T) {
+	tests := []struct {
+		name            string
+		state           string
+		apiResponseCode int
+		apiResponse     string
+		expectedResult  weather.City
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"id":3451190,"name":"Test City","latitude":-22.90278,"longitude":-43.2075,"country_code":"BR","admin1":"Rio de Janeiro"}]}`,
+			expectedResult: weather.City{
+				ID:    "-22.9028,-43.2075",
+				Name:  "Test City",
+				State: "Rio de Janeiro",
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "city not found",
+			apiResponseCode: 200,
+			apiResponse:     `{"generationtime_ms":0.5}`,
+			expectedResult:  weather.City{},
+			expectedError:   weather.ErrCityNotFound,
+		},
+		{
+			name:            "api returns multiple cities",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"name":"Test City A","latitude":1,"longitude":2},{"name":"Test City B","latitude":3,"longitude":4}]}`,
+			expectedResult:  weather.City{},
+			expectedError:   weather.ErrMultipleCities,
+		},
+		{
+			name:            "exact match among multiple cities",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"name":"Test City Other","latitude":1,"longitude":2},{"name":"Test City","latitude":38.7167,"longitude":-9.1333,"admin1":"Lisboa"},{"name":"Test City","latitude":3,"longitude":4}]}`,
+			expectedResult: weather.City{
+				ID:    "38.7167,-9.1333",
+				Name:  "Test City",
+				State: "Lisboa",
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "match ignoring accents",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"name":"Test City Other","latitude":1,"longitude":2},{"name":"Tést Cíty","latitude":3,"longitude":4}]}`,
+			expectedResult: weather.City{
+				ID:   "3.0000,4.0000",
+				Name: "Tést Cíty",
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "state by UF",
+			state:           "sp",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"name":"Test City","latitude":1,"longitude":2,"country_code":"BR","admin1":"Rio de Janeiro"},{"name":"Test City","latitude":3,"longitude":4,"country_code":"BR","admin1":"São Paulo"}]}`,
+			expectedResult: weather.City{
+				ID:    "3.0000,4.0000",
+				Name:  "Test City",
+				State: "São Paulo",
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "state by name",
+			state:           "Sao Paulo",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"name":"Test City","latitude":1,"longitude":2,"country_code":"BR","admin1":"Rio de Janeiro"},{"name":"Test City","latitude":3,"longitude":4,"country_code":"BR","admin1":"São Paulo"}]}`,
+			expectedResult: weather.City{
+				ID:    "3.0000,4.0000",
+				Name:  "Test City",
+				State: "São Paulo",
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "no city in state",
+			state:           "MG",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"name":"Test City","latitude":1,"longitude":2,"country_code":"BR","admin1":"Rio de Janeiro"}]}`,
+			expectedResult:  weather.City{},
+			expectedError:   weather.ErrCityNotFound,
+		},
+		{
+			name:            "api returns error",
+			apiResponseCode: 500,
+			apiResponse:     "Internal Server Error",
+			expectedResult:  weather.City{},
+			expectedError:   weather.ErrProviderUnavailable,
+		},
+		{
+			name:            "malformed json",
+			apiResponseCode: 200,
+			apiResponse:     `{"results":[{"name":`,
+			expectedResult:  weather.City{},
+			expectedError:   ErrReadingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/search?name=test+city&count=10&language=pt&format=json", r.URL.String())
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, server.URL)
+
+			result, err := client.FindCity("test city", tt.state)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_GetForecast(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		apiResponse     string
+		expectedResult  weather.CityForecast
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			apiResponse:     `{"daily":{"time":["2025-02-08","2025-02-09"],"weather_code":[2,61],"temperature_2m_max":[32.6,33.1],"temperature_2m_min":[21.4,22],"uv_index_max":[11.2,null]}}`,
+			expectedResult: weather.CityForecast{
+				UpdatedAt: "2025-02-08",
+				Forecast: []weather.Forecast{
+					{
+						Date:           "2025-02-08",
+						Code:           "pn",
+						Weather:        "Parcialmente Nublado",
+						MaxTemperature: 33,
+						MinTemperature: 21,
+						IUV:            11.2,
+					},
+					{
+						Date:           "2025-02-09",
+						Code:           "c",
+						Weather:        "Chuva",
+						MaxTemperature: 33,
+						MinTemperature: 22,
+						IUV:            0,
+					},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "missing temperature",
+			apiResponseCode: 200,
+			apiResponse:     `{"daily":{"time":["2025-02-08"],"weather_code":[2],"temperature_2m_max":[null],"temperature_2m_min":[21.4],"uv_index_max":[11.2]}}`,
+			expectedResult:  weather.CityForecast{},
+			expectedError:   ErrReadingResponse,
+		},
+		{
+			name:            "api returns error",
+			apiResponseCode: 500,
+			apiResponse:     "Internal Server Error",
+			expectedResult:  weather.CityForecast{},
+			expectedError:   ErrFetchingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/forecast?latitude=-22.9028&longitude=-43.2075&daily=weather_code,temperature_2m_max,temperature_2m_min,uv_index_max&timezone=auto&forecast_days=4", r.URL.String())
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, server.URL)
+
+			result, err := client.GetForecast("-22.9028,-43.2075")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_GetExtendedForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/forecast?latitude=-22.9028&longitude=-43.2075&daily=weather_code,temperature_2m_max,temperature_2m_min,uv_index_max&timezone=auto&forecast_days=7", r.URL.String())
+		w.Write([]byte(`{"daily":{"time":["2025-02-08"],"weather_code":[0],"temperature_2m_max":[30],"temperature_2m_min":[20],"uv_index_max":[9]}}`))
+	}))
+
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, server.URL, server.URL)
+
+	result, err := client.GetExtendedForecast("-22.9028,-43.2075")
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Forecast, 1)
+}
+
+func TestClient_GetWaveForecast(t *testing.T) {
+	hours := func(value string) string {
+		result := value
+		for i := 1; i < 24; i++ {
+			result += "," + value
+		}
+		return result
+	}
+
+	times := `"2025-02-07T00:00"`
+	for i := 1; i < 24; i++ {
+		times += fmt.Sprintf(`,"2025-02-07T%02d:00"`, i)
+	}
+
+	tests := []struct {
+		name            string
+		apiResponseCode int
+		apiResponse     string
+		expectedResult  weather.CityWaveForecast
+		expectedError   error
+	}{
+		{
+			name:            "success",
+			apiResponseCode: 200,
+			apiResponse:     fmt.Sprintf(`{"hourly":{"time":[%s],"wave_height":[%s],"wave_direction":[%s]}}`, times, hours("1.4"), hours("135")),
+			expectedResult: weather.CityWaveForecast{
+				UpdatedAt: "2025-02-07",
+				Date:      "2025-02-07",
+				Morning:   weather.WaveForecast{Swell: "Moderado", Height: 1.4, WaveDirection: "SE"},
+				Afternoon: weather.WaveForecast{Swell: "Moderado", Height: 1.4, WaveDirection: "SE"},
+				Evening:   weather.WaveForecast{Swell: "Moderado", Height: 1.4, WaveDirection: "SE"},
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "no marine data",
+			apiResponseCode: 200,
+			apiResponse:     fmt.Sprintf(`{"hourly":{"time":[%s],"wave_height":[%s],"wave_direction":[%s]}}`, times, hours("null"), hours("null")),
+			expectedResult:  weather.CityWaveForecast{},
+			expectedError:   weather.ErrCityNotFound,
+		},
+		{
+			name:            "api returns error",
+			apiResponseCode: 400,
+			apiResponse:     `{"error":true,"reason":"No data is available for this location"}`,
+			expectedResult:  weather.CityWaveForecast{},
+			expectedError:   ErrFetchingResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/marine?latitude=-22.9028&longitude=-43.2075&hourly=wave_height,wave_direction&timezone=auto&forecast_days=1", r.URL.String())
+				w.WriteHeader(tt.apiResponseCode)
+				w.Write([]byte(tt.apiResponse))
+			}))
+
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, server.URL, server.URL)
+
+			result, err := client.GetWaveForecast("-22.9028,-43.2075")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestClient_GetWaveForecastRange(t *testing.T) {
+	// Three days, with the last evening missing
+	var times, heights []string
+
+	for hour := range 3 * 24 {
+		times = append(times, fmt.Sprintf(`"2025-02-%02dT%02d:00"`, 8+hour/24, hour%24))
+
+		if hour == 2*24+21 {
+			heights = append(heights, "null")
+		} else {
+			heights = append(heights, "1.2")
+		}
+	}
+
+	response := fmt.Sprintf(`{"hourly":{"time":[%s],"wave_height":[%s],"wave_direction":[%s]}}`, strings.Join(times, ","), strings.Join(heights, ","), strings.Join(heights, ","))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/marine?latitude=-22.9028&longitude=-43.2075&hourly=wave_height,wave_direction&timezone=auto&forecast_days=3", r.URL.String())
+		w.Write([]byte(response))
+	}))
+
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, server.URL, server.URL)
+
+	result, err := client.GetWaveForecastRange("-22.9028,-43.2075", 3)
+
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "2025-02-08", result[0].Date)
+	assert.Equal(t, "2025-02-09", result[1].Date)
+}
+
+func TestClient_FindCityByCoordinates(t *testing.T) {
+	client := NewClient(http.DefaultClient, "", "", "")
+
+	result, err := client.FindCityByCoordinates(-22.90278, -43.2075)
+
+	assert.Nil(t, err)
+	assert.Equal(t, weather.City{ID: "-22.9028,-43.2075", Name: "-22.9028, -43.2075"}, result)
+}
+
+func TestClient_FindCityByID(t *testing.T) {
+	client := NewClient(http.DefaultClient, "", "", "")
+
+	result, err := client.FindCityByID("-22.9028,-43.2075")
+
+	assert.Nil(t, err)
+	assert.Equal(t, weather.City{ID: "-22.9028,-43.2075", Name: "-22.9028, -43.2075"}, result)
+
+	_, err = client.FindCityByID("241")
+
+	assert.True(t, errors.Is(err, weather.ErrCityNotFound))
+}
+
+func TestClient_SearchCities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/search?name=sao+paulo&count=10&language=pt&format=json", r.URL.String())
+		w.Write([]byte(`{"results":[{"name":"São Paulo","latitude":-23.5475,"longitude":-46.63611,"country_code":"BR","admin1":"São Paulo"},{"name":"São Paulo de Olivença","latitude":-3.37833,"longitude":-68.8725,"country_code":"BR","admin1":"Amazonas"}]}`))
+	}))
+
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL, server.URL, server.URL)
+
+	result, err := client.SearchCities("sao paulo")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []weather.City{
+		{ID: "-23.5475,-46.6361", Name: "São Paulo", State: "São Paulo"},
+		{ID: "-3.3783,-68.8725", Name: "São Paulo de Olivença", State: "Amazonas"},
+	}, result)
+}
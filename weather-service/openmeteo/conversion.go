@@ -0,0 +1,172 @@
+package openmeteo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/fgouvea/weather/weather-service/cptec"
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+const (
+	hoursPerDay   = 24
+	morningHour   = 9
+	afternoonHour = 15
+	eveningHour   = 21
+)
+
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+func buildCity(to LocationTO) weather.City {
+	return weather.City{
+		ID:    buildID(to.Latitude, to.Longitude),
+		Name:  to.Name,
+		State: to.Admin1,
+	}
+}
+
+func buildCityForecast(to ForecastResponseTO) (weather.CityForecast, error) {
+	daily := to.Daily
+
+	if len(daily.Time) == 0 {
+		return weather.CityForecast{}, weather.ErrCityNotFound
+	}
+
+	forecast := make([]weather.Forecast, len(daily.Time))
+
+	for i, date := range daily.Time {
+		min, err := valueAt(daily.MinTemperature, i)
+		if err != nil {
+			return weather.CityForecast{}, fmt.Errorf("%w: failed to read min temp for %s", ErrReadingResponse, date)
+		}
+
+		max, err := valueAt(daily.MaxTemperature, i)
+		if err != nil {
+			return weather.CityForecast{}, fmt.Errorf("%w: failed to read max temp for %s", ErrReadingResponse, date)
+		}
+
+		iuv, err := valueAt(daily.UVIndex, i)
+		if err != nil {
+			iuv = 0
+		}
+
+		code := ""
+		weatherName := cptec.UnknownWeather
+		if i < len(daily.WeatherCode) && daily.WeatherCode[i] != nil {
+			if known, exists := ConditionCode[*daily.WeatherCode[i]]; exists {
+				code = known
+				weatherName = cptec.WeatherName[known]
+			}
+		}
+
+		forecast[i] = weather.Forecast{
+			Date:           date,
+			Code:           code,
+			Weather:        weatherName,
+			MinTemperature: int(math.Round(min)),
+			MaxTemperature: int(math.Round(max)),
+			IUV:            iuv,
+		}
+	}
+
+	return weather.CityForecast{
+		UpdatedAt: daily.Time[0],
+		Forecast:  forecast,
+	}, nil
+}
+
+// buildCityWaveForecasts splits the hourly data into days. Days with missing
+// data cut the range short, unless it is the first one.
+func buildCityWaveForecasts(to MarineResponseTO) ([]weather.CityWaveForecast, error) {
+	var forecasts []weather.CityWaveForecast
+
+	for start := 0; start+eveningHour < len(to.Hourly.Time); start += hoursPerDay {
+		forecast, err := buildCityWaveForecast(to.Hourly, start)
+
+		if err != nil {
+			break
+		}
+
+		forecasts = append(forecasts, forecast)
+	}
+
+	if len(forecasts) == 0 {
+		return nil, weather.ErrCityNotFound
+	}
+
+	return forecasts, nil
+}
+
+func buildCityWaveForecast(hourly HourlyMarineTO, start int) (weather.CityWaveForecast, error) {
+	morning, err := buildWaveForecast(hourly, start+morningHour)
+	if err != nil {
+		return weather.CityWaveForecast{}, err
+	}
+
+	afternoon, err := buildWaveForecast(hourly, start+afternoonHour)
+	if err != nil {
+		return weather.CityWaveForecast{}, err
+	}
+
+	evening, err := buildWaveForecast(hourly, start+eveningHour)
+	if err != nil {
+		return weather.CityWaveForecast{}, err
+	}
+
+	updatedAt, _, _ := strings.Cut(hourly.Time[0], "T")
+	date, _, _ := strings.Cut(hourly.Time[start], "T")
+
+	return weather.CityWaveForecast{
+		UpdatedAt: updatedAt,
+		Date:      date,
+		Morning:   morning,
+		Afternoon: afternoon,
+		Evening:   evening,
+	}, nil
+}
+
+// buildWaveForecast reads the sea state at the given hour. Points away from
+// the sea have no marine data, which is reported as the city not being found,
+// just like CPTEC does.
+func buildWaveForecast(to HourlyMarineTO, hour int) (weather.WaveForecast, error) {
+	height, err := valueAt(to.WaveHeight, hour)
+	if err != nil {
+		return weather.WaveForecast{}, weather.ErrCityNotFound
+	}
+
+	direction, err := valueAt(to.WaveDirection, hour)
+	if err != nil {
+		return weather.WaveForecast{}, weather.ErrCityNotFound
+	}
+
+	return weather.WaveForecast{
+		Swell:         swell(height),
+		Height:        height,
+		WaveDirection: compassPoint(direction),
+	}, nil
+}
+
+func valueAt(values []*float64, i int) (float64, error) {
+	if i >= len(values) || values[i] == nil {
+		return 0, fmt.Errorf("missing value at %d", i)
+	}
+
+	return *values[i], nil
+}
+
+func swell(height float64) string {
+	switch {
+	case height < 1:
+		return "Fraco"
+	case height < 2.5:
+		return "Moderado"
+	default:
+		return "Forte"
+	}
+}
+
+func compassPoint(degrees float64) string {
+	index := int(math.Round(math.Mod(degrees, 360)/22.5)) % len(compassPoints)
+	return compassPoints[index]
+}
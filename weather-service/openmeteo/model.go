@@ -0,0 +1,101 @@
+package openmeteo
+
+type GeocodingResponseTO struct {
+	Results []LocationTO `json:"results"`
+}
+
+type LocationTO struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	CountryCode string  `json:"country_code"`
+	Admin1      string  `json:"admin1"`
+}
+
+type ForecastResponseTO struct {
+	Daily DailyForecastTO `json:"daily"`
+}
+
+type DailyForecastTO struct {
+	Time           []string   `json:"time"`
+	WeatherCode    []*int     `json:"weather_code"`
+	MaxTemperature []*float64 `json:"temperature_2m_max"`
+	MinTemperature []*float64 `json:"temperature_2m_min"`
+	UVIndex        []*float64 `json:"uv_index_max"`
+}
+
+type MarineResponseTO struct {
+	Hourly HourlyMarineTO `json:"hourly"`
+}
+
+type HourlyMarineTO struct {
+	Time          []string   `json:"time"`
+	WaveHeight    []*float64 `json:"wave_height"`
+	WaveDirection []*float64 `json:"wave_direction"`
+}
+
+// ConditionCode maps WMO weather interpretation codes to CPTEC condition
+// codes, so notifications read the same whichever provider answered.
+var ConditionCode = map[int]string{
+	0:  "cl",
+	1:  "ps",
+	2:  "pn",
+	3:  "e",
+	45: "nv",
+	48: "nv",
+	51: "cv",
+	53: "cv",
+	55: "cv",
+	56: "cv",
+	57: "cv",
+	61: "c",
+	63: "c",
+	65: "ch",
+	66: "c",
+	67: "ch",
+	71: "ne",
+	73: "ne",
+	75: "ne",
+	77: "ne",
+	80: "pc",
+	81: "pc",
+	82: "pc",
+	85: "ne",
+	86: "ne",
+	95: "t",
+	96: "t",
+	99: "t",
+}
+
+// StateName maps the UFs used by CPTEC to the names of the states, which is
+// how Open-Meteo reports them.
+var StateName = map[string]string{
+	"AC": "Acre",
+	"AL": "Alagoas",
+	"AP": "Amapá",
+	"AM": "Amazonas",
+	"BA": "Bahia",
+	"CE": "Ceará",
+	"DF": "Distrito Federal",
+	"ES": "Espírito Santo",
+	"GO": "Goiás",
+	"MA": "Maranhão",
+	"MT": "Mato Grosso",
+	"MS": "Mato Grosso do Sul",
+	"MG": "Minas Gerais",
+	"PA": "Pará",
+	"PB": "Paraíba",
+	"PR": "Paraná",
+	"PE": "Pernambuco",
+	"PI": "Piauí",
+	"RJ": "Rio de Janeiro",
+	"RN": "Rio Grande do Norte",
+	"RS": "Rio Grande do Sul",
+	"RO": "Rondônia",
+	"RR": "Roraima",
+	"SC": "Santa Catarina",
+	"SP": "São Paulo",
+	"SE": "Sergipe",
+	"TO": "Tocantins",
+}
@@ -0,0 +1,87 @@
+package revision
+
+import (
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/schedule"
+	"go.uber.org/zap"
+)
+
+type Checker interface {
+	Cities() ([]string, error)
+	Check(cityID string) error
+}
+
+// Job checks the forecast of every subscribed city for revisions. How soon a
+// revision is noticed also depends on how long forecasts are cached.
+//
+// When several replicas are running, only the one holding the Locker checks
+// on each tick.
+type Job struct {
+	Interval time.Duration
+	Checker  Checker
+	Locker   schedule.Locker
+	Logger   *zap.Logger
+}
+
+func NewJob(interval time.Duration, checker Checker, locker schedule.Locker, logger *zap.Logger) *Job {
+	return &Job{
+		Interval: interval,
+		Checker:  checker,
+		Locker:   locker,
+		Logger:   logger,
+	}
+}
+
+func (j *Job) Start() {
+	ticker := time.NewTicker(j.Interval)
+
+	go func() {
+		j.Logger.Info("starting revision job")
+
+		for range ticker.C {
+			j.Tick()
+		}
+
+		j.Logger.Info("stopping revision job")
+	}()
+}
+
+// Tick runs the job if this instance holds the lock.
+func (j *Job) Tick() {
+	held, err := j.Locker.Acquire()
+
+	if err != nil {
+		j.Logger.Error("error acquiring revision job lock", zap.Error(err))
+		return
+	}
+
+	if !held {
+		return
+	}
+
+	j.Run()
+}
+
+// Run checks every subscribed city, going on with the others when one fails.
+func (j *Job) Run() {
+	cities, err := j.Checker.Cities()
+
+	if err != nil {
+		j.Logger.Error("error listing subscribed cities", zap.Error(err))
+		return
+	}
+
+	failed := 0
+
+	for _, cityID := range cities {
+		err := j.Checker.Check(cityID)
+
+		if err != nil {
+			j.Logger.Error("error checking forecast revisions", zap.String("cityID", cityID), zap.Error(err))
+			failed++
+		}
+	}
+
+	j.Logger.Info("revision job finished", zap.Int("citiesChecked", len(cities)), zap.Int("citiesFailed", failed))
+}
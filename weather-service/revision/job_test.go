@@ -0,0 +1,57 @@
+package revision
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/schedule"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestJob_Run(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	checker := &checkerMock{
+		citiesResult: []string{"cptec:241", "cptec:244", "openmeteo:-22.9,-43.2"},
+		checkError:   map[string]error{"cptec:244": ErrFailedToCheck},
+	}
+
+	job := NewJob(time.Minute, checker, schedule.NewMemoryLocker(&schedule.MemoryLock{}, "instance", time.Minute), logger)
+
+	job.Run()
+
+	// A failing city does not stop the others from being checked
+	assert.Equal(t, []string{"cptec:241", "cptec:244", "openmeteo:-22.9,-43.2"}, checker.checkCalls)
+}
+
+func TestJob_Run_CitiesError(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	checker := &checkerMock{citiesError: errors.New("failed to connect to db")}
+
+	job := NewJob(time.Minute, checker, schedule.NewMemoryLocker(&schedule.MemoryLock{}, "instance", time.Minute), logger)
+
+	job.Run()
+
+	assert.Nil(t, checker.checkCalls)
+}
+
+func TestJob_Tick_SingleLeader(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	lock := &schedule.MemoryLock{}
+
+	leaderChecker := &checkerMock{citiesResult: []string{"cptec:241"}}
+	followerChecker := &checkerMock{citiesResult: []string{"cptec:241"}}
+
+	leader := NewJob(time.Minute, leaderChecker, schedule.NewMemoryLocker(lock, "leader", time.Minute), logger)
+	follower := NewJob(time.Minute, followerChecker, schedule.NewMemoryLocker(lock, "follower", time.Minute), logger)
+
+	leader.Tick()
+	follower.Tick()
+
+	assert.Equal(t, []string{"cptec:241"}, leaderChecker.checkCalls)
+	assert.Nil(t, followerChecker.checkCalls)
+}
@@ -0,0 +1,148 @@
+package revision
+
+import (
+	"github.com/fgouvea/weather/weather-service/user"
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+var testCity = weather.City{
+	ID:    "cptec:241",
+	Name:  "Rio de Janeiro",
+	State: "RJ",
+}
+
+type serviceMock struct {
+	saveCalls []Subscription
+	saveError error
+
+	deleteCalls []string
+	deleteError error
+
+	findCalls  []string
+	findResult Subscription
+	findError  error
+
+	findAllCalls  []string
+	findAllResult []Subscription
+	findAllError  error
+
+	findByCityCalls  []string
+	findByCityResult []Subscription
+	findByCityError  error
+
+	findCitiesResult []string
+	findCitiesError  error
+
+	findSnapshotsCalls  []string
+	findSnapshotsResult map[string][]Snapshot
+	findSnapshotsError  error
+	saveSnapshotsIDs    []string
+	saveSnapshotsCalls  [][]Snapshot
+	saveSnapshotsError  error
+
+	validateCalls  []weather.CityQuery
+	validateResult weather.City
+	validateError  error
+
+	getForecastCities []weather.CityQuery
+	getForecastCalls  []int
+	getForecastResult weather.CityForecast
+	getForecastError  error
+
+	findUserResult user.User
+	findUserError  error
+
+	notifyUsers    []string
+	notifyCalls    []string
+	notifyPayloads []weather.Payload
+	notifyError    error
+}
+
+var _ SubscriptionSaver = (*serviceMock)(nil)
+var _ SubscriptionFinder = (*serviceMock)(nil)
+var _ SnapshotStore = (*serviceMock)(nil)
+var _ Validator = (*serviceMock)(nil)
+var _ Forecaster = (*serviceMock)(nil)
+var _ weather.UserFinder = (*serviceMock)(nil)
+var _ weather.Notifier = (*serviceMock)(nil)
+
+func (m *serviceMock) Save(subscription Subscription) error {
+	m.saveCalls = append(m.saveCalls, subscription)
+	return m.saveError
+}
+
+func (m *serviceMock) Delete(id string) error {
+	m.deleteCalls = append(m.deleteCalls, id)
+	return m.deleteError
+}
+
+func (m *serviceMock) Find(id string) (Subscription, error) {
+	m.findCalls = append(m.findCalls, id)
+	return m.findResult, m.findError
+}
+
+func (m *serviceMock) FindAll(userID string) ([]Subscription, error) {
+	m.findAllCalls = append(m.findAllCalls, userID)
+	return m.findAllResult, m.findAllError
+}
+
+func (m *serviceMock) FindByCity(cityID string) ([]Subscription, error) {
+	m.findByCityCalls = append(m.findByCityCalls, cityID)
+	return m.findByCityResult, m.findByCityError
+}
+
+func (m *serviceMock) FindCities() ([]string, error) {
+	return m.findCitiesResult, m.findCitiesError
+}
+
+func (m *serviceMock) FindSnapshots(subscriptionID string) ([]Snapshot, error) {
+	m.findSnapshotsCalls = append(m.findSnapshotsCalls, subscriptionID)
+	return m.findSnapshotsResult[subscriptionID], m.findSnapshotsError
+}
+
+func (m *serviceMock) SaveSnapshots(subscriptionID string, snapshots []Snapshot) error {
+	m.saveSnapshotsIDs = append(m.saveSnapshotsIDs, subscriptionID)
+	m.saveSnapshotsCalls = append(m.saveSnapshotsCalls, snapshots)
+	return m.saveSnapshotsError
+}
+
+func (m *serviceMock) Validate(userID string, city weather.CityQuery) (weather.City, error) {
+	m.validateCalls = append(m.validateCalls, city)
+	return m.validateResult, m.validateError
+}
+
+func (m *serviceMock) GetForecast(city weather.CityQuery, days int) (weather.CityForecast, error) {
+	m.getForecastCities = append(m.getForecastCities, city)
+	m.getForecastCalls = append(m.getForecastCalls, days)
+	return m.getForecastResult, m.getForecastError
+}
+
+func (m *serviceMock) FindUser(id string) (user.User, error) {
+	return m.findUserResult, m.findUserError
+}
+
+func (m *serviceMock) Notify(userID string, notification weather.Notification) error {
+	m.notifyUsers = append(m.notifyUsers, userID)
+	m.notifyCalls = append(m.notifyCalls, notification.Text)
+	m.notifyPayloads = append(m.notifyPayloads, notification.Payload)
+	return m.notifyError
+}
+
+type checkerMock struct {
+	citiesResult []string
+	citiesError  error
+
+	checkCalls []string
+	checkError map[string]error
+}
+
+var _ Checker = (*checkerMock)(nil)
+
+func (m *checkerMock) Cities() ([]string, error) {
+	return m.citiesResult, m.citiesError
+}
+
+func (m *checkerMock) Check(cityID string) error {
+	m.checkCalls = append(m.checkCalls, cityID)
+	return m.checkError[cityID]
+}
@@ -0,0 +1,62 @@
+package revision
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("forecast subscription not found")
+	ErrInvalidSubscription  = errors.New("invalid forecast subscription")
+	ErrFailedToSave         = errors.New("failed to save forecast subscription")
+	ErrFailedToCheck        = errors.New("failed to check forecast revisions")
+)
+
+const (
+	// DefaultThreshold is how many degrees the minimum or maximum temperature
+	// must shift for a revision to be sent
+	DefaultThreshold = 3
+	// DefaultDays looks at today and tomorrow
+	DefaultDays = 2
+)
+
+// Subscription asks for a user to be told when the forecast of a city is
+// revised. Days is how many forecast days, starting today, are watched.
+type Subscription struct {
+	ID       string
+	UserID   string
+	CityID   string
+	CityName string
+	State    string
+
+	// Threshold is the temperature shift, in °C, that makes a revision worth
+	// sending. A change of condition is always sent.
+	Threshold int
+	Days      int
+	CreatedAt time.Time
+}
+
+// Snapshot is the forecast of a date as last sent to a subscriber, or as
+// first fetched. Code is the condition code of the forecast and Weather its
+// name.
+type Snapshot struct {
+	SubscriptionID string
+	Date           string
+	UpdatedAt      string
+	Code           string
+	Weather        string
+	Min            int
+	Max            int
+}
+
+// Message is given to the "revision" template
+type Message struct {
+	CityName  string
+	Revisions []Revision
+}
+
+// Revision is a date whose forecast changed since the last snapshot
+type Revision struct {
+	Previous Snapshot
+	Current  Snapshot
+}
@@ -0,0 +1,274 @@
+package revision
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/google/uuid"
+)
+
+// Validator checks a user and the city they asked for, returning the city
+// found.
+type Validator interface {
+	Validate(userID string, city weather.CityQuery) (weather.City, error)
+}
+
+type Forecaster interface {
+	GetForecast(city weather.CityQuery, days int) (weather.CityForecast, error)
+}
+
+type SubscriptionSaver interface {
+	Save(subscription Subscription) error
+	Delete(id string) error
+}
+
+type SubscriptionFinder interface {
+	Find(id string) (Subscription, error)
+	FindAll(userID string) ([]Subscription, error)
+	FindByCity(cityID string) ([]Subscription, error)
+	// FindCities lists every city with at least one subscription
+	FindCities() ([]string, error)
+}
+
+// SnapshotStore keeps, for each subscription and date, the forecast its
+// subscriber was last told about.
+type SnapshotStore interface {
+	FindSnapshots(subscriptionID string) ([]Snapshot, error)
+	SaveSnapshots(subscriptionID string, snapshots []Snapshot) error
+}
+
+type Service struct {
+	Saver      SubscriptionSaver
+	Finder     SubscriptionFinder
+	Snapshots  SnapshotStore
+	Validator  Validator
+	Forecaster Forecaster
+	Users      weather.UserFinder
+	Renderer   weather.Renderer
+	Notifier   weather.Notifier
+}
+
+func NewService(saver SubscriptionSaver, finder SubscriptionFinder, snapshots SnapshotStore, validator Validator, forecaster Forecaster, users weather.UserFinder, renderer weather.Renderer, notifier weather.Notifier) *Service {
+	return &Service{
+		Saver:      saver,
+		Finder:     finder,
+		Snapshots:  snapshots,
+		Validator:  validator,
+		Forecaster: forecaster,
+		Users:      users,
+		Renderer:   renderer,
+		Notifier:   notifier,
+	}
+}
+
+func (s *Service) Subscribe(userID string, city weather.CityQuery, subscription Subscription) (Subscription, error) {
+	if subscription.Threshold == 0 {
+		subscription.Threshold = DefaultThreshold
+	}
+
+	if subscription.Days == 0 {
+		subscription.Days = DefaultDays
+	}
+
+	err := subscription.Validate()
+
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	resolved, err := s.Validator.Validate(userID, city)
+
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	subscription.ID = fmt.Sprintf("REVISION-%s", uuid.New())
+	subscription.UserID = userID
+	subscription.CityID = resolved.ID
+	subscription.CityName = resolved.Name
+	subscription.State = resolved.State
+	subscription.CreatedAt = time.Now()
+
+	err = s.Saver.Save(subscription)
+
+	if err != nil {
+		return Subscription{}, fmt.Errorf("%w: %w", ErrFailedToSave, err)
+	}
+
+	return subscription, nil
+}
+
+func (s *Service) Find(id string) (Subscription, error) {
+	return s.Finder.Find(id)
+}
+
+func (s *Service) List(userID string) ([]Subscription, error) {
+	return s.Finder.FindAll(userID)
+}
+
+func (s *Service) Unsubscribe(id string) error {
+	_, err := s.Finder.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Saver.Delete(id)
+}
+
+// Cities lists the cities that have subscribers, which are the ones worth
+// checking.
+func (s *Service) Cities() ([]string, error) {
+	cities, err := s.Finder.FindCities()
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCheck, err)
+	}
+
+	return cities, nil
+}
+
+// Check fetches the forecast of a city and compares it, for each subscriber,
+// with the last forecast they were told about, notifying them of the dates
+// revised beyond their threshold. The first fetch of a date only stores its
+// snapshot.
+//
+// The forecast is fetched only from the provider of the city, with no
+// fallback, since the forecasts of another provider differ enough to look
+// like revisions; the check fails until the provider is back.
+func (s *Service) Check(cityID string) error {
+	subscriptions, err := s.Finder.FindByCity(cityID)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCheck, err)
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	days := 0
+
+	for _, subscription := range subscriptions {
+		days = max(days, subscription.Days)
+	}
+
+	forecast, err := s.Forecaster.GetForecast(weather.CityQuery{ID: cityID}, days)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCheck, err)
+	}
+
+	var errs []error
+
+	for _, subscription := range subscriptions {
+		err = s.check(subscription, forecast)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: subscription %s: %w", ErrFailedToCheck, subscription.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check compares the days watched by the subscription with its snapshots.
+// A snapshot moves only when its date is notified, so a forecast drifting a
+// little at a time is sent once the drift adds up to the threshold, and a
+// notification that fails is sent again on the next check.
+func (s *Service) check(subscription Subscription, forecast weather.CityForecast) error {
+	previous, err := s.Snapshots.FindSnapshots(subscription.ID)
+
+	if err != nil {
+		return err
+	}
+
+	byDate := map[string]Snapshot{}
+
+	for _, snapshot := range previous {
+		byDate[snapshot.Date] = snapshot
+	}
+
+	days := forecast.Forecast[:min(subscription.Days, len(forecast.Forecast))]
+
+	var revisions []Revision
+	snapshots := make([]Snapshot, len(days))
+
+	for i, day := range days {
+		current := newSnapshot(subscription.ID, forecast.UpdatedAt, day)
+		last, exists := byDate[day.Date]
+		revision := Revision{Previous: last, Current: current}
+
+		switch {
+		case !exists:
+			snapshots[i] = current
+		case revision.matters(subscription.Threshold):
+			revisions = append(revisions, revision)
+			snapshots[i] = current
+		default:
+			snapshots[i] = last
+		}
+	}
+
+	if len(revisions) > 0 {
+		err = s.notify(subscription, forecast, revisions)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.Snapshots.SaveSnapshots(subscription.ID, snapshots)
+}
+
+func (s *Service) notify(subscription Subscription, forecast weather.CityForecast, revisions []Revision) error {
+	userEntry, err := s.Users.FindUser(subscription.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	content, err := s.Renderer.Render(userEntry.Locale, "revision", Message{CityName: subscription.CityName, Revisions: revisions})
+
+	if err != nil {
+		return err
+	}
+
+	return s.Notifier.Notify(subscription.UserID, weather.Notification{
+		Content: content,
+		Payload: weather.Payload{
+			Type: "revision",
+			City: weather.City{ID: subscription.CityID, Name: subscription.CityName, State: subscription.State},
+			Days: payloadDays(forecast, revisions),
+		},
+	})
+}
+
+// payloadDays gives the whole forecast of each revised date, along with what
+// was known of it before
+func payloadDays(forecast weather.CityForecast, revisions []Revision) []weather.PayloadDay {
+	byDate := map[string]weather.Forecast{}
+
+	for _, day := range forecast.Forecast {
+		byDate[day.Date] = day
+	}
+
+	days := make([]weather.PayloadDay, len(revisions))
+
+	for i, revision := range revisions {
+		days[i] = weather.PayloadDay{
+			Forecast: byDate[revision.Current.Date],
+			Previous: &weather.Forecast{
+				Date:           revision.Previous.Date,
+				Code:           revision.Previous.Code,
+				Weather:        revision.Previous.Weather,
+				MinTemperature: revision.Previous.Min,
+				MaxTemperature: revision.Previous.Max,
+			},
+		}
+	}
+
+	return days
+}
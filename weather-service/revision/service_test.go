@@ -0,0 +1,365 @@
+package revision
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fgouvea/weather/weather-service/message"
+	"github.com/fgouvea/weather/weather-service/user"
+	"github.com/fgouvea/weather/weather-service/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRenderer, _ = message.NewEmbeddedRenderer()
+
+var testForecast = weather.CityForecast{
+	UpdatedAt: "2025-02-07",
+	Forecast: []weather.Forecast{
+		{Date: "2025-02-07", Code: "cl", Weather: "Céu Claro", MinTemperature: 22, MaxTemperature: 31},
+		{Date: "2025-02-08", Code: "c", Weather: "Chuva", MinTemperature: 21, MaxTemperature: 30},
+		{Date: "2025-02-09", Code: "c", Weather: "Chuva", MinTemperature: 20, MaxTemperature: 29},
+	},
+}
+
+func testSnapshots(subscriptionID string) []Snapshot {
+	return []Snapshot{
+		{SubscriptionID: subscriptionID, Date: "2025-02-07", UpdatedAt: "2025-02-06", Code: "cl", Weather: "Céu Claro", Min: 22, Max: 31},
+		{SubscriptionID: subscriptionID, Date: "2025-02-08", UpdatedAt: "2025-02-06", Code: "cl", Weather: "Céu Claro", Min: 21, Max: 30},
+		{SubscriptionID: subscriptionID, Date: "2025-02-09", UpdatedAt: "2025-02-06", Code: "c", Weather: "Chuva", Min: 20, Max: 34},
+	}
+}
+
+func TestService_Subscribe(t *testing.T) {
+	tests := []struct {
+		name              string
+		subscription      Subscription
+		validateError     error
+		saveError         error
+		expectedError     error
+		expectedValidates int
+		expectedSaveCalls int
+		expectedThreshold int
+		expectedDays      int
+	}{
+		{
+			name:              "success",
+			subscription:      Subscription{},
+			expectedValidates: 1,
+			expectedSaveCalls: 1,
+			expectedThreshold: DefaultThreshold,
+			expectedDays:      DefaultDays,
+		},
+		{
+			name:              "custom threshold and days",
+			subscription:      Subscription{Threshold: 5, Days: 4},
+			expectedValidates: 1,
+			expectedSaveCalls: 1,
+			expectedThreshold: 5,
+			expectedDays:      4,
+		},
+		{
+			name:              "invalid threshold",
+			subscription:      Subscription{Threshold: -1},
+			expectedError:     ErrInvalidSubscription,
+			expectedValidates: 0,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "too many days",
+			subscription:      Subscription{Days: weather.MaxForecastDays + 1},
+			expectedError:     ErrInvalidSubscription,
+			expectedValidates: 0,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "error validating",
+			subscription:      Subscription{},
+			validateError:     user.ErrUserNotFound,
+			expectedError:     user.ErrUserNotFound,
+			expectedValidates: 1,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "error saving",
+			subscription:      Subscription{},
+			saveError:         errors.New("failed to connect to db"),
+			expectedError:     ErrFailedToSave,
+			expectedValidates: 1,
+			expectedSaveCalls: 1,
+			expectedThreshold: DefaultThreshold,
+			expectedDays:      DefaultDays,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{validateResult: testCity, validateError: tt.validateError, saveError: tt.saveError}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+			result, err := service.Subscribe("USER-ID", weather.CityQuery{Name: "rio de janeiro"}, tt.subscription)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedValidates, len(mock.validateCalls))
+			assert.Equal(t, tt.expectedSaveCalls, len(mock.saveCalls))
+
+			for _, saved := range mock.saveCalls {
+				assert.Equal(t, "USER-ID", saved.UserID)
+				assert.Equal(t, "cptec:241", saved.CityID)
+				assert.Equal(t, "Rio de Janeiro", saved.CityName)
+				assert.Equal(t, "RJ", saved.State)
+				assert.Equal(t, tt.expectedThreshold, saved.Threshold)
+				assert.Equal(t, tt.expectedDays, saved.Days)
+			}
+
+			if tt.expectedError == nil {
+				assert.Equal(t, mock.saveCalls[0], result)
+			} else {
+				assert.Equal(t, Subscription{}, result)
+			}
+		})
+	}
+}
+
+func TestService_Check(t *testing.T) {
+	tomorrow := Subscription{ID: "REVISION-1", UserID: "USER-1", CityName: "Rio de Janeiro", Threshold: 3, Days: 2}
+	later := Subscription{ID: "REVISION-2", UserID: "USER-2", CityName: "Rio de Janeiro", Threshold: 6, Days: 3}
+	sensitive := Subscription{ID: "REVISION-3", UserID: "USER-3", CityName: "Rio de Janeiro", Threshold: 2, Days: 3}
+
+	renamed := testSnapshots("REVISION-1")
+	renamed[1].Code = "c"
+	renamed[1].Weather = "Chuvoso"
+
+	unknown := testSnapshots("REVISION-1")
+	unknown[1].Code = ""
+
+	tests := []struct {
+		name                  string
+		subscriptions         []Subscription
+		snapshots             map[string][]Snapshot
+		locale                string
+		findSnapshotsError    error
+		findUserError         error
+		forecastError         error
+		notifyError           error
+		expectedError         error
+		expectedForecastCalls []int
+		expectedSavedIDs      []string
+		// expectedSaved has the UpdatedAt of each snapshot saved, telling the
+		// dates moved to the current forecast from the ones kept
+		expectedSaved         [][]string
+		expectedUsers         []string
+		expectedNotifications []string
+	}{
+		{
+			name:                  "no subscriptions",
+			subscriptions:         nil,
+			expectedForecastCalls: nil,
+		},
+		{
+			name:                  "first fetch only stores snapshots",
+			subscriptions:         []Subscription{tomorrow},
+			snapshots:             nil,
+			expectedForecastCalls: []int{2},
+			expectedSavedIDs:      []string{"REVISION-1"},
+			expectedSaved:         [][]string{{"2025-02-07", "2025-02-07"}},
+		},
+		{
+			name:                  "condition changed",
+			subscriptions:         []Subscription{tomorrow},
+			snapshots:             map[string][]Snapshot{"REVISION-1": testSnapshots("REVISION-1")},
+			expectedForecastCalls: []int{2},
+			expectedSavedIDs:      []string{"REVISION-1"},
+			expectedSaved:         [][]string{{"2025-02-06", "2025-02-07"}},
+			expectedUsers:         []string{"USER-1"},
+			expectedNotifications: []string{"Previsão atualizada para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 30°C (antes: Céu Claro, 21 - 30°C)"},
+		},
+		{
+			name:                  "condition renamed",
+			subscriptions:         []Subscription{tomorrow},
+			snapshots:             map[string][]Snapshot{"REVISION-1": renamed},
+			expectedForecastCalls: []int{2},
+			expectedSavedIDs:      []string{"REVISION-1"},
+			expectedSaved:         [][]string{{"2025-02-06", "2025-02-06"}},
+		},
+		{
+			name:                  "condition unknown",
+			subscriptions:         []Subscription{tomorrow},
+			snapshots:             map[string][]Snapshot{"REVISION-1": unknown},
+			expectedForecastCalls: []int{2},
+			expectedSavedIDs:      []string{"REVISION-1"},
+			expectedSaved:         [][]string{{"2025-02-06", "2025-02-06"}},
+		},
+		{
+			name:                  "temperature within the threshold keeps the snapshot",
+			subscriptions:         []Subscription{later},
+			snapshots:             map[string][]Snapshot{"REVISION-2": testSnapshots("REVISION-2")[2:]},
+			expectedForecastCalls: []int{3},
+			expectedSavedIDs:      []string{"REVISION-2"},
+			expectedSaved:         [][]string{{"2025-02-07", "2025-02-07", "2025-02-06"}},
+		},
+		{
+			name:                  "temperature beyond the threshold",
+			subscriptions:         []Subscription{sensitive},
+			snapshots:             map[string][]Snapshot{"REVISION-3": testSnapshots("REVISION-3")[2:]},
+			expectedForecastCalls: []int{3},
+			expectedSavedIDs:      []string{"REVISION-3"},
+			expectedSaved:         [][]string{{"2025-02-07", "2025-02-07", "2025-02-07"}},
+			expectedUsers:         []string{"USER-3"},
+			expectedNotifications: []string{"Previsão atualizada para Rio de Janeiro\n\n09/02/2025: Chuva, 20 - 29°C (antes: Chuva, 20 - 34°C)"},
+		},
+		{
+			name:          "each subscriber gets the revisions that matter to them",
+			subscriptions: []Subscription{tomorrow, later, sensitive},
+			snapshots: map[string][]Snapshot{
+				"REVISION-1": testSnapshots("REVISION-1"),
+				"REVISION-2": testSnapshots("REVISION-2"),
+				"REVISION-3": testSnapshots("REVISION-3"),
+			},
+			expectedForecastCalls: []int{3},
+			expectedSavedIDs:      []string{"REVISION-1", "REVISION-2", "REVISION-3"},
+			expectedSaved: [][]string{
+				{"2025-02-06", "2025-02-07"},
+				{"2025-02-06", "2025-02-07", "2025-02-06"},
+				{"2025-02-06", "2025-02-07", "2025-02-07"},
+			},
+			expectedUsers: []string{"USER-1", "USER-2", "USER-3"},
+			expectedNotifications: []string{
+				"Previsão atualizada para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 30°C (antes: Céu Claro, 21 - 30°C)",
+				"Previsão atualizada para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 30°C (antes: Céu Claro, 21 - 30°C)",
+				"Previsão atualizada para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 30°C (antes: Céu Claro, 21 - 30°C)\n09/02/2025: Chuva, 20 - 29°C (antes: Chuva, 20 - 34°C)",
+			},
+		},
+		{
+			name:                  "in the locale of the user",
+			subscriptions:         []Subscription{tomorrow},
+			snapshots:             map[string][]Snapshot{"REVISION-1": testSnapshots("REVISION-1")},
+			locale:                "es",
+			expectedForecastCalls: []int{2},
+			expectedSavedIDs:      []string{"REVISION-1"},
+			expectedSaved:         [][]string{{"2025-02-06", "2025-02-07"}},
+			expectedUsers:         []string{"USER-1"},
+			expectedNotifications: []string{"Pronóstico actualizado para Rio de Janeiro\n\n08/02/2025: Lluvia, 21 - 30°C (antes: Cielo Despejado, 21 - 30°C)"},
+		},
+		{
+			name:                  "error finding snapshots",
+			subscriptions:         []Subscription{tomorrow},
+			findSnapshotsError:    errors.New("failed to connect to db"),
+			expectedError:         ErrFailedToCheck,
+			expectedForecastCalls: []int{2},
+		},
+		{
+			name:                  "error finding user keeps the snapshots",
+			subscriptions:         []Subscription{tomorrow},
+			snapshots:             map[string][]Snapshot{"REVISION-1": testSnapshots("REVISION-1")},
+			findUserError:         user.ErrUserNotFound,
+			expectedError:         user.ErrUserNotFound,
+			expectedForecastCalls: []int{2},
+		},
+		{
+			name:                  "error fetching forecast",
+			subscriptions:         []Subscription{tomorrow},
+			forecastError:         weather.ErrProviderUnavailable,
+			expectedError:         weather.ErrProviderUnavailable,
+			expectedForecastCalls: []int{2},
+		},
+		{
+			name:                  "error notifying keeps the snapshots",
+			subscriptions:         []Subscription{tomorrow},
+			snapshots:             map[string][]Snapshot{"REVISION-1": testSnapshots("REVISION-1")},
+			notifyError:           errors.New("broker down"),
+			expectedError:         ErrFailedToCheck,
+			expectedForecastCalls: []int{2},
+			expectedUsers:         []string{"USER-1"},
+			expectedNotifications: []string{"Previsão atualizada para Rio de Janeiro\n\n08/02/2025: Chuva, 21 - 30°C (antes: Céu Claro, 21 - 30°C)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				findByCityResult:    tt.subscriptions,
+				findSnapshotsResult: tt.snapshots,
+				findSnapshotsError:  tt.findSnapshotsError,
+				findUserResult:      user.User{Locale: tt.locale},
+				findUserError:       tt.findUserError,
+				getForecastResult:   testForecast,
+				getForecastError:    tt.forecastError,
+				notifyError:         tt.notifyError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+			err := service.Check("cptec:241")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, []string{"cptec:241"}, mock.findByCityCalls)
+			assert.Equal(t, tt.expectedForecastCalls, mock.getForecastCalls)
+			assert.Equal(t, tt.expectedUsers, mock.notifyUsers)
+			assert.Equal(t, tt.expectedNotifications, mock.notifyCalls)
+			assert.Equal(t, tt.expectedSavedIDs, mock.saveSnapshotsIDs)
+
+			for _, city := range mock.getForecastCities {
+				assert.Equal(t, weather.CityQuery{ID: "cptec:241"}, city)
+			}
+
+			var saved [][]string
+
+			for i, snapshots := range mock.saveSnapshotsCalls {
+				var updatedAt []string
+
+				for _, snapshot := range snapshots {
+					assert.Equal(t, tt.expectedSavedIDs[i], snapshot.SubscriptionID)
+					updatedAt = append(updatedAt, snapshot.UpdatedAt)
+				}
+
+				saved = append(saved, updatedAt)
+			}
+
+			assert.Equal(t, tt.expectedSaved, saved)
+		})
+	}
+}
+
+func TestService_Check_Payload(t *testing.T) {
+	subscription := Subscription{ID: "REVISION-1", UserID: "USER-1", CityID: "cptec:241", CityName: "Rio de Janeiro", State: "RJ", Threshold: 3, Days: 2}
+
+	mock := &serviceMock{
+		findByCityResult:    []Subscription{subscription},
+		findSnapshotsResult: map[string][]Snapshot{"REVISION-1": testSnapshots("REVISION-1")},
+		getForecastResult:   testForecast,
+	}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+	err := service.Check("cptec:241")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []weather.Payload{{
+		Type: "revision",
+		City: weather.City{ID: "cptec:241", Name: "Rio de Janeiro", State: "RJ"},
+		Days: []weather.PayloadDay{{
+			Forecast: testForecast.Forecast[1],
+			Previous: &weather.Forecast{Date: "2025-02-08", Code: "cl", Weather: "Céu Claro", MinTemperature: 21, MaxTemperature: 30},
+		}},
+	}}, mock.notifyPayloads)
+}
+
+func TestService_Unsubscribe(t *testing.T) {
+	mock := &serviceMock{findError: ErrSubscriptionNotFound}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, testRenderer, mock)
+
+	err := service.Unsubscribe("REVISION-1")
+
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+	assert.Nil(t, mock.deleteCalls)
+
+	mock.findError = nil
+
+	err = service.Unsubscribe("REVISION-1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"REVISION-1"}, mock.deleteCalls)
+}
@@ -0,0 +1,55 @@
+package revision
+
+import (
+	"fmt"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+// Validate checks the subscription. Threshold and Days must have been
+// defaulted already.
+func (s Subscription) Validate() error {
+	if s.Threshold < 1 {
+		return fmt.Errorf("%w: threshold must be at least 1", ErrInvalidSubscription)
+	}
+
+	if s.Days < 1 || s.Days > weather.MaxForecastDays {
+		return fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidSubscription, weather.MaxForecastDays)
+	}
+
+	return nil
+}
+
+func newSnapshot(subscriptionID, updatedAt string, forecast weather.Forecast) Snapshot {
+	return Snapshot{
+		SubscriptionID: subscriptionID,
+		Date:           forecast.Date,
+		UpdatedAt:      updatedAt,
+		Code:           forecast.Code,
+		Weather:        forecast.Weather,
+		Min:            forecast.MinTemperature,
+		Max:            forecast.MaxTemperature,
+	}
+}
+
+// matters tells whether the revision is worth sending to a subscriber with
+// the given threshold
+func (r Revision) matters(threshold int) bool {
+	return r.conditionChanged() ||
+		abs(r.Current.Min-r.Previous.Min) >= threshold ||
+		abs(r.Current.Max-r.Previous.Max) >= threshold
+}
+
+// conditionChanged compares the condition codes, which stay the same when
+// only the wording of the name does. An unknown condition is not a change.
+func (r Revision) conditionChanged() bool {
+	return r.Previous.Code != "" && r.Current.Code != "" && r.Previous.Code != r.Current.Code
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
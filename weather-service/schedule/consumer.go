@@ -16,6 +16,7 @@ import (
 
 type ScheduleProcessor interface {
 	Process(schedule Schedule) error
+	Fail(schedule Schedule, cause error) error
 }
 
 type Consumer struct {
@@ -78,8 +79,13 @@ func (c *Consumer) consume(consumerName string, delivery amqp.Delivery) {
 
 	err = c.Processor.Process(schedule)
 
-	if errors.Is(user.ErrUserNotFound, err) || errors.Is(weather.ErrCityNotFound, err) {
+	if errors.Is(err, user.ErrUserNotFound) || errors.Is(err, weather.ErrCityNotFound) || errors.Is(err, weather.ErrMultipleCities) {
 		c.Logger.Error("non retryable error processing schedule", zap.String("consumer", consumerName), zap.String("userID", string(schedule.UserID)), zap.Error(err))
+
+		if failErr := c.Processor.Fail(schedule, err); failErr != nil {
+			c.Logger.Error("error failing schedule", zap.String("consumer", consumerName), zap.String("scheduleID", schedule.ID), zap.Error(failErr))
+		}
+
 		delivery.Nack(false, false)
 		return
 	}
@@ -1,27 +1,47 @@
 package schedule
 
 import (
+	"fmt"
 	"time"
 
 	"go.uber.org/zap"
 )
 
-type ScheduleSearcher interface {
-	FindAllBefore(t time.Time) ([]Schedule, error)
+// ScheduleClaimer atomically moves due active schedules to processing and
+// returns them, so that a schedule is only ever handed to one caller.
+type ScheduleClaimer interface {
+	ClaimDue(now time.Time, limit int) ([]Schedule, error)
 }
 
+type SchedulePublisher interface {
+	Publish(schedule Schedule) error
+}
+
+// Job polls the database for due schedules and publishes them to the
+// schedule queue. Schedules are never held in memory between ticks: anything
+// not yet due stays active in the database and is picked up by whichever
+// instance polls after its time has come, so restarts lose nothing.
+//
+// When several replicas are running, only the one holding the Locker polls
+// on each tick.
 type Job struct {
 	Interval  time.Duration
-	Publisher *Publisher
-	Searcher  ScheduleSearcher
+	BatchSize int
+	Claimer   ScheduleClaimer
+	Publisher SchedulePublisher
+	Saver     ScheduleSaver
+	Locker    Locker
 	Logger    *zap.Logger
 }
 
-func NewJob(interval time.Duration, publisher *Publisher, searcher ScheduleSearcher, logger *zap.Logger) *Job {
+func NewJob(interval time.Duration, batchSize int, claimer ScheduleClaimer, publisher SchedulePublisher, saver ScheduleSaver, locker Locker, logger *zap.Logger) *Job {
 	return &Job{
 		Interval:  interval,
+		BatchSize: batchSize,
+		Claimer:   claimer,
 		Publisher: publisher,
-		Searcher:  searcher,
+		Saver:     saver,
+		Locker:    locker,
 		Logger:    logger,
 	}
 }
@@ -33,38 +53,85 @@ func (j *Job) Start() {
 		j.Logger.Info("starting schedule job")
 
 		for currentTime := range ticker.C {
-			// TODO: Lock job to avoid multiple instances running the same job together
+			j.Tick(currentTime)
+		}
+
+		j.Logger.Info("stopping schedule job")
+	}()
+}
+
+// Tick runs the job if this instance holds the lock, taking it first when no
+// other instance does. Once taken, the lock stays with this instance until it
+// is released or, for the advisory lock, its database connection drops.
+func (j *Job) Tick(now time.Time) {
+	held, err := j.Locker.Acquire()
 
-			threshold := time.Now().Add(j.Interval)
+	if err != nil {
+		j.Logger.Error("error acquiring schedule job lock", zap.Error(err))
+		return
+	}
+
+	if !held {
+		j.Logger.Debug("schedule job lock held by another instance")
+		return
+	}
+
+	j.Run(now)
+}
+
+// Run claims and publishes every schedule due at the given time.
+func (j *Job) Run(now time.Time) {
+	published := 0
 
-			schedules, err := j.Searcher.FindAllBefore(threshold)
+	for {
+		schedules, err := j.Claimer.ClaimDue(now, j.BatchSize)
+
+		if err != nil {
+			j.Logger.Error("error claiming due schedules", zap.Error(err))
+			return
+		}
+
+		failed := false
+
+		for _, schedule := range schedules {
+			err := j.publish(schedule)
 
 			if err != nil {
-				j.Logger.Error("error querying schedules within threshold", zap.Error(err))
+				j.Logger.Error("error publishing schedule", zap.String("scheduleID", schedule.ID), zap.Error(err))
+				failed = true
 				continue
 			}
 
-			j.Logger.Info("running schedule job", zap.Int("schedulesFound", len(schedules)))
+			published++
+		}
 
-			for _, schedule := range schedules {
-				duration := schedule.Time.Sub(currentTime)
+		// Failed schedules are due again right away, so leave them for the next run
+		if failed || len(schedules) < j.BatchSize {
+			break
+		}
+	}
 
-				j.Logger.Info("scheduling info",
-					zap.String("scheduleID", schedule.ID),
-					zap.String("scheduledIn", duration.String()),
-					zap.String("currentTime", currentTime.Format(time.RFC3339)),
-					zap.String("scheduleTime", schedule.Time.Format(time.RFC3339)),
-				)
+	j.Logger.Info("schedule job finished", zap.Int("schedulesPublished", published))
+}
 
-				go func() {
-					time.Sleep(duration)
+func (j *Job) publish(schedule Schedule) error {
+	j.Logger.Info("publishing scheduled info",
+		zap.String("scheduleID", schedule.ID),
+		zap.String("scheduleTime", schedule.Time.Format(time.RFC3339)),
+	)
 
-					j.Logger.Info("processing scheduled info", zap.String("scheduleID", schedule.ID))
-					j.Publisher.Publish(schedule)
-				}()
-			}
-		}
+	err := j.Publisher.Publish(schedule)
 
-		j.Logger.Info("stopping schedule job")
-	}()
+	if err == nil {
+		return nil
+	}
+
+	// Hand the schedule back so the next run can claim it again
+	schedule.Status = StatusActive
+
+	if _, saveErr := j.Saver.SaveIfStatus(schedule, StatusProcessing); saveErr != nil {
+		return fmt.Errorf("%w: %w: %w", err, ErrFailedToSave, saveErr)
+	}
+
+	return err
 }
@@ -0,0 +1,140 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var jobStart = time.Date(2025, 2, 7, 10, 0, 0, 0, time.UTC)
+
+func testSchedules() []Schedule {
+	return []Schedule{
+		{ID: "SCHEDULE-1", Status: StatusActive, Time: jobStart.Add(-time.Minute)},
+		{ID: "SCHEDULE-2", Status: StatusActive, Time: jobStart},
+		{ID: "SCHEDULE-3", Status: StatusActive, Time: jobStart.Add(time.Minute)},
+		{ID: "SCHEDULE-4", Status: StatusActive, Time: jobStart.Add(time.Hour)},
+		{ID: "SCHEDULE-5", Status: StatusCancelled, Time: jobStart.Add(-time.Minute)},
+		{ID: "SCHEDULE-6", Status: StatusCompleted, Time: jobStart.Add(-time.Minute)},
+	}
+}
+
+func TestJob_Run(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	repository := newMemoryRepository(testSchedules()...)
+	publisher := &publisherMock{}
+
+	job := NewJob(time.Minute, 1, repository, publisher, repository, NewMemoryLocker(&MemoryLock{}, "instance", time.Minute), logger)
+
+	job.Run(jobStart)
+
+	assert.Equal(t, []string{"SCHEDULE-1", "SCHEDULE-2"}, publisher.publishCalls)
+	assert.Equal(t, StatusProcessing, repository.get("SCHEDULE-1").Status)
+	assert.Equal(t, StatusProcessing, repository.get("SCHEDULE-2").Status)
+	assert.Equal(t, StatusActive, repository.get("SCHEDULE-3").Status)
+	assert.Equal(t, StatusCancelled, repository.get("SCHEDULE-5").Status)
+}
+
+func TestJob_Run_SurvivesRestart(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	repository := newMemoryRepository(testSchedules()...)
+	publisher := &publisherMock{}
+
+	// First instance runs once and then dies before the later schedules are due
+	first := NewJob(time.Minute, 10, repository, publisher, repository, NewMemoryLocker(&MemoryLock{}, "instance", time.Minute), logger)
+	first.Run(jobStart)
+
+	// A fresh instance over the same database takes over
+	second := NewJob(time.Minute, 10, repository, publisher, repository, NewMemoryLocker(&MemoryLock{}, "instance", time.Minute), logger)
+	second.Run(jobStart)
+	second.Run(jobStart.Add(time.Minute))
+	second.Run(jobStart.Add(2 * time.Hour))
+
+	assert.Equal(t, []string{"SCHEDULE-1", "SCHEDULE-2", "SCHEDULE-3", "SCHEDULE-4"}, publisher.publishCalls)
+}
+
+func TestJob_Run_ConcurrentInstances(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	var schedules []Schedule
+	for i := 0; i < 100; i++ {
+		schedules = append(schedules, Schedule{ID: fmt.Sprintf("SCHEDULE-%03d", i), Status: StatusActive, Time: jobStart})
+	}
+
+	repository := newMemoryRepository(schedules...)
+	publisher := &publisherMock{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			NewJob(time.Minute, 7, repository, publisher, repository, NewMemoryLocker(&MemoryLock{}, "instance", time.Minute), logger).Run(jobStart)
+		}()
+	}
+	wg.Wait()
+
+	sort.Strings(publisher.publishCalls)
+
+	var expected []string
+	for _, s := range schedules {
+		expected = append(expected, s.ID)
+	}
+
+	assert.Equal(t, expected, publisher.publishCalls)
+}
+
+func TestJob_Run_PublishError(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	repository := newMemoryRepository(testSchedules()...)
+	publisher := &publisherMock{publishError: errors.New("broker unavailable")}
+
+	job := NewJob(time.Minute, 10, repository, publisher, repository, NewMemoryLocker(&MemoryLock{}, "instance", time.Minute), logger)
+
+	job.Run(jobStart)
+
+	assert.Equal(t, []string{"SCHEDULE-1", "SCHEDULE-2"}, publisher.publishCalls)
+	assert.Equal(t, StatusActive, repository.get("SCHEDULE-1").Status)
+	assert.Equal(t, StatusActive, repository.get("SCHEDULE-2").Status)
+
+	publisher.publishError = nil
+	publisher.publishCalls = nil
+
+	job.Run(jobStart)
+
+	assert.Equal(t, []string{"SCHEDULE-1", "SCHEDULE-2"}, publisher.publishCalls)
+}
+
+func TestJob_Tick_SingleLeader(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	repository := newMemoryRepository(testSchedules()...)
+	publisher := &publisherMock{}
+
+	lock := &MemoryLock{}
+	leaderLocker := NewMemoryLocker(lock, "leader", time.Minute)
+	followerLocker := NewMemoryLocker(lock, "follower", time.Minute)
+
+	leader := NewJob(time.Minute, 10, repository, publisher, repository, leaderLocker, logger)
+	follower := NewJob(time.Minute, 10, repository, publisher, repository, followerLocker, logger)
+
+	leader.Tick(jobStart)
+	follower.Tick(jobStart.Add(time.Minute))
+
+	assert.Equal(t, []string{"SCHEDULE-1", "SCHEDULE-2"}, publisher.publishCalls)
+
+	// The leader shuts down and releases the lock, so the follower takes over
+	leaderLocker.Release()
+	follower.Tick(jobStart.Add(time.Minute))
+
+	assert.Equal(t, []string{"SCHEDULE-1", "SCHEDULE-2", "SCHEDULE-3"}, publisher.publishCalls)
+}
@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+)
+
+// Locker elects a single instance among the replicas to run a job.
+type Locker interface {
+	// Acquire takes the lock, or checks it is still held if this instance
+	// already holds it, renewing it for lockers with a lease, and reports
+	// whether this instance is the holder.
+	Acquire() (bool, error)
+	Release() error
+}
+
+// MemoryLock is a lease shared by MemoryLockers, standing in for the
+// database when several job instances run in the same process.
+type MemoryLock struct {
+	mu        sync.Mutex
+	owner     string
+	expiresAt time.Time
+}
+
+// MemoryLocker holds a MemoryLock for a lease duration. A holder that stops
+// renewing loses the lock once the lease expires, letting another take over.
+type MemoryLocker struct {
+	Lock  *MemoryLock
+	Owner string
+	Lease time.Duration
+
+	now func() time.Time
+}
+
+var _ Locker = (*MemoryLocker)(nil)
+
+func NewMemoryLocker(lock *MemoryLock, owner string, lease time.Duration) *MemoryLocker {
+	return &MemoryLocker{
+		Lock:  lock,
+		Owner: owner,
+		Lease: lease,
+
+		now: time.Now,
+	}
+}
+
+func (l *MemoryLocker) Acquire() (bool, error) {
+	l.Lock.mu.Lock()
+	defer l.Lock.mu.Unlock()
+
+	now := l.now()
+
+	if l.Lock.owner != "" && l.Lock.owner != l.Owner && now.Before(l.Lock.expiresAt) {
+		return false, nil
+	}
+
+	l.Lock.owner = l.Owner
+	l.Lock.expiresAt = now.Add(l.Lease)
+
+	return true, nil
+}
+
+func (l *MemoryLocker) Release() error {
+	l.Lock.mu.Lock()
+	defer l.Lock.mu.Unlock()
+
+	if l.Lock.owner == l.Owner {
+		l.Lock.owner = ""
+		l.Lock.expiresAt = time.Time{}
+	}
+
+	return nil
+}
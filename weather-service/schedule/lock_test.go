@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	now := time.Date(2025, 2, 7, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	lock := &MemoryLock{}
+
+	first := NewMemoryLocker(lock, "first", time.Minute)
+	first.now = clock
+
+	second := NewMemoryLocker(lock, "second", time.Minute)
+	second.now = clock
+
+	held, err := first.Acquire()
+	assert.Nil(t, err)
+	assert.True(t, held)
+
+	held, _ = second.Acquire()
+	assert.False(t, held)
+
+	// Renewing keeps the lease alive past its original expiry
+	now = now.Add(50 * time.Second)
+	held, _ = first.Acquire()
+	assert.True(t, held)
+
+	now = now.Add(50 * time.Second)
+	held, _ = second.Acquire()
+	assert.False(t, held)
+
+	// The holder stops renewing and the lease expires
+	now = now.Add(time.Minute)
+	held, _ = second.Acquire()
+	assert.True(t, held)
+
+	held, _ = first.Acquire()
+	assert.False(t, held)
+
+	// Releasing by a non-holder has no effect
+	assert.Nil(t, first.Release())
+	held, _ = first.Acquire()
+	assert.False(t, held)
+
+	assert.Nil(t, second.Release())
+	held, _ = first.Acquire()
+	assert.True(t, held)
+}
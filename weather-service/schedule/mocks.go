@@ -1,36 +1,206 @@
 package schedule
 
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
+)
+
+var testCity = weather.City{
+	ID:    "cptec:241",
+	Name:  "City Name",
+	State: "XY",
+}
+
 type userAndCity struct {
-	userID   string
-	cityName string
+	userID string
+	city   weather.CityQuery
+	days   int
 }
 
 type serviceMock struct {
-	validateCalls []userAndCity
-	validateError error
+	validateCalls  []userAndCity
+	validateResult weather.City
+	validateError  error
 
+	// NotifyUserByCity is recorded here too
 	notifyCalls []userAndCity
 	notifyError error
 
-	saveCalls []Schedule
-	saveError error
+	evaluateCalls []userAndCity
+	evaluateError error
+
+	saveCalls   []Schedule
+	saveError   error
+	saveSkipped bool
+
+	findCalls  []string
+	findResult Schedule
+	findError  error
+
+	findAllCalls  []Filter
+	findAllResult []Schedule
+	findAllTotal  int
+	findAllError  error
 }
 
 var _ Validator = (*serviceMock)(nil)
 var _ ScheduleSaver = (*serviceMock)(nil)
 var _ Notifier = (*serviceMock)(nil)
+var _ Alerter = (*serviceMock)(nil)
+var _ ScheduleFinder = (*serviceMock)(nil)
 
-func (m *serviceMock) Validate(userID, cityName string) error {
-	m.validateCalls = append(m.validateCalls, userAndCity{userID: userID, cityName: cityName})
-	return m.validateError
+func (m *serviceMock) Validate(userID string, city weather.CityQuery) (weather.City, error) {
+	m.validateCalls = append(m.validateCalls, userAndCity{userID: userID, city: city})
+	return m.validateResult, m.validateError
 }
 
-func (m *serviceMock) NotifyUser(userID string, cityName string) error {
-	m.notifyCalls = append(m.notifyCalls, userAndCity{userID: userID, cityName: cityName})
+func (m *serviceMock) NotifyUser(userID string, city weather.CityQuery, days int, style, channel string) error {
+	m.notifyCalls = append(m.notifyCalls, userAndCity{userID: userID, city: city, days: days})
 	return m.notifyError
 }
 
+func (m *serviceMock) NotifyUserByCity(userID string, city weather.CityQuery, days int) error {
+	m.notifyCalls = append(m.notifyCalls, userAndCity{userID: userID, city: city, days: days})
+	return m.notifyError
+}
+
+func (m *serviceMock) Evaluate(userID, cityID string) error {
+	m.evaluateCalls = append(m.evaluateCalls, userAndCity{userID: userID, city: weather.CityQuery{ID: cityID}})
+	return m.evaluateError
+}
+
 func (m *serviceMock) Save(schedule Schedule) error {
 	m.saveCalls = append(m.saveCalls, schedule)
 	return m.saveError
 }
+
+func (m *serviceMock) SaveIfStatus(schedule Schedule, status string) (bool, error) {
+	m.saveCalls = append(m.saveCalls, schedule)
+	return !m.saveSkipped && m.saveError == nil, m.saveError
+}
+
+func (m *serviceMock) Find(id string) (Schedule, error) {
+	m.findCalls = append(m.findCalls, id)
+	return m.findResult, m.findError
+}
+
+func (m *serviceMock) FindAll(filter Filter) ([]Schedule, int, error) {
+	m.findAllCalls = append(m.findAllCalls, filter)
+	return m.findAllResult, m.findAllTotal, m.findAllError
+}
+
+// memoryRepository mimics the claim semantics of the database repository:
+// claiming is atomic, so concurrent jobs never get the same schedule.
+type memoryRepository struct {
+	mu        sync.Mutex
+	schedules map[string]Schedule
+}
+
+var _ ScheduleClaimer = (*memoryRepository)(nil)
+var _ ScheduleSaver = (*memoryRepository)(nil)
+var _ StuckScheduleFinder = (*memoryRepository)(nil)
+
+func newMemoryRepository(schedules ...Schedule) *memoryRepository {
+	r := &memoryRepository{schedules: map[string]Schedule{}}
+
+	for _, s := range schedules {
+		r.schedules[s.ID] = s
+	}
+
+	return r
+}
+
+func (r *memoryRepository) ClaimDue(now time.Time, limit int) ([]Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []Schedule
+
+	for _, s := range r.schedules {
+		if s.Status == StatusActive && !s.Time.After(now) {
+			due = append(due, s)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].Time.Before(due[j].Time) })
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].Status = StatusProcessing
+		due[i].Attempts++
+		due[i].ClaimedAt = now
+		r.schedules[due[i].ID] = due[i]
+	}
+
+	return due, nil
+}
+
+func (r *memoryRepository) Save(schedule Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (r *memoryRepository) SaveIfStatus(schedule Schedule, status string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.schedules[schedule.ID].Status != status {
+		return false, nil
+	}
+
+	r.schedules[schedule.ID] = schedule
+	return true, nil
+}
+
+func (r *memoryRepository) FindStuck(claimedBefore time.Time, limit int) ([]Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stuck []Schedule
+
+	for _, s := range r.schedules {
+		if s.Status == StatusProcessing && s.ClaimedAt.Before(claimedBefore) {
+			stuck = append(stuck, s)
+		}
+	}
+
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].ID < stuck[j].ID })
+
+	if len(stuck) > limit {
+		stuck = stuck[:limit]
+	}
+
+	return stuck, nil
+}
+
+func (r *memoryRepository) get(id string) Schedule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.schedules[id]
+}
+
+type publisherMock struct {
+	mu           sync.Mutex
+	publishCalls []string
+	publishError error
+}
+
+var _ SchedulePublisher = (*publisherMock)(nil)
+
+func (m *publisherMock) Publish(schedule Schedule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.publishCalls = append(m.publishCalls, schedule.ID)
+	return m.publishError
+}
@@ -3,6 +3,8 @@ package schedule
 import (
 	"errors"
 	"time"
+
+	"github.com/fgouvea/weather/weather-service/weather"
 )
 
 var (
@@ -10,18 +12,90 @@ var (
 	ErrScheduleInThePast = errors.New("schedule time cannot be in the past")
 	ErrFailedToSave      = errors.New("failed to save schedule")
 	ErrFailedToProcess   = errors.New("failed to process schedule")
+	ErrScheduleNotActive = errors.New("schedule is no longer active")
+	ErrInvalidStatus     = errors.New("invalid schedule status")
+	ErrProcessingTimeout = errors.New("schedule processing timed out")
 )
 
 const (
 	StatusActive     = "active"
 	StatusProcessing = "processing"
 	StatusCompleted  = "completed"
+	StatusCancelled  = "cancelled"
+	StatusFailed     = "failed"
 )
 
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Schedule is a notification to send at Time, or at every occurrence of
+// Recurrence. Days is the forecast horizon, where 0 means the default one.
+// AlertsOnly schedules send nothing but the alerts matching the rules of the
+// user for the city.
+// CityID, CityName and State are those of the city found when scheduling,
+// while Coordinates are kept as requested. Schedules saved before cities were
+// resolved have no CityID, and are found by name on every run.
 type Schedule struct {
-	ID       string
+	ID          string
+	UserID      string
+	CityID      string
+	CityName    string
+	State       string
+	Coordinates *weather.Coordinates
+	Status      string
+	Time        time.Time
+	Recurrence  string
+	Timezone    string
+	Days        int
+	AlertsOnly  bool
+	Attempts    int
+	LastError   string
+	ClaimedAt   time.Time
+}
+
+func (s Schedule) IsRecurring() bool {
+	return s.Recurrence != ""
+}
+
+// City is the query to find the city of schedules without a CityID
+func (s Schedule) City() weather.CityQuery {
+	return weather.CityQuery{ID: s.CityID, Name: s.CityName, State: s.State, Coordinates: s.Coordinates}
+}
+
+// Filter selects schedules when listing. Empty fields match everything.
+type Filter struct {
 	UserID   string
-	CityName string
 	Status   string
-	Time     time.Time
+	Page     int
+	PageSize int
+}
+
+type Page struct {
+	Schedules []Schedule
+	Page      int
+	PageSize  int
+	Total     int
+}
+
+// Update holds the fields to change on a schedule. Nil fields are kept as is,
+// except that setting any of the city fields replaces all of them.
+type Update struct {
+	Time        *time.Time
+	CityID      *string
+	CityName    *string
+	State       *string
+	Coordinates *weather.Coordinates
+	Days        *int
+	AlertsOnly  *bool
+}
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusActive, StatusProcessing, StatusCompleted, StatusCancelled, StatusFailed:
+		return true
+	}
+
+	return false
 }
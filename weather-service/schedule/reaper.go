@@ -0,0 +1,109 @@
+package schedule
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type StuckScheduleFinder interface {
+	FindStuck(claimedBefore time.Time, limit int) ([]Schedule, error)
+}
+
+type ScheduleFailer interface {
+	Fail(schedule Schedule, cause error) error
+}
+
+// Reaper looks for schedules left in processing for longer than Timeout,
+// which happens when publishing or consuming them dies halfway. They are put
+// back to active to be claimed again, or failed once MaxAttempts is reached.
+type Reaper struct {
+	Interval    time.Duration
+	Timeout     time.Duration
+	MaxAttempts int
+	BatchSize   int
+	Finder      StuckScheduleFinder
+	Saver       ScheduleSaver
+	Failer      ScheduleFailer
+	Locker      Locker
+	Logger      *zap.Logger
+}
+
+func NewReaper(interval, timeout time.Duration, maxAttempts, batchSize int, finder StuckScheduleFinder, saver ScheduleSaver, failer ScheduleFailer, locker Locker, logger *zap.Logger) *Reaper {
+	return &Reaper{
+		Interval:    interval,
+		Timeout:     timeout,
+		MaxAttempts: maxAttempts,
+		BatchSize:   batchSize,
+		Finder:      finder,
+		Saver:       saver,
+		Failer:      failer,
+		Locker:      locker,
+		Logger:      logger,
+	}
+}
+
+func (r *Reaper) Start() {
+	ticker := time.NewTicker(r.Interval)
+
+	go func() {
+		r.Logger.Info("starting schedule reaper")
+
+		for currentTime := range ticker.C {
+			r.Tick(currentTime)
+		}
+
+		r.Logger.Info("stopping schedule reaper")
+	}()
+}
+
+// Tick runs the reaper if this instance holds the lock.
+func (r *Reaper) Tick(now time.Time) {
+	held, err := r.Locker.Acquire()
+
+	if err != nil {
+		r.Logger.Error("error acquiring schedule reaper lock", zap.Error(err))
+		return
+	}
+
+	if !held {
+		return
+	}
+
+	r.Run(now)
+}
+
+// Run requeues or fails every schedule stuck in processing at the given time.
+func (r *Reaper) Run(now time.Time) {
+	schedules, err := r.Finder.FindStuck(now.Add(-r.Timeout), r.BatchSize)
+
+	if err != nil {
+		r.Logger.Error("error querying stuck schedules", zap.Error(err))
+		return
+	}
+
+	for _, schedule := range schedules {
+		if schedule.Attempts >= r.MaxAttempts {
+			r.Logger.Warn("failing stuck schedule", zap.String("scheduleID", schedule.ID), zap.Int("attempts", schedule.Attempts))
+
+			err := r.Failer.Fail(schedule, ErrProcessingTimeout)
+
+			if err != nil {
+				r.Logger.Error("error failing stuck schedule", zap.String("scheduleID", schedule.ID), zap.Error(err))
+			}
+
+			continue
+		}
+
+		r.Logger.Warn("requeueing stuck schedule", zap.String("scheduleID", schedule.ID), zap.Int("attempts", schedule.Attempts))
+
+		schedule.Status = StatusActive
+		schedule.LastError = ErrProcessingTimeout.Error()
+
+		_, err := r.Saver.SaveIfStatus(schedule, StatusProcessing)
+
+		if err != nil {
+			r.Logger.Error("error requeueing stuck schedule", zap.String("scheduleID", schedule.ID), zap.Error(err))
+		}
+	}
+}
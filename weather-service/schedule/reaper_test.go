@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestReaper_Run(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	now := time.Date(2025, 2, 7, 10, 0, 0, 0, time.UTC)
+
+	repository := newMemoryRepository(
+		Schedule{ID: "SCHEDULE-1", Status: StatusProcessing, Attempts: 1, ClaimedAt: now.Add(-time.Hour)},
+		Schedule{ID: "SCHEDULE-2", Status: StatusProcessing, Attempts: 3, ClaimedAt: now.Add(-time.Hour)},
+		Schedule{ID: "SCHEDULE-3", Status: StatusProcessing, Attempts: 3, ClaimedAt: now.Add(-time.Hour), Time: now.Add(-time.Hour), Recurrence: "@daily", Timezone: "UTC"},
+		Schedule{ID: "SCHEDULE-4", Status: StatusProcessing, Attempts: 1, ClaimedAt: now.Add(-time.Minute)},
+		Schedule{ID: "SCHEDULE-5", Status: StatusActive, Attempts: 1, ClaimedAt: now.Add(-time.Hour)},
+	)
+
+	service := NewService(repository, nil, nil, nil, nil)
+
+	reaper := NewReaper(time.Minute, 10*time.Minute, 3, 100, repository, repository, service, NewMemoryLocker(&MemoryLock{}, "instance", time.Minute), logger)
+
+	reaper.Run(now)
+
+	requeued := repository.get("SCHEDULE-1")
+	assert.Equal(t, StatusActive, requeued.Status)
+	assert.Equal(t, ErrProcessingTimeout.Error(), requeued.LastError)
+	assert.Equal(t, 1, requeued.Attempts)
+
+	failed := repository.get("SCHEDULE-2")
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, ErrProcessingTimeout.Error(), failed.LastError)
+
+	rearmed := repository.get("SCHEDULE-3")
+	assert.Equal(t, StatusActive, rearmed.Status)
+	assert.Equal(t, 0, rearmed.Attempts)
+	assert.True(t, rearmed.Time.After(time.Now()))
+
+	assert.Equal(t, StatusProcessing, repository.get("SCHEDULE-4").Status)
+	assert.Equal(t, StatusActive, repository.get("SCHEDULE-5").Status)
+}
+
+func TestReaper_Run_RequeuedScheduleIsClaimedAgain(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	now := time.Date(2025, 2, 7, 10, 0, 0, 0, time.UTC)
+	locker := NewMemoryLocker(&MemoryLock{}, "instance", time.Minute)
+
+	repository := newMemoryRepository(Schedule{ID: "SCHEDULE-1", Status: StatusActive, Time: now})
+	publisher := &publisherMock{}
+
+	job := NewJob(time.Minute, 10, repository, publisher, repository, locker, logger)
+	reaper := NewReaper(time.Minute, 10*time.Minute, 2, 100, repository, repository, NewService(repository, nil, nil, nil, nil), locker, logger)
+
+	// Published but never processed
+	job.Run(now)
+	reaper.Run(now.Add(20 * time.Minute))
+	job.Run(now.Add(20 * time.Minute))
+
+	// Still stuck after the second attempt
+	reaper.Run(now.Add(40 * time.Minute))
+	job.Run(now.Add(40 * time.Minute))
+
+	assert.Equal(t, []string{"SCHEDULE-1", "SCHEDULE-1"}, publisher.publishCalls)
+	assert.Equal(t, StatusFailed, repository.get("SCHEDULE-1").Status)
+	assert.Equal(t, 2, repository.get("SCHEDULE-1").Attempts)
+}
@@ -0,0 +1,241 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+// maxSearchYears bounds the search for the next occurrence, so expressions
+// that can never match (e.g. "0 0 31 2 *") fail instead of looping forever.
+const maxSearchYears = 5
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Recurrence is a parsed cron expression (minute hour day-of-month month
+// day-of-week) evaluated in a given timezone.
+type Recurrence struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+
+	location *time.Location
+}
+
+type field struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12}
+	dayOfWeekField  = field{name: "day of week", min: 0, max: 7}
+)
+
+func ParseRecurrence(expression, timezone string) (*Recurrence, error) {
+	location, err := time.LoadLocation(timezone)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %s", ErrInvalidRecurrence, timezone)
+	}
+
+	expression = strings.TrimSpace(expression)
+
+	if descriptor, exists := descriptors[expression]; exists {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidRecurrence, len(fields))
+	}
+
+	r := &Recurrence{
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+		location:      location,
+	}
+
+	specs := []struct {
+		target *uint64
+		field  field
+	}{
+		{&r.minute, minuteField},
+		{&r.hour, hourField},
+		{&r.dayOfMonth, dayOfMonthField},
+		{&r.month, monthField},
+		{&r.dayOfWeek, dayOfWeekField},
+	}
+
+	for i, spec := range specs {
+		bits, err := parseField(fields[i], spec.field)
+
+		if err != nil {
+			return nil, err
+		}
+
+		*spec.target = bits
+	}
+
+	// Both 0 and 7 mean Sunday
+	if r.dayOfWeek&(1<<7) != 0 {
+		r.dayOfWeek |= 1
+	}
+
+	return r, nil
+}
+
+// Next returns the first occurrence strictly after the given time.
+func (r *Recurrence) Next(after time.Time) time.Time {
+	t := after.In(r.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !has(r.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, r.location)
+			continue
+		}
+
+		if !r.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, r.location)
+			continue
+		}
+
+		if !has(r.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, r.location)
+			continue
+		}
+
+		if !has(r.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay follows cron semantics: when both day fields are restricted, a
+// day matches if either of them does.
+func (r *Recurrence) matchesDay(t time.Time) bool {
+	domMatch := has(r.dayOfMonth, t.Day())
+	dowMatch := has(r.dayOfWeek, int(t.Weekday()))
+
+	if r.anyDayOfMonth || r.anyDayOfWeek {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+func parseField(expression string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expression, ",") {
+		partBits, err := parseRange(part, f)
+
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= partBits
+	}
+
+	return bits, nil
+}
+
+func parseRange(expression string, f field) (uint64, error) {
+	step := 1
+	rangeExpression := expression
+
+	if i := strings.Index(expression, "/"); i >= 0 {
+		parsedStep, err := strconv.Atoi(expression[i+1:])
+
+		if err != nil || parsedStep <= 0 {
+			return 0, fmt.Errorf("%w: invalid step in %s field: %s", ErrInvalidRecurrence, f.name, expression)
+		}
+
+		step = parsedStep
+		rangeExpression = expression[:i]
+	}
+
+	start, end := f.min, f.max
+
+	switch {
+	case rangeExpression == "*":
+	case strings.Contains(rangeExpression, "-"):
+		bounds := strings.SplitN(rangeExpression, "-", 2)
+
+		var err error
+
+		if start, err = parseValue(bounds[0], f); err != nil {
+			return 0, err
+		}
+
+		if end, err = parseValue(bounds[1], f); err != nil {
+			return 0, err
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("%w: invalid range in %s field: %s", ErrInvalidRecurrence, f.name, expression)
+		}
+	default:
+		value, err := parseValue(rangeExpression, f)
+
+		if err != nil {
+			return 0, err
+		}
+
+		start = value
+
+		if step == 1 {
+			end = value
+		}
+	}
+
+	var bits uint64
+
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+
+	return bits, nil
+}
+
+func parseValue(expression string, f field) (int, error) {
+	value, err := strconv.Atoi(expression)
+
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("%w: invalid value in %s field: %s", ErrInvalidRecurrence, f.name, expression)
+	}
+
+	return value, nil
+}
+
+func has(bits uint64, value int) bool {
+	return bits&(1<<value) != 0
+}
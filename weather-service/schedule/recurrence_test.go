@@ -0,0 +1,129 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecurrence_Next(t *testing.T) {
+	tests := []struct {
+		name         string
+		expression   string
+		timezone     string
+		after        string
+		expectedNext string
+	}{
+		{
+			name:         "daily at fixed time later today",
+			expression:   "0 7 * * *",
+			timezone:     "UTC",
+			after:        "2025-02-07T05:30:00Z",
+			expectedNext: "2025-02-07T07:00:00Z",
+		},
+		{
+			name:         "daily at fixed time tomorrow",
+			expression:   "0 7 * * *",
+			timezone:     "UTC",
+			after:        "2025-02-07T07:00:00Z",
+			expectedNext: "2025-02-08T07:00:00Z",
+		},
+		{
+			name:         "weekdays skip the weekend",
+			expression:   "30 6 * * 1-5",
+			timezone:     "UTC",
+			after:        "2025-02-07T08:00:00Z",
+			expectedNext: "2025-02-10T06:30:00Z",
+		},
+		{
+			name:         "weekly on sunday using 7",
+			expression:   "0 9 * * 7",
+			timezone:     "UTC",
+			after:        "2025-02-07T08:00:00Z",
+			expectedNext: "2025-02-09T09:00:00Z",
+		},
+		{
+			name:         "step and list",
+			expression:   "*/15 8,20 * * *",
+			timezone:     "UTC",
+			after:        "2025-02-07T08:16:00Z",
+			expectedNext: "2025-02-07T08:30:00Z",
+		},
+		{
+			name:         "day of month or day of week",
+			expression:   "0 0 1 * 1",
+			timezone:     "UTC",
+			after:        "2025-02-07T00:00:00Z",
+			expectedNext: "2025-02-10T00:00:00Z",
+		},
+		{
+			name:         "descriptor",
+			expression:   "@monthly",
+			timezone:     "UTC",
+			after:        "2025-02-07T00:00:00Z",
+			expectedNext: "2025-03-01T00:00:00Z",
+		},
+		{
+			name:         "evaluated in timezone",
+			expression:   "0 7 * * *",
+			timezone:     "America/Sao_Paulo",
+			after:        "2025-02-07T09:00:00Z",
+			expectedNext: "2025-02-07T10:00:00Z",
+		},
+		{
+			name:         "never matches",
+			expression:   "0 0 31 2 *",
+			timezone:     "UTC",
+			after:        "2025-02-07T00:00:00Z",
+			expectedNext: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(tt.expression, tt.timezone)
+
+			assert.Nil(t, err)
+
+			after, _ := time.Parse(time.RFC3339, tt.after)
+
+			next := recurrence.Next(after)
+
+			if tt.expectedNext == "" {
+				assert.True(t, next.IsZero())
+				return
+			}
+
+			expectedNext, _ := time.Parse(time.RFC3339, tt.expectedNext)
+
+			assert.True(t, expectedNext.Equal(next), fmt.Sprintf("Expected: %s / Actual: %s", expectedNext, next))
+		})
+	}
+}
+
+func TestParseRecurrence_Error(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		timezone   string
+	}{
+		{name: "missing fields", expression: "0 7 * *", timezone: "UTC"},
+		{name: "value out of range", expression: "60 7 * * *", timezone: "UTC"},
+		{name: "invalid range", expression: "0 10-5 * * *", timezone: "UTC"},
+		{name: "invalid step", expression: "*/0 7 * * *", timezone: "UTC"},
+		{name: "not a number", expression: "0 seven * * *", timezone: "UTC"},
+		{name: "unknown timezone", expression: "0 7 * * *", timezone: "Mars/Olympus_Mons"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRecurrence(tt.expression, tt.timezone)
+
+			assert.Nil(t, result)
+			assert.True(t, errors.Is(err, ErrInvalidRecurrence), fmt.Sprintf("Expected: %s / Actual: %s", ErrInvalidRecurrence, err))
+		})
+	}
+}
@@ -1,76 +1,381 @@
 package schedule
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/fgouvea/weather/weather-service/weather"
 	"github.com/google/uuid"
 )
 
+const defaultTimezone = "UTC"
+
+// Validator checks a user and the city they asked for, returning the city
+// found.
 type Validator interface {
-	Validate(userID, cityName string) error
+	Validate(userID string, city weather.CityQuery) (weather.City, error)
 }
 
 type ScheduleSaver interface {
 	Save(schedule Schedule) error
+	// SaveIfStatus saves the schedule only if its stored status is still the
+	// given one, reporting whether it did.
+	SaveIfStatus(schedule Schedule, status string) (bool, error)
+}
+
+type ScheduleFinder interface {
+	Find(id string) (Schedule, error)
+	FindAll(filter Filter) ([]Schedule, int, error)
 }
 
+// Notifier sends the forecast in the style chosen by the user when given an
+// empty style, which is how schedules send it.
 type Notifier interface {
-	NotifyUser(userID, cityName string) error
+	NotifyUser(userID string, city weather.CityQuery, days int, style, channel string) error
+	NotifyUserByCity(userID string, city weather.CityQuery, days int) error
+}
+
+// Alerter notifies a user only about the weather matching their alert rules
+// for a city.
+type Alerter interface {
+	Evaluate(userID, cityID string) error
 }
 
 type Service struct {
 	Validator Validator
 	Saver     ScheduleSaver
+	Finder    ScheduleFinder
 	Notifier  Notifier
+	Alerter   Alerter
 }
 
-func NewService(saver ScheduleSaver, validator Validator, notifier Notifier) *Service {
+func NewService(saver ScheduleSaver, finder ScheduleFinder, validator Validator, notifier Notifier, alerter Alerter) *Service {
 	return &Service{
 		Validator: validator,
 		Notifier:  notifier,
+		Alerter:   alerter,
 		Saver:     saver,
+		Finder:    finder,
 	}
 }
 
-func (s *Service) Schedule(userID, cityName string, scheduleTime time.Time) error {
+func (s *Service) Schedule(userID string, city weather.CityQuery, scheduleTime time.Time, days int, alertsOnly bool) (Schedule, error) {
 	if scheduleTime.Before(time.Now()) {
-		return ErrScheduleInThePast
+		return Schedule{}, ErrScheduleInThePast
 	}
 
-	err := s.Validator.Validate(userID, cityName)
+	err := weather.ValidateForecastDays(days)
 
 	if err != nil {
-		return err
+		return Schedule{}, err
+	}
+
+	resolved, err := s.Validator.Validate(userID, city)
+
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	schedule := Schedule{
+		ID:          fmt.Sprintf("SCHEDULE-%s", uuid.New()),
+		Status:      StatusActive,
+		UserID:      userID,
+		CityID:      resolved.ID,
+		CityName:    resolved.Name,
+		State:       resolved.State,
+		Coordinates: city.Coordinates,
+		Time:        scheduleTime,
+		Days:        days,
+		AlertsOnly:  alertsOnly,
+	}
+
+	err = s.Saver.Save(schedule)
+
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %w", ErrFailedToSave, err)
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) ScheduleRecurring(userID string, city weather.CityQuery, recurrence, timezone string, days int, alertsOnly bool) (Schedule, error) {
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
+	parsedRecurrence, err := ParseRecurrence(recurrence, timezone)
+
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	firstRun := parsedRecurrence.Next(time.Now())
+
+	if firstRun.IsZero() {
+		return Schedule{}, fmt.Errorf("%w: expression never matches", ErrInvalidRecurrence)
+	}
+
+	err = weather.ValidateForecastDays(days)
+
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	resolved, err := s.Validator.Validate(userID, city)
+
+	if err != nil {
+		return Schedule{}, err
 	}
 
 	schedule := Schedule{
-		ID:       fmt.Sprintf("SCHEDULE-%s", uuid.New()),
-		Status:   StatusActive,
-		UserID:   userID,
-		CityName: cityName,
-		Time:     scheduleTime,
+		ID:          fmt.Sprintf("SCHEDULE-%s", uuid.New()),
+		Status:      StatusActive,
+		UserID:      userID,
+		CityID:      resolved.ID,
+		CityName:    resolved.Name,
+		State:       resolved.State,
+		Coordinates: city.Coordinates,
+		Time:        firstRun,
+		Recurrence:  recurrence,
+		Timezone:    timezone,
+		Days:        days,
+		AlertsOnly:  alertsOnly,
 	}
 
 	err = s.Saver.Save(schedule)
 
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %w", ErrFailedToSave, err)
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) Find(id string) (Schedule, error) {
+	schedule, err := s.Finder.Find(id)
+
+	if err != nil {
+		if errors.Is(err, ErrScheduleNotFound) {
+			return Schedule{}, err
+		}
+
+		return Schedule{}, fmt.Errorf("unexpected error fetching schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) List(filter Filter) (Page, error) {
+	if filter.Status != "" && !IsValidStatus(filter.Status) {
+		return Page{}, fmt.Errorf("%w: %s", ErrInvalidStatus, filter.Status)
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultPageSize
+	}
+
+	if filter.PageSize > MaxPageSize {
+		filter.PageSize = MaxPageSize
+	}
+
+	schedules, total, err := s.Finder.FindAll(filter)
+
+	if err != nil {
+		return Page{}, fmt.Errorf("unexpected error listing schedules: %w", err)
+	}
+
+	return Page{
+		Schedules: schedules,
+		Page:      filter.Page,
+		PageSize:  filter.PageSize,
+		Total:     total,
+	}, nil
+}
+
+func (s *Service) Update(id string, update Update) (Schedule, error) {
+	schedule, err := s.Find(id)
+
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	if schedule.Status != StatusActive {
+		return Schedule{}, ErrScheduleNotActive
+	}
+
+	if update.Time != nil {
+		if update.Time.Before(time.Now()) {
+			return Schedule{}, ErrScheduleInThePast
+		}
+
+		schedule.Time = *update.Time
+	}
+
+	if update.CityID != nil || update.CityName != nil || update.State != nil || update.Coordinates != nil {
+		city := weather.CityQuery{Coordinates: update.Coordinates}
+
+		if update.CityID != nil {
+			city.ID = *update.CityID
+		}
+
+		if update.CityName != nil {
+			city.Name = *update.CityName
+		}
+
+		if update.State != nil {
+			city.State = *update.State
+		}
+
+		resolved, err := s.Validator.Validate(schedule.UserID, city)
+
+		if err != nil {
+			return Schedule{}, err
+		}
+
+		schedule.CityID = resolved.ID
+		schedule.CityName = resolved.Name
+		schedule.State = resolved.State
+		schedule.Coordinates = city.Coordinates
+	}
+
+	if update.Days != nil {
+		err = weather.ValidateForecastDays(*update.Days)
+
+		if err != nil {
+			return Schedule{}, err
+		}
+
+		schedule.Days = *update.Days
+	}
+
+	if update.AlertsOnly != nil {
+		// Schedules from before cities were resolved have no ID to match rules by
+		if *update.AlertsOnly && schedule.CityID == "" {
+			return Schedule{}, fmt.Errorf("%w: alerts need the city to be set again", weather.ErrInvalidCity)
+		}
+
+		schedule.AlertsOnly = *update.AlertsOnly
+	}
+
+	saved, err := s.Saver.SaveIfStatus(schedule, StatusActive)
+
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %w", ErrFailedToSave, err)
+	}
+
+	// Claimed by the job in the meantime
+	if !saved {
+		return Schedule{}, ErrScheduleNotActive
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) Cancel(id string) error {
+	schedule, err := s.Find(id)
+
+	if err != nil {
+		return err
+	}
+
+	if schedule.Status == StatusCancelled {
+		return nil
+	}
+
+	if schedule.Status == StatusCompleted || schedule.Status == StatusFailed {
+		return ErrScheduleNotActive
+	}
+
+	previousStatus := schedule.Status
+	schedule.Status = StatusCancelled
+
+	saved, err := s.Saver.SaveIfStatus(schedule, previousStatus)
+
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrFailedToSave, err)
 	}
 
+	// The status changed in the meantime, so check it again
+	if !saved {
+		return s.Cancel(id)
+	}
+
 	return nil
 }
 
 func (s *Service) Process(schedule Schedule) error {
-	err := s.Notifier.NotifyUser(schedule.UserID, schedule.CityName)
+	current, err := s.Finder.Find(schedule.ID)
+
+	if errors.Is(err, ErrScheduleNotFound) {
+		return nil
+	}
 
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrFailedToProcess, err)
 	}
 
-	schedule.Status = StatusCompleted
+	// The schedule may have been cancelled, or reaped and claimed again, since
+	// this message was published
+	if current.Status != StatusProcessing || !current.ClaimedAt.Equal(schedule.ClaimedAt) {
+		return nil
+	}
 
-	err = s.Saver.Save(schedule)
+	switch {
+	case schedule.AlertsOnly:
+		err = s.Alerter.Evaluate(schedule.UserID, schedule.CityID)
+	case schedule.CityID != "":
+		err = s.Notifier.NotifyUserByCity(schedule.UserID, schedule.City(), schedule.Days)
+	default:
+		err = s.Notifier.NotifyUser(schedule.UserID, schedule.City(), schedule.Days, "", "")
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToProcess, err)
+	}
+
+	schedule.Attempts = 0
+	schedule.LastError = ""
+
+	schedule, err = s.rearm(schedule)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToProcess, err)
+	}
+
+	_, err = s.Saver.SaveIfStatus(schedule, StatusProcessing)
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToSave, err)
+	}
+
+	return nil
+}
+
+// Fail records why a claimed schedule could not be processed. One-off
+// schedules are marked as failed, while recurring ones move on to their next
+// occurrence.
+func (s *Service) Fail(schedule Schedule, cause error) error {
+	if schedule.IsRecurring() {
+		var err error
+		schedule, err = s.rearm(schedule)
+
+		if err != nil {
+			schedule.Status = StatusFailed
+		}
+
+		schedule.Attempts = 0
+	} else {
+		schedule.Status = StatusFailed
+	}
+
+	schedule.LastError = cause.Error()
+
+	_, err := s.Saver.SaveIfStatus(schedule, StatusProcessing)
 
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrFailedToSave, err)
@@ -78,3 +383,36 @@ func (s *Service) Process(schedule Schedule) error {
 
 	return nil
 }
+
+// rearm moves recurring schedules to their next occurrence and completes the
+// one-off ones.
+func (s *Service) rearm(schedule Schedule) (Schedule, error) {
+	if !schedule.IsRecurring() {
+		schedule.Status = StatusCompleted
+		return schedule, nil
+	}
+
+	recurrence, err := ParseRecurrence(schedule.Recurrence, schedule.Timezone)
+
+	if err != nil {
+		return schedule, err
+	}
+
+	after := schedule.Time
+
+	if now := time.Now(); now.After(after) {
+		after = now
+	}
+
+	next := recurrence.Next(after)
+
+	if next.IsZero() {
+		schedule.Status = StatusCompleted
+		return schedule, nil
+	}
+
+	schedule.Status = StatusActive
+	schedule.Time = next
+
+	return schedule, nil
+}
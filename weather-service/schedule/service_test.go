@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/fgouvea/weather/weather-service/user"
+	"github.com/fgouvea/weather/weather-service/weather"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,6 +15,7 @@ func TestService_Schedule(t *testing.T) {
 	tests := []struct {
 		name                  string
 		scheduleTime          string
+		days                  int
 		validateError         error
 		saveError             error
 		expectedError         error
@@ -38,6 +40,22 @@ func TestService_Schedule(t *testing.T) {
 			expectedValidateCalls: 0,
 			expectedSaveCalls:     0,
 		},
+		{
+			name:                  "extended forecast",
+			scheduleTime:          "2028-02-01T10:00:00Z",
+			days:                  7,
+			expectedError:         nil,
+			expectedValidateCalls: 1,
+			expectedSaveCalls:     1,
+		},
+		{
+			name:                  "invalid forecast days",
+			scheduleTime:          "2028-02-01T10:00:00Z",
+			days:                  8,
+			expectedError:         weather.ErrInvalidForecastDays,
+			expectedValidateCalls: 0,
+			expectedSaveCalls:     0,
+		},
 		{
 			name:                  "error validating",
 			scheduleTime:          "2028-02-01T10:00:00Z",
@@ -61,29 +79,39 @@ func TestService_Schedule(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &serviceMock{
-				validateError: tt.validateError,
-				saveError:     tt.saveError,
+				validateResult: testCity,
+				validateError:  tt.validateError,
+				saveError:      tt.saveError,
 			}
 
-			service := NewService(mock, mock, mock)
+			service := NewService(mock, mock, mock, mock, mock)
 
 			scheduleTime, _ := time.Parse(time.RFC3339, tt.scheduleTime)
 
-			err := service.Schedule("USER-ID", "city name", scheduleTime)
+			result, err := service.Schedule("USER-ID", weather.CityQuery{Name: "city name"}, scheduleTime, tt.days, false)
 
 			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
 
 			assert.Equal(t, tt.expectedValidateCalls, len(mock.validateCalls))
 			for i := 0; i < tt.expectedValidateCalls; i++ {
-				assert.Equal(t, userAndCity{userID: "USER-ID", cityName: "city name"}, mock.validateCalls[i])
+				assert.Equal(t, userAndCity{userID: "USER-ID", city: weather.CityQuery{Name: "city name"}}, mock.validateCalls[i])
 			}
 
 			assert.Equal(t, tt.expectedSaveCalls, len(mock.saveCalls))
 			for i := 0; i < tt.expectedSaveCalls; i++ {
 				assert.Equal(t, "USER-ID", mock.saveCalls[i].UserID)
 				assert.Equal(t, StatusActive, mock.saveCalls[i].Status)
-				assert.Equal(t, "city name", mock.saveCalls[i].CityName)
+				assert.Equal(t, "cptec:241", mock.saveCalls[i].CityID)
+				assert.Equal(t, "City Name", mock.saveCalls[i].CityName)
+				assert.Equal(t, "XY", mock.saveCalls[i].State)
 				assert.Equal(t, scheduleTime, mock.saveCalls[i].Time)
+				assert.Equal(t, tt.days, mock.saveCalls[i].Days)
+			}
+
+			if tt.expectedError == nil {
+				assert.Equal(t, mock.saveCalls[0], result)
+			} else {
+				assert.Equal(t, Schedule{}, result)
 			}
 		})
 	}
@@ -92,6 +120,7 @@ func TestService_Schedule(t *testing.T) {
 func TestService_Process(t *testing.T) {
 	tests := []struct {
 		name                string
+		cityID              string
 		notifyError         error
 		saveError           error
 		expectedError       error
@@ -106,6 +135,13 @@ func TestService_Process(t *testing.T) {
 			expectedNotifyCalls: 1,
 			expectedSaveCalls:   1,
 		},
+		{
+			name:                "success with resolved city",
+			cityID:              "cptec:241",
+			expectedError:       nil,
+			expectedNotifyCalls: 1,
+			expectedSaveCalls:   1,
+		},
 		{
 			name:                "error notifying",
 			notifyError:         errors.New("runtime error"),
@@ -127,25 +163,35 @@ func TestService_Process(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &serviceMock{
+				findResult:  Schedule{ID: "SCHEDULE-1", Status: StatusProcessing},
 				notifyError: tt.notifyError,
 				saveError:   tt.saveError,
 			}
 
-			service := NewService(mock, mock, mock)
+			service := NewService(mock, mock, mock, mock, mock)
 
 			schedule := Schedule{
-				UserID:   "USER-ID",
-				CityName: "city name",
-				Status:   StatusActive,
+				ID:        "SCHEDULE-1",
+				UserID:    "USER-ID",
+				CityID:    tt.cityID,
+				CityName:  "city name",
+				Status:    StatusProcessing,
+				Days:      5,
+				Attempts:  1,
+				LastError: ErrProcessingTimeout.Error(),
 			}
 
 			err := service.Process(schedule)
 
 			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
 
+			// Schedules with a resolved city are notified by ID, older ones by
+			// name, and both keep the name to find the city elsewhere
+			expectedCity := weather.CityQuery{ID: tt.cityID, Name: "city name"}
+
 			assert.Equal(t, tt.expectedNotifyCalls, len(mock.notifyCalls))
 			for i := 0; i < tt.expectedNotifyCalls; i++ {
-				assert.Equal(t, userAndCity{userID: "USER-ID", cityName: "city name"}, mock.notifyCalls[i])
+				assert.Equal(t, userAndCity{userID: "USER-ID", city: expectedCity, days: 5}, mock.notifyCalls[i])
 			}
 
 			assert.Equal(t, tt.expectedSaveCalls, len(mock.saveCalls))
@@ -153,6 +199,594 @@ func TestService_Process(t *testing.T) {
 				assert.Equal(t, "USER-ID", mock.saveCalls[i].UserID)
 				assert.Equal(t, StatusCompleted, mock.saveCalls[i].Status)
 				assert.Equal(t, "city name", mock.saveCalls[i].CityName)
+				assert.Equal(t, 0, mock.saveCalls[i].Attempts)
+				assert.Equal(t, "", mock.saveCalls[i].LastError)
+			}
+		})
+	}
+}
+
+func TestService_Process_AlertsOnly(t *testing.T) {
+	mock := &serviceMock{findResult: Schedule{ID: "SCHEDULE-1", Status: StatusProcessing}}
+
+	service := NewService(mock, mock, mock, mock, mock)
+
+	err := service.Process(Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityID: "cptec:241", Status: StatusProcessing, AlertsOnly: true})
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.notifyCalls)
+	assert.Equal(t, []userAndCity{{userID: "USER-ID", city: weather.CityQuery{ID: "cptec:241"}}}, mock.evaluateCalls)
+	assert.Equal(t, 1, len(mock.saveCalls))
+	assert.Equal(t, StatusCompleted, mock.saveCalls[0].Status)
+
+	mock.saveCalls = nil
+	mock.evaluateError = errors.New("runtime error")
+
+	err = service.Process(Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityID: "cptec:241", Status: StatusProcessing, AlertsOnly: true})
+
+	assert.ErrorIs(t, err, ErrFailedToProcess)
+	assert.Nil(t, mock.saveCalls)
+}
+
+func TestService_ScheduleRecurring(t *testing.T) {
+	tests := []struct {
+		name                  string
+		recurrence            string
+		timezone              string
+		validateError         error
+		saveError             error
+		expectedError         error
+		expectedTimezone      string
+		expectedValidateCalls int
+		expectedSaveCalls     int
+	}{
+		{
+			name:                  "success",
+			recurrence:            "0 7 * * *",
+			timezone:              "America/Sao_Paulo",
+			expectedError:         nil,
+			expectedTimezone:      "America/Sao_Paulo",
+			expectedValidateCalls: 1,
+			expectedSaveCalls:     1,
+		},
+		{
+			name:                  "success with default timezone",
+			recurrence:            "@daily",
+			timezone:              "",
+			expectedError:         nil,
+			expectedTimezone:      "UTC",
+			expectedValidateCalls: 1,
+			expectedSaveCalls:     1,
+		},
+		{
+			name:                  "invalid recurrence",
+			recurrence:            "every day",
+			timezone:              "UTC",
+			expectedError:         ErrInvalidRecurrence,
+			expectedValidateCalls: 0,
+			expectedSaveCalls:     0,
+		},
+		{
+			name:                  "recurrence never matches",
+			recurrence:            "0 0 30 2 *",
+			timezone:              "UTC",
+			expectedError:         ErrInvalidRecurrence,
+			expectedValidateCalls: 0,
+			expectedSaveCalls:     0,
+		},
+		{
+			name:                  "error validating",
+			recurrence:            "0 7 * * *",
+			timezone:              "UTC",
+			validateError:         user.ErrUserNotFound,
+			expectedError:         user.ErrUserNotFound,
+			expectedValidateCalls: 1,
+			expectedSaveCalls:     0,
+		},
+		{
+			name:                  "error saving schedule",
+			recurrence:            "0 7 * * *",
+			timezone:              "UTC",
+			saveError:             errors.New("failed to connect to db"),
+			expectedError:         ErrFailedToSave,
+			expectedTimezone:      "UTC",
+			expectedValidateCalls: 1,
+			expectedSaveCalls:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				validateError: tt.validateError,
+				saveError:     tt.saveError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock)
+
+			_, err := service.ScheduleRecurring("USER-ID", weather.CityQuery{Name: "city name"}, tt.recurrence, tt.timezone, 0, false)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+
+			assert.Equal(t, tt.expectedValidateCalls, len(mock.validateCalls))
+			assert.Equal(t, tt.expectedSaveCalls, len(mock.saveCalls))
+			for i := 0; i < tt.expectedSaveCalls; i++ {
+				assert.Equal(t, "USER-ID", mock.saveCalls[i].UserID)
+				assert.Equal(t, StatusActive, mock.saveCalls[i].Status)
+				assert.Equal(t, tt.recurrence, mock.saveCalls[i].Recurrence)
+				assert.Equal(t, tt.expectedTimezone, mock.saveCalls[i].Timezone)
+				assert.True(t, mock.saveCalls[i].Time.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestService_Process_Recurring(t *testing.T) {
+	mock := &serviceMock{
+		findResult: Schedule{ID: "SCHEDULE-1", Status: StatusProcessing},
+	}
+
+	service := NewService(mock, mock, mock, mock, mock)
+
+	scheduleTime := time.Now().Add(-time.Minute).Truncate(time.Minute)
+
+	schedule := Schedule{
+		ID:         "SCHEDULE-1",
+		UserID:     "USER-ID",
+		CityName:   "city name",
+		Status:     StatusProcessing,
+		Time:       scheduleTime,
+		Recurrence: "* * * * *",
+		Timezone:   "UTC",
+	}
+
+	err := service.Process(schedule)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mock.notifyCalls))
+	assert.Equal(t, 1, len(mock.saveCalls))
+	assert.Equal(t, StatusActive, mock.saveCalls[0].Status)
+	assert.True(t, mock.saveCalls[0].Time.After(time.Now()))
+	assert.Equal(t, "* * * * *", mock.saveCalls[0].Recurrence)
+}
+
+func TestService_Process_NoLongerClaimed(t *testing.T) {
+	claimedAt := time.Date(2025, 2, 7, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		current   Schedule
+		findError error
+	}{
+		{
+			name:    "cancelled",
+			current: Schedule{ID: "SCHEDULE-1", Status: StatusCancelled, ClaimedAt: claimedAt},
+		},
+		{
+			name:    "requeued by the reaper",
+			current: Schedule{ID: "SCHEDULE-1", Status: StatusActive, ClaimedAt: claimedAt},
+		},
+		{
+			name:    "claimed again after being requeued",
+			current: Schedule{ID: "SCHEDULE-1", Status: StatusProcessing, ClaimedAt: claimedAt.Add(time.Hour)},
+		},
+		{
+			name:      "deleted",
+			findError: ErrScheduleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				findResult: tt.current,
+				findError:  tt.findError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock)
+
+			err := service.Process(Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityName: "city name", Status: StatusProcessing, ClaimedAt: claimedAt})
+
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"SCHEDULE-1"}, mock.findCalls)
+			assert.Equal(t, 0, len(mock.notifyCalls))
+			assert.Equal(t, 0, len(mock.saveCalls))
+		})
+	}
+}
+
+func TestService_Fail(t *testing.T) {
+	tests := []struct {
+		name             string
+		schedule         Schedule
+		expectedStatus   string
+		expectedAttempts int
+		expectedFuture   bool
+	}{
+		{
+			name:             "one-off schedule",
+			schedule:         Schedule{ID: "SCHEDULE-1", Status: StatusProcessing, Attempts: 3, Time: time.Now().Add(-time.Hour)},
+			expectedStatus:   StatusFailed,
+			expectedAttempts: 3,
+			expectedFuture:   false,
+		},
+		{
+			name:             "recurring schedule",
+			schedule:         Schedule{ID: "SCHEDULE-1", Status: StatusProcessing, Attempts: 3, Time: time.Now().Add(-time.Hour), Recurrence: "@hourly", Timezone: "UTC"},
+			expectedStatus:   StatusActive,
+			expectedAttempts: 0,
+			expectedFuture:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{}
+
+			service := NewService(mock, mock, mock, mock, mock)
+
+			err := service.Fail(tt.schedule, user.ErrUserNotFound)
+
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(mock.saveCalls))
+			assert.Equal(t, tt.expectedStatus, mock.saveCalls[0].Status)
+			assert.Equal(t, tt.expectedAttempts, mock.saveCalls[0].Attempts)
+			assert.Equal(t, user.ErrUserNotFound.Error(), mock.saveCalls[0].LastError)
+			assert.Equal(t, tt.expectedFuture, mock.saveCalls[0].Time.After(time.Now()))
+		})
+	}
+}
+
+func TestService_Find(t *testing.T) {
+	tests := []struct {
+		name           string
+		findResult     Schedule
+		findError      error
+		expectedResult Schedule
+		expectedError  error
+	}{
+		{
+			name:           "success",
+			findResult:     Schedule{ID: "SCHEDULE-1", UserID: "USER-ID"},
+			expectedResult: Schedule{ID: "SCHEDULE-1", UserID: "USER-ID"},
+			expectedError:  nil,
+		},
+		{
+			name:           "schedule not found",
+			findError:      ErrScheduleNotFound,
+			expectedResult: Schedule{},
+			expectedError:  ErrScheduleNotFound,
+		},
+		{
+			name:           "unexpected error",
+			findError:      errors.New("failed to connect to db"),
+			expectedResult: Schedule{},
+			expectedError:  errors.New("unexpected error fetching schedule: failed to connect to db"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				findResult: tt.findResult,
+				findError:  tt.findError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock)
+
+			result, err := service.Find("SCHEDULE-1")
+
+			assert.Equal(t, tt.expectedError == nil, err == nil)
+			if err != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			}
+
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, []string{"SCHEDULE-1"}, mock.findCalls)
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		filter         Filter
+		findAllError   error
+		expectedFilter []Filter
+		expectedError  error
+	}{
+		{
+			name:           "defaults",
+			filter:         Filter{UserID: "USER-ID"},
+			expectedFilter: []Filter{{UserID: "USER-ID", Page: 1, PageSize: DefaultPageSize}},
+			expectedError:  nil,
+		},
+		{
+			name:           "page size capped",
+			filter:         Filter{Status: StatusActive, Page: 3, PageSize: 1000},
+			expectedFilter: []Filter{{Status: StatusActive, Page: 3, PageSize: MaxPageSize}},
+			expectedError:  nil,
+		},
+		{
+			name:           "invalid status",
+			filter:         Filter{Status: "sleeping"},
+			expectedFilter: nil,
+			expectedError:  ErrInvalidStatus,
+		},
+		{
+			name:           "error listing",
+			filter:         Filter{},
+			findAllError:   errors.New("failed to connect to db"),
+			expectedFilter: []Filter{{Page: 1, PageSize: DefaultPageSize}},
+			expectedError:  errors.New("failed to connect to db"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				findAllResult: []Schedule{{ID: "SCHEDULE-1"}},
+				findAllTotal:  21,
+				findAllError:  tt.findAllError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock)
+
+			result, err := service.List(tt.filter)
+
+			assert.Equal(t, tt.expectedFilter, mock.findAllCalls)
+
+			if tt.expectedError != nil {
+				assert.ErrorContains(t, err, tt.expectedError.Error())
+				assert.Equal(t, Page{}, result)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, Page{
+				Schedules: []Schedule{{ID: "SCHEDULE-1"}},
+				Page:      tt.expectedFilter[0].Page,
+				PageSize:  tt.expectedFilter[0].PageSize,
+				Total:     21,
+			}, result)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	future := time.Now().Add(time.Hour).Truncate(time.Second)
+	past := time.Now().Add(-time.Hour)
+	city := "other city"
+	cityID := "cptec:244"
+	state := "SP"
+	days := 6
+	invalidDays := 10
+	alertsOnly := true
+	coordinates := &weather.Coordinates{Latitude: -22.9, Longitude: -43.2}
+
+	tests := []struct {
+		name                  string
+		current               Schedule
+		findError             error
+		update                Update
+		validateResult        weather.City
+		validateError         error
+		saveError             error
+		saveSkipped           bool
+		expectedError         error
+		expectedValidateCalls []userAndCity
+		expectedSaved         []Schedule
+	}{
+		{
+			name:                  "move time and city",
+			current:               Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityName: "city name", Status: StatusActive},
+			update:                Update{Time: &future, CityName: &city},
+			validateResult:        weather.City{ID: "cptec:242", Name: "Other City", State: "XY"},
+			expectedError:         nil,
+			expectedValidateCalls: []userAndCity{{userID: "USER-ID", city: weather.CityQuery{Name: "other city"}}},
+			expectedSaved:         []Schedule{{ID: "SCHEDULE-1", UserID: "USER-ID", CityID: "cptec:242", CityName: "Other City", State: "XY", Status: StatusActive, Time: future}},
+		},
+		{
+			name:                  "move time only",
+			current:               Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityName: "city name", Status: StatusActive},
+			update:                Update{Time: &future},
+			expectedError:         nil,
+			expectedValidateCalls: nil,
+			expectedSaved:         []Schedule{{ID: "SCHEDULE-1", UserID: "USER-ID", CityName: "city name", Status: StatusActive, Time: future}},
+		},
+		{
+			name:                  "move to coordinates",
+			current:               Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityName: "city name", Status: StatusActive},
+			update:                Update{Coordinates: coordinates},
+			expectedError:         nil,
+			expectedValidateCalls: []userAndCity{{userID: "USER-ID", city: weather.CityQuery{Coordinates: coordinates}}},
+			expectedSaved:         []Schedule{{ID: "SCHEDULE-1", UserID: "USER-ID", Coordinates: coordinates, Status: StatusActive}},
+		},
+		{
+			name:                  "move to city by ID",
+			current:               Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityName: "city name", State: "RJ", Status: StatusActive},
+			update:                Update{CityID: &cityID},
+			validateResult:        weather.City{ID: "cptec:244", Name: "São Paulo", State: "SP"},
+			expectedError:         nil,
+			expectedValidateCalls: []userAndCity{{userID: "USER-ID", city: weather.CityQuery{ID: "cptec:244"}}},
+			expectedSaved:         []Schedule{{ID: "SCHEDULE-1", UserID: "USER-ID", CityID: "cptec:244", CityName: "São Paulo", State: "SP", Status: StatusActive}},
+		},
+		{
+			name:                  "move to city in state",
+			current:               Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", CityID: "cptec:244", Status: StatusActive},
+			update:                Update{CityName: &city, State: &state},
+			validateResult:        weather.City{ID: "cptec:243", Name: "Other City", State: "SP"},
+			expectedError:         nil,
+			expectedValidateCalls: []userAndCity{{userID: "USER-ID", city: weather.CityQuery{Name: "other city", State: "SP"}}},
+			expectedSaved:         []Schedule{{ID: "SCHEDULE-1", UserID: "USER-ID", CityID: "cptec:243", CityName: "Other City", State: "SP", Status: StatusActive}},
+		},
+		{
+			name:          "change forecast days",
+			current:       Schedule{ID: "SCHEDULE-1", Status: StatusActive},
+			update:        Update{Days: &days},
+			expectedError: nil,
+			expectedSaved: []Schedule{{ID: "SCHEDULE-1", Status: StatusActive, Days: 6}},
+		},
+		{
+			name:          "switch to alerts only",
+			current:       Schedule{ID: "SCHEDULE-1", CityID: "cptec:241", Status: StatusActive},
+			update:        Update{AlertsOnly: &alertsOnly},
+			expectedError: nil,
+			expectedSaved: []Schedule{{ID: "SCHEDULE-1", CityID: "cptec:241", Status: StatusActive, AlertsOnly: true}},
+		},
+		{
+			name:          "alerts only without resolved city",
+			current:       Schedule{ID: "SCHEDULE-1", CityName: "city name", Status: StatusActive},
+			update:        Update{AlertsOnly: &alertsOnly},
+			expectedError: weather.ErrInvalidCity,
+		},
+		{
+			name:          "invalid forecast days",
+			current:       Schedule{ID: "SCHEDULE-1", Status: StatusActive},
+			update:        Update{Days: &invalidDays},
+			expectedError: weather.ErrInvalidForecastDays,
+		},
+		{
+			name:          "schedule not found",
+			findError:     ErrScheduleNotFound,
+			update:        Update{Time: &future},
+			expectedError: ErrScheduleNotFound,
+		},
+		{
+			name:          "schedule not active",
+			current:       Schedule{ID: "SCHEDULE-1", Status: StatusCompleted},
+			update:        Update{Time: &future},
+			expectedError: ErrScheduleNotActive,
+		},
+		{
+			name:          "time in the past",
+			current:       Schedule{ID: "SCHEDULE-1", Status: StatusActive},
+			update:        Update{Time: &past},
+			expectedError: ErrScheduleInThePast,
+		},
+		{
+			name:                  "invalid city",
+			current:               Schedule{ID: "SCHEDULE-1", UserID: "USER-ID", Status: StatusActive},
+			update:                Update{CityName: &city},
+			validateError:         user.ErrUserNotFound,
+			expectedError:         user.ErrUserNotFound,
+			expectedValidateCalls: []userAndCity{{userID: "USER-ID", city: weather.CityQuery{Name: "other city"}}},
+		},
+		{
+			name:          "claimed while updating",
+			current:       Schedule{ID: "SCHEDULE-1", Status: StatusActive},
+			update:        Update{Time: &future},
+			saveSkipped:   true,
+			expectedError: ErrScheduleNotActive,
+			expectedSaved: []Schedule{{ID: "SCHEDULE-1", Status: StatusActive, Time: future}},
+		},
+		{
+			name:          "error saving",
+			current:       Schedule{ID: "SCHEDULE-1", Status: StatusActive},
+			update:        Update{Time: &future},
+			saveError:     errors.New("failed to connect to db"),
+			expectedError: ErrFailedToSave,
+			expectedSaved: []Schedule{{ID: "SCHEDULE-1", Status: StatusActive, Time: future}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				findResult:     tt.current,
+				findError:      tt.findError,
+				validateResult: tt.validateResult,
+				validateError:  tt.validateError,
+				saveError:      tt.saveError,
+				saveSkipped:    tt.saveSkipped,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock)
+
+			result, err := service.Update("SCHEDULE-1", tt.update)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedValidateCalls, mock.validateCalls)
+			assert.Equal(t, tt.expectedSaved, mock.saveCalls)
+
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedSaved[0], result)
+			}
+		})
+	}
+}
+
+func TestService_Cancel(t *testing.T) {
+	tests := []struct {
+		name              string
+		currentStatus     string
+		findError         error
+		saveError         error
+		expectedError     error
+		expectedSaveCalls int
+	}{
+		{
+			name:              "cancel active schedule",
+			currentStatus:     StatusActive,
+			expectedError:     nil,
+			expectedSaveCalls: 1,
+		},
+		{
+			name:              "cancel processing schedule",
+			currentStatus:     StatusProcessing,
+			expectedError:     nil,
+			expectedSaveCalls: 1,
+		},
+		{
+			name:              "already cancelled",
+			currentStatus:     StatusCancelled,
+			expectedError:     nil,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "already failed",
+			currentStatus:     StatusFailed,
+			expectedError:     ErrScheduleNotActive,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "already completed",
+			currentStatus:     StatusCompleted,
+			expectedError:     ErrScheduleNotActive,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "schedule not found",
+			findError:         ErrScheduleNotFound,
+			expectedError:     ErrScheduleNotFound,
+			expectedSaveCalls: 0,
+		},
+		{
+			name:              "error saving",
+			currentStatus:     StatusActive,
+			saveError:         errors.New("failed to connect to db"),
+			expectedError:     ErrFailedToSave,
+			expectedSaveCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &serviceMock{
+				findResult: Schedule{ID: "SCHEDULE-1", Status: tt.currentStatus},
+				findError:  tt.findError,
+				saveError:  tt.saveError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock)
+
+			err := service.Cancel("SCHEDULE-1")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+
+			assert.Equal(t, tt.expectedSaveCalls, len(mock.saveCalls))
+			for i := 0; i < tt.expectedSaveCalls; i++ {
+				assert.Equal(t, StatusCancelled, mock.saveCalls[i].Status)
 			}
 		})
 	}
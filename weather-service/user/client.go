@@ -54,7 +54,9 @@ func (c *Client) FindUser(id string) (User, error) {
 	}
 
 	return User{
-		ID:   parsedResponse.ID,
-		Name: parsedResponse.Name,
+		ID:            parsedResponse.ID,
+		Name:          parsedResponse.Name,
+		Locale:        parsedResponse.Locale,
+		ForecastStyle: parsedResponse.ForecastStyle,
 	}, nil
 }
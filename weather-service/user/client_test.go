@@ -28,6 +28,28 @@ func TestClient_Find(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:            "with locale",
+			apiResponseCode: 200,
+			apiResponse:     `{"id":"USER-123","name":"Fulano","locale":"en","notification":{"enabled":true,"web":{"enabled":false,"id":""}}}`,
+			expectedResult: User{
+				ID:     "USER-123",
+				Name:   "Fulano",
+				Locale: "en",
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "with forecast style",
+			apiResponseCode: 200,
+			apiResponse:     `{"id":"USER-123","name":"Fulano","forecastStyle":"detailed","notification":{"enabled":true,"web":{"enabled":false,"id":""}}}`,
+			expectedResult: User{
+				ID:            "USER-123",
+				Name:          "Fulano",
+				ForecastStyle: "detailed",
+			},
+			expectedError: nil,
+		},
 		{
 			name:            "user not found",
 			apiResponseCode: 404,
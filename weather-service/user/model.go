@@ -3,6 +3,8 @@ package user
 type UserTO struct {
 	ID                 string               `json:"id"`
 	Name               string               `json:"name"`
+	Locale             string               `json:"locale"`
+	ForecastStyle      string               `json:"forecastStyle"`
 	NotificationConfig NotificationConfigTO `json:"notification"`
 }
 
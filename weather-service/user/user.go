@@ -4,7 +4,12 @@ import "errors"
 
 var ErrUserNotFound = errors.New("user not found")
 
+// User is who notifications go to. Locale picks the language they are
+// written in and ForecastStyle how much of the forecast they get, and both
+// may be empty.
 type User struct {
-	ID   string
-	Name string
+	ID            string
+	Name          string
+	Locale        string
+	ForecastStyle string
 }
@@ -0,0 +1,32 @@
+package weather
+
+import "fmt"
+
+type CurrentConditionsProvider interface {
+	GetCurrentConditions(station string) (CurrentConditions, error)
+}
+
+// StationConditions finds the current conditions of a city through the
+// weather station mapped to it, usually the one at the nearest airport.
+type StationConditions struct {
+	Provider CurrentConditionsProvider
+	// ICAO station codes by city ID
+	Stations map[string]string
+}
+
+func NewStationConditions(provider CurrentConditionsProvider, stations map[string]string) *StationConditions {
+	return &StationConditions{
+		Provider: provider,
+		Stations: stations,
+	}
+}
+
+func (s *StationConditions) GetCityConditions(cityID string) (CurrentConditions, error) {
+	station, mapped := s.Stations[cityID]
+
+	if !mapped {
+		return CurrentConditions{}, fmt.Errorf("%w: no station for city %s", ErrStationNotFound, cityID)
+	}
+
+	return s.Provider.GetCurrentConditions(station)
+}
@@ -4,3 +4,9 @@ import "errors"
 
 var ErrCityNotFound = errors.New("city not found")
 var ErrMultipleCities = errors.New("multiple cities found with name")
+var ErrProviderUnavailable = errors.New("weather provider unavailable")
+var ErrInvalidCity = errors.New("invalid city")
+var ErrStationNotFound = errors.New("weather station not found")
+var ErrWavesNotFound = errors.New("wave forecast not found")
+var ErrInvalidForecastDays = errors.New("invalid number of forecast days")
+var ErrInvalidForecastStyle = errors.New("invalid forecast style")
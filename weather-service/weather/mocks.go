@@ -43,23 +43,54 @@ type mockClient struct {
 	findCityResult City
 	findCityError  error
 
+	findCityByCoordinatesCalls []Coordinates
+	findCityByIDCalls          []string
+	findCityStates             []string
+
+	relocateCalls  []CityQuery
+	relocateResult City
+	relocateError  error
+
+	searchCitiesCalls  []string
+	searchCitiesResult []City
+	searchCitiesError  error
+
 	getForecastCalls  []string
 	getForecastResult CityForecast
 	getForecastError  error
 
+	getExtendedForecastCalls  []string
+	getExtendedForecastResult CityForecast
+	getExtendedForecastError  error
+
 	getWaveForecastCalls  []string
 	getWaveForecastResult CityWaveForecast
 	getWaveForecastError  error
 
-	notifyCallsUserID  []string
-	notifyCallsContent []string
-	notifyError        error
+	getWaveForecastRangeCalls  []string
+	getWaveForecastRangeDays   []int
+	getWaveForecastRangeResult []CityWaveForecast
+	getWaveForecastRangeError  error
+
+	getCityConditionsCalls  []string
+	getCityConditionsResult CurrentConditions
+	getCityConditionsError  error
+
+	notifyCallsUserID       []string
+	notifyCallsContent      []string
+	notifyCallsNotification []Notification
+	notifyError             error
 }
 
 var _ UserFinder = (*mockClient)(nil)
 var _ CityFinder = (*mockClient)(nil)
+var _ CityRelocator = (*mockClient)(nil)
+var _ CitySearcher = (*mockClient)(nil)
 var _ WeatherForecaster = (*mockClient)(nil)
+var _ ExtendedForecaster = (*mockClient)(nil)
 var _ WaveForecaster = (*mockClient)(nil)
+var _ CityConditionsFinder = (*mockClient)(nil)
+var _ CurrentConditionsProvider = (*mockClient)(nil)
 var _ Notifier = (*mockClient)(nil)
 
 func (m *mockClient) FindUser(id string) (user.User, error) {
@@ -67,8 +98,31 @@ func (m *mockClient) FindUser(id string) (user.User, error) {
 	return m.findUserResult, m.findUserError
 }
 
-func (m *mockClient) FindCity(name string) (City, error) {
+func (m *mockClient) FindCity(name, state string) (City, error) {
 	m.findCityCalls = append(m.findCityCalls, name)
+	m.findCityStates = append(m.findCityStates, state)
+	return m.findCityResult, m.findCityError
+}
+
+// FindCityByID shares the result of FindCity
+func (m *mockClient) FindCityByID(id string) (City, error) {
+	m.findCityByIDCalls = append(m.findCityByIDCalls, id)
+	return m.findCityResult, m.findCityError
+}
+
+func (m *mockClient) Relocate(id string, query CityQuery) (City, error) {
+	m.relocateCalls = append(m.relocateCalls, query)
+	return m.relocateResult, m.relocateError
+}
+
+func (m *mockClient) SearchCities(name string) ([]City, error) {
+	m.searchCitiesCalls = append(m.searchCitiesCalls, name)
+	return m.searchCitiesResult, m.searchCitiesError
+}
+
+// FindCityByCoordinates shares the result of FindCity
+func (m *mockClient) FindCityByCoordinates(latitude, longitude float64) (City, error) {
+	m.findCityByCoordinatesCalls = append(m.findCityByCoordinatesCalls, Coordinates{Latitude: latitude, Longitude: longitude})
 	return m.findCityResult, m.findCityError
 }
 
@@ -77,13 +131,35 @@ func (m *mockClient) GetForecast(id string) (CityForecast, error) {
 	return m.getForecastResult, m.getForecastError
 }
 
+func (m *mockClient) GetExtendedForecast(id string) (CityForecast, error) {
+	m.getExtendedForecastCalls = append(m.getExtendedForecastCalls, id)
+	return m.getExtendedForecastResult, m.getExtendedForecastError
+}
+
 func (m *mockClient) GetWaveForecast(id string) (CityWaveForecast, error) {
 	m.getWaveForecastCalls = append(m.getWaveForecastCalls, id)
 	return m.getWaveForecastResult, m.getWaveForecastError
 }
 
-func (m *mockClient) Notify(userID string, content string) error {
+func (m *mockClient) GetWaveForecastRange(id string, days int) ([]CityWaveForecast, error) {
+	m.getWaveForecastRangeCalls = append(m.getWaveForecastRangeCalls, id)
+	m.getWaveForecastRangeDays = append(m.getWaveForecastRangeDays, days)
+	return m.getWaveForecastRangeResult, m.getWaveForecastRangeError
+}
+
+func (m *mockClient) GetCityConditions(cityID string) (CurrentConditions, error) {
+	m.getCityConditionsCalls = append(m.getCityConditionsCalls, cityID)
+	return m.getCityConditionsResult, m.getCityConditionsError
+}
+
+func (m *mockClient) GetCurrentConditions(station string) (CurrentConditions, error) {
+	m.getCityConditionsCalls = append(m.getCityConditionsCalls, station)
+	return m.getCityConditionsResult, m.getCityConditionsError
+}
+
+func (m *mockClient) Notify(userID string, notification Notification) error {
 	m.notifyCallsUserID = append(m.notifyCallsUserID, userID)
-	m.notifyCallsContent = append(m.notifyCallsContent, content)
+	m.notifyCallsContent = append(m.notifyCallsContent, notification.Text)
+	m.notifyCallsNotification = append(m.notifyCallsNotification, notification)
 	return m.notifyError
 }
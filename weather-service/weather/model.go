@@ -1,24 +1,158 @@
 package weather
 
+import (
+	"fmt"
+
+	"github.com/fgouvea/weather/weather-service/message"
+)
+
+// CityQuery identifies a city by the first of these that is given: its ID,
+// its coordinates or its name, optionally narrowed down by State (UF).
+type CityQuery struct {
+	ID          string
+	Name        string
+	State       string
+	Coordinates *Coordinates
+}
+
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (q CityQuery) Validate() error {
+	if q.ID != "" {
+		return nil
+	}
+
+	if q.Coordinates == nil {
+		if q.Name == "" {
+			return fmt.Errorf("%w: either id, name or coordinates are required", ErrInvalidCity)
+		}
+
+		return nil
+	}
+
+	if q.Coordinates.Latitude < -90 || q.Coordinates.Latitude > 90 || q.Coordinates.Longitude < -180 || q.Coordinates.Longitude > 180 {
+		return fmt.Errorf("%w: coordinates out of range: %f, %f", ErrInvalidCity, q.Coordinates.Latitude, q.Coordinates.Longitude)
+	}
+
+	return nil
+}
+
 type City struct {
 	ID    string
 	Name  string
 	State string
 }
 
+// ForecastMessage is given to the "forecast" template. Conditions is nil
+// when the city has no weather station. Detailed asks for the condition, UV
+// index, wave direction and wind of each day, besides the temperatures and
+// wave heights.
+type ForecastMessage struct {
+	UserName   string
+	City       City
+	Conditions *CurrentConditions
+	Forecast   []Forecast
+	Waves      []CityWaveForecast
+	Detailed   bool
+}
+
+// Notification is what is sent to a user: the message written for each kind
+// of channel and the data it was written from, for channels that present it
+// on their own.
+type Notification struct {
+	message.Content
+	Payload Payload
+	// Channel is where the notification is sent, or empty for every channel
+	// enabled by the user
+	Channel string
+}
+
+// Payload is the data of a notification. Type is the name of the template it
+// was rendered from, such as "forecast".
+type Payload struct {
+	Type       string
+	City       City
+	Conditions *CurrentConditions
+	Days       []PayloadDay
+}
+
+// PayloadDay is the forecast of a date. Waves is nil for cities without a
+// wave forecast, and Previous is only set for revisions.
+type PayloadDay struct {
+	Forecast Forecast
+	Waves    *CityWaveForecast
+	Previous *Forecast
+}
+
+// NewPayloadDays pairs each day of the forecast with its waves
+func NewPayloadDays(forecast []Forecast, waves []CityWaveForecast) []PayloadDay {
+	byDate := map[string]*CityWaveForecast{}
+
+	for i := range waves {
+		byDate[waves[i].Date] = &waves[i]
+	}
+
+	days := make([]PayloadDay, len(forecast))
+
+	for i, day := range forecast {
+		days[i] = PayloadDay{Forecast: day, Waves: byDate[day.Date]}
+	}
+
+	return days
+}
+
 type CityForecast struct {
 	UpdatedAt string
 	Forecast  []Forecast
 }
 
+// Forecast is the weather of a day. Code is the CPTEC condition code, such
+// as "c" for rain, or empty when the provider's condition is unknown, and
+// Weather is its name.
 type Forecast struct {
 	Date           string
+	Code           string
 	Weather        string
 	MaxTemperature int
 	MinTemperature int
 	IUV            float64
 }
 
+// UVRisk is the risk category of the UV index, in the scale used by INMET
+// and the WHO
+func (f Forecast) UVRisk() string {
+	switch {
+	case f.IUV < 3:
+		return "baixo"
+	case f.IUV < 6:
+		return "moderado"
+	case f.IUV < 8:
+		return "alto"
+	case f.IUV < 11:
+		return "muito alto"
+	default:
+		return "extremo"
+	}
+}
+
+// CurrentConditions are the latest observations of a weather station.
+// Temperature is in °C, pressure in hPa, humidity in %, wind speed in km/h,
+// wind direction in degrees and visibility in meters.
+type CurrentConditions struct {
+	Station       string
+	UpdatedAt     string
+	Weather       string
+	Temperature   int
+	Pressure      int
+	Humidity      int
+	WindSpeed     int
+	WindDirection int
+	Visibility    int
+}
+
 type CityWaveForecast struct {
 	UpdatedAt string
 	Date      string
@@ -0,0 +1,29 @@
+package weather
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName lowers the case, drops accents and collapses spaces, so that
+// names typed by users match the ones from providers ("sao paulo" and
+// "São Paulo").
+func NormalizeName(name string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	normalized, _, err := transform.String(stripAccents, name)
+
+	if err != nil {
+		normalized = name
+	}
+
+	return strings.Join(strings.Fields(strings.ToLower(normalized)), " ")
+}
+
+func SameName(a, b string) bool {
+	return NormalizeName(a) == NormalizeName(b)
+}
@@ -0,0 +1,26 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "São Paulo", expected: "sao paulo"},
+		{name: "  sao   PAULO ", expected: "sao paulo"},
+		{name: "Florianópolis", expected: "florianopolis"},
+		{name: "Mogi Guaçu", expected: "mogi guacu"},
+		{name: "Itaúna", expected: "itauna"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeName(tt.name))
+		})
+	}
+}
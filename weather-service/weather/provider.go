@@ -0,0 +1,194 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Provider is a source of weather data, such as CPTEC.
+type Provider interface {
+	CityFinder
+	CitySearcher
+	WeatherForecaster
+	ExtendedForecaster
+	WaveForecaster
+}
+
+type NamedProvider struct {
+	Name     string
+	Provider Provider
+}
+
+// FallbackProvider tries its providers in order, moving on to the next one
+// whenever a provider is unavailable (or does not know the city, when
+// searching). City IDs are prefixed with the name of the provider that found
+// them, so later forecasts are fetched from the same provider. When that
+// provider is down, Relocate finds the city on the others.
+type FallbackProvider struct {
+	Providers []NamedProvider
+}
+
+// cityLookup finds a city on a single provider
+type cityLookup func(CityFinder) (City, error)
+
+var _ Provider = (*FallbackProvider)(nil)
+
+func NewFallbackProvider(providers ...NamedProvider) *FallbackProvider {
+	return &FallbackProvider{
+		Providers: providers,
+	}
+}
+
+func (f *FallbackProvider) FindCity(name, state string) (City, error) {
+	return f.findCity(findByName(name, state), -1)
+}
+
+func (f *FallbackProvider) FindCityByCoordinates(latitude, longitude float64) (City, error) {
+	return f.findCity(findByCoordinates(latitude, longitude), -1)
+}
+
+// FindCityByID finds the city on the provider of the ID
+func (f *FallbackProvider) FindCityByID(id string) (City, error) {
+	index, providerID := f.resolve(id)
+	provider := f.Providers[index]
+
+	city, err := provider.Provider.FindCityByID(providerID)
+
+	if err != nil {
+		return City{}, err
+	}
+
+	city.ID = fmt.Sprintf("%s:%s", provider.Name, city.ID)
+
+	return city, nil
+}
+
+// Relocate finds the city of the ID on the providers other than its own, by its
+// coordinates or else its name and state, for when its provider is
+// unavailable. Callers keep those along with the ID, so IDs stored long ago
+// fall back as well as the ones just found.
+func (f *FallbackProvider) Relocate(id string, query CityQuery) (City, error) {
+	index, _ := f.resolve(id)
+
+	switch {
+	case query.Coordinates != nil:
+		return f.findCity(findByCoordinates(query.Coordinates.Latitude, query.Coordinates.Longitude), index)
+	case query.Name != "":
+		return f.findCity(findByName(query.Name, query.State), index)
+	default:
+		return City{}, fmt.Errorf("%w: no name or coordinates to find %s elsewhere", ErrCityNotFound, id)
+	}
+}
+
+// SearchCities searches the first available provider.
+func (f *FallbackProvider) SearchCities(name string) ([]City, error) {
+	var err error
+
+	for _, provider := range f.Providers {
+		var cities []City
+		cities, err = provider.Provider.SearchCities(name)
+
+		if errors.Is(err, ErrProviderUnavailable) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range cities {
+			cities[i].ID = fmt.Sprintf("%s:%s", provider.Name, cities[i].ID)
+		}
+
+		return cities, nil
+	}
+
+	return nil, err
+}
+
+func findByName(name, state string) cityLookup {
+	return func(p CityFinder) (City, error) {
+		return p.FindCity(name, state)
+	}
+}
+
+func findByCoordinates(latitude, longitude float64) cityLookup {
+	return func(p CityFinder) (City, error) {
+		return p.FindCityByCoordinates(latitude, longitude)
+	}
+}
+
+func (f *FallbackProvider) GetForecast(id string) (CityForecast, error) {
+	return route(f, id, func(p Provider, id string) (CityForecast, error) {
+		return p.GetForecast(id)
+	})
+}
+
+func (f *FallbackProvider) GetExtendedForecast(id string) (CityForecast, error) {
+	return route(f, id, func(p Provider, id string) (CityForecast, error) {
+		return p.GetExtendedForecast(id)
+	})
+}
+
+func (f *FallbackProvider) GetWaveForecast(id string) (CityWaveForecast, error) {
+	return route(f, id, func(p Provider, id string) (CityWaveForecast, error) {
+		return p.GetWaveForecast(id)
+	})
+}
+
+// findCity tries the providers in order, except for the one at the skipped
+// index, which is -1 to try them all
+func (f *FallbackProvider) findCity(lookup cityLookup, skip int) (City, error) {
+	var err error = ErrCityNotFound
+
+	for i, provider := range f.Providers {
+		if i == skip {
+			continue
+		}
+
+		city, providerErr := lookup(provider.Provider)
+
+		if providerErr == nil {
+			city.ID = fmt.Sprintf("%s:%s", provider.Name, city.ID)
+			return city, nil
+		}
+
+		if !errors.Is(providerErr, ErrProviderUnavailable) && !errors.Is(providerErr, ErrCityNotFound) {
+			return City{}, providerErr
+		}
+
+		// An unavailable provider might have known the city, so report that
+		// over not found to keep the error retryable
+		if !errors.Is(err, ErrProviderUnavailable) {
+			err = providerErr
+		}
+	}
+
+	return City{}, err
+}
+
+func (f *FallbackProvider) GetWaveForecastRange(id string, days int) ([]CityWaveForecast, error) {
+	return route(f, id, func(p Provider, id string) ([]CityWaveForecast, error) {
+		return p.GetWaveForecastRange(id, days)
+	})
+}
+
+// route fetches from the provider of the city ID
+func route[T any](f *FallbackProvider, id string, fetch func(Provider, string) (T, error)) (T, error) {
+	index, providerID := f.resolve(id)
+
+	return fetch(f.Providers[index].Provider, providerID)
+}
+
+// resolve finds the provider for a prefixed city ID. IDs without a known
+// prefix belong to the first provider.
+func (f *FallbackProvider) resolve(id string) (int, string) {
+	for i, provider := range f.Providers {
+		if providerID, found := strings.CutPrefix(id, provider.Name+":"); found {
+			return i, providerID
+		}
+	}
+
+	return 0, id
+}
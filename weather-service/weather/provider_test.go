@@ -0,0 +1,284 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var unavailableError = fmt.Errorf("%w: connection refused", ErrProviderUnavailable)
+
+func TestFallbackProvider_FindCity(t *testing.T) {
+	tests := []struct {
+		name                   string
+		primaryError           error
+		secondaryError         error
+		expectedResult         City
+		expectedError          error
+		expectedSecondaryCalls []string
+	}{
+		{
+			name:                   "found by primary",
+			expectedResult:         City{ID: "primary:city-id", Name: "Test City"},
+			expectedError:          nil,
+			expectedSecondaryCalls: nil,
+		},
+		{
+			name:                   "primary unavailable",
+			primaryError:           unavailableError,
+			expectedResult:         City{ID: "secondary:city-id", Name: "Test City"},
+			expectedError:          nil,
+			expectedSecondaryCalls: []string{"test city"},
+		},
+		{
+			name:                   "primary does not know the city",
+			primaryError:           ErrCityNotFound,
+			expectedResult:         City{ID: "secondary:city-id", Name: "Test City"},
+			expectedError:          nil,
+			expectedSecondaryCalls: []string{"test city"},
+		},
+		{
+			name:                   "multiple cities on primary",
+			primaryError:           ErrMultipleCities,
+			expectedResult:         City{},
+			expectedError:          ErrMultipleCities,
+			expectedSecondaryCalls: nil,
+		},
+		{
+			name:                   "unavailable wins over not found",
+			primaryError:           unavailableError,
+			secondaryError:         ErrCityNotFound,
+			expectedResult:         City{},
+			expectedError:          ErrProviderUnavailable,
+			expectedSecondaryCalls: []string{"test city"},
+		},
+		{
+			name:                   "not found anywhere",
+			primaryError:           ErrCityNotFound,
+			secondaryError:         ErrCityNotFound,
+			expectedResult:         City{},
+			expectedError:          ErrCityNotFound,
+			expectedSecondaryCalls: []string{"test city"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &mockClient{findCityResult: City{ID: "city-id", Name: "Test City"}, findCityError: tt.primaryError}
+			secondary := &mockClient{findCityResult: City{ID: "city-id", Name: "Test City"}, findCityError: tt.secondaryError}
+
+			provider := NewFallbackProvider(
+				NamedProvider{Name: "primary", Provider: primary},
+				NamedProvider{Name: "secondary", Provider: secondary},
+			)
+
+			result, err := provider.FindCity("test city", "")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, []string{"test city"}, primary.findCityCalls)
+			assert.Equal(t, tt.expectedSecondaryCalls, secondary.findCityCalls)
+		})
+	}
+}
+
+func TestFallbackProvider_GetForecast(t *testing.T) {
+	tests := []struct {
+		name                   string
+		cityID                 string
+		primaryError           error
+		expectedResult         CityForecast
+		expectedError          error
+		expectedPrimaryCalls   []string
+		expectedSecondaryCalls []string
+	}{
+		{
+			name:                   "routed to primary",
+			cityID:                 "primary:city-id",
+			expectedResult:         CityForecast{UpdatedAt: "primary"},
+			expectedPrimaryCalls:   []string{"city-id"},
+			expectedSecondaryCalls: nil,
+		},
+		{
+			name:                   "routed to secondary",
+			cityID:                 "secondary:other-id",
+			expectedResult:         CityForecast{UpdatedAt: "secondary"},
+			expectedPrimaryCalls:   nil,
+			expectedSecondaryCalls: []string{"other-id"},
+		},
+		{
+			name:                   "id without prefix goes to primary",
+			cityID:                 "city-id",
+			expectedResult:         CityForecast{UpdatedAt: "primary"},
+			expectedPrimaryCalls:   []string{"city-id"},
+			expectedSecondaryCalls: nil,
+		},
+		{
+			name:                   "unavailable provider is reported, to relocate the city",
+			cityID:                 "primary:city-id",
+			primaryError:           unavailableError,
+			expectedResult:         CityForecast{},
+			expectedError:          ErrProviderUnavailable,
+			expectedPrimaryCalls:   []string{"city-id"},
+			expectedSecondaryCalls: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &mockClient{
+				getForecastResult: CityForecast{UpdatedAt: "primary"},
+				getForecastError:  tt.primaryError,
+			}
+
+			secondary := &mockClient{
+				getForecastResult: CityForecast{UpdatedAt: "secondary"},
+			}
+
+			provider := NewFallbackProvider(
+				NamedProvider{Name: "primary", Provider: primary},
+				NamedProvider{Name: "secondary", Provider: secondary},
+			)
+
+			if tt.expectedError != nil {
+				primary.getForecastResult = CityForecast{}
+			}
+
+			result, err := provider.GetForecast(tt.cityID)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, tt.expectedPrimaryCalls, primary.getForecastCalls)
+			assert.Equal(t, tt.expectedSecondaryCalls, secondary.getForecastCalls)
+		})
+	}
+}
+
+func TestFallbackProvider_FindCityByCoordinates(t *testing.T) {
+	primary := &mockClient{findCityResult: City{ID: "city-id", Name: "Test City"}, findCityError: unavailableError}
+	secondary := &mockClient{findCityResult: City{ID: "other-id", Name: "Test City"}}
+
+	provider := NewFallbackProvider(
+		NamedProvider{Name: "primary", Provider: primary},
+		NamedProvider{Name: "secondary", Provider: secondary},
+	)
+
+	city, err := provider.FindCityByCoordinates(-22.9, -43.2)
+	assert.Nil(t, err)
+	assert.Equal(t, City{ID: "secondary:other-id", Name: "Test City"}, city)
+
+	coordinates := Coordinates{Latitude: -22.9, Longitude: -43.2}
+	assert.Equal(t, []Coordinates{coordinates}, primary.findCityByCoordinatesCalls)
+	assert.Equal(t, []Coordinates{coordinates}, secondary.findCityByCoordinatesCalls)
+	assert.Nil(t, secondary.findCityCalls)
+}
+
+func TestFallbackProvider_SearchCities(t *testing.T) {
+	primary := &mockClient{searchCitiesError: unavailableError}
+	secondary := &mockClient{
+		searchCitiesResult: []City{{ID: "other-id", Name: "Test City", State: "XY"}},
+		findCityResult:     City{ID: "other-id", Name: "Test City", State: "XY"},
+		getForecastResult:  testWeatherForecast,
+	}
+
+	provider := NewFallbackProvider(
+		NamedProvider{Name: "primary", Provider: primary},
+		NamedProvider{Name: "secondary", Provider: secondary},
+	)
+
+	cities, err := provider.SearchCities("test city")
+	assert.Nil(t, err)
+	assert.Equal(t, []City{{ID: "secondary:other-id", Name: "Test City", State: "XY"}}, cities)
+	assert.Equal(t, []string{"test city"}, primary.searchCitiesCalls)
+	assert.Equal(t, []string{"test city"}, secondary.searchCitiesCalls)
+}
+
+func TestFallbackProvider_FindCityByID(t *testing.T) {
+	primary := &mockClient{findCityResult: City{ID: "city-id", Name: "Test City", State: "XY"}}
+	secondary := &mockClient{findCityResult: City{ID: "other-id", Name: "Test City", State: "XY"}}
+
+	provider := NewFallbackProvider(
+		NamedProvider{Name: "primary", Provider: primary},
+		NamedProvider{Name: "secondary", Provider: secondary},
+	)
+
+	city, err := provider.FindCityByID("secondary:other-id")
+	assert.Nil(t, err)
+	assert.Equal(t, City{ID: "secondary:other-id", Name: "Test City", State: "XY"}, city)
+	assert.Nil(t, primary.findCityByIDCalls)
+	assert.Equal(t, []string{"other-id"}, secondary.findCityByIDCalls)
+
+	// Only the provider of the ID is asked
+	primary.findCityError = unavailableError
+
+	_, err = provider.FindCityByID("primary:city-id")
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+	assert.Nil(t, secondary.findCityCalls)
+}
+
+func TestFallbackProvider_Relocate(t *testing.T) {
+	coordinates := &Coordinates{Latitude: -22.9, Longitude: -43.2}
+
+	tests := []struct {
+		name                           string
+		cityID                         string
+		query                          CityQuery
+		expectedResult                 City
+		expectedError                  error
+		expectedPrimaryCalls           []string
+		expectedSecondaryCalls         []string
+		expectedSecondaryByCoordinates []Coordinates
+	}{
+		{
+			name:                   "by name and state",
+			cityID:                 "primary:city-id",
+			query:                  CityQuery{ID: "primary:city-id", Name: "Test City", State: "XY"},
+			expectedResult:         City{ID: "secondary:other-id", Name: "Test City", State: "XY"},
+			expectedSecondaryCalls: []string{"Test City"},
+		},
+		{
+			name:                           "by coordinates",
+			cityID:                         "primary:city-id",
+			query:                          CityQuery{ID: "primary:city-id", Name: "Test City", Coordinates: coordinates},
+			expectedResult:                 City{ID: "secondary:other-id", Name: "Test City", State: "XY"},
+			expectedSecondaryByCoordinates: []Coordinates{*coordinates},
+		},
+		{
+			name:                 "from a later provider to an earlier one",
+			cityID:               "secondary:other-id",
+			query:                CityQuery{ID: "secondary:other-id", Name: "Test City", State: "XY"},
+			expectedResult:       City{ID: "primary:city-id", Name: "Test City", State: "XY"},
+			expectedPrimaryCalls: []string{"Test City"},
+		},
+		{
+			name:           "without a name or coordinates",
+			cityID:         "primary:city-id",
+			query:          CityQuery{ID: "primary:city-id"},
+			expectedResult: City{},
+			expectedError:  ErrCityNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &mockClient{findCityResult: City{ID: "city-id", Name: "Test City", State: "XY"}}
+			secondary := &mockClient{findCityResult: City{ID: "other-id", Name: "Test City", State: "XY"}}
+
+			provider := NewFallbackProvider(
+				NamedProvider{Name: "primary", Provider: primary},
+				NamedProvider{Name: "secondary", Provider: secondary},
+			)
+
+			result, err := provider.Relocate(tt.cityID, tt.query)
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedResult, result)
+			assert.Equal(t, tt.expectedPrimaryCalls, primary.findCityCalls)
+			assert.Equal(t, tt.expectedSecondaryCalls, secondary.findCityCalls)
+			assert.Equal(t, tt.expectedSecondaryByCoordinates, secondary.findCityByCoordinatesCalls)
+			assert.Nil(t, primary.findCityByCoordinatesCalls)
+		})
+	}
+}
@@ -4,36 +4,84 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/fgouvea/weather/weather-service/user"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultForecastDays = 4
+	MaxForecastDays     = 7
+	MaxWaveDays         = 6
+)
+
+// Forecast styles. The simple one has the temperatures and wave heights,
+// while the detailed one adds the condition, UV index, wave direction and
+// wind.
+const (
+	StyleSimple   = "simple"
+	StyleDetailed = "detailed"
 )
 
 type Service struct {
-	UserFinder        UserFinder
-	CityFinder        CityFinder
-	WeatherForecaster WeatherForecaster
-	WaveForecaster    WaveForecaster
-	Notifier          Notifier
+	UserFinder         UserFinder
+	CityFinder         CityFinder
+	CityRelocator      CityRelocator
+	CitySearcher       CitySearcher
+	WeatherForecaster  WeatherForecaster
+	ExtendedForecaster ExtendedForecaster
+	WaveForecaster     WaveForecaster
+	ConditionsFinder   CityConditionsFinder
+	Renderer           Renderer
+	Notifier           Notifier
+	Logger             *zap.Logger
 }
 
 func NewService(
 	userFinder UserFinder,
 	cityFinder CityFinder,
+	cityRelocator CityRelocator,
+	citySearcher CitySearcher,
 	weatherForecaster WeatherForecaster,
+	extendedForecaster ExtendedForecaster,
 	waveForecaster WaveForecaster,
+	conditionsFinder CityConditionsFinder,
+	renderer Renderer,
 	notifier Notifier,
+	logger *zap.Logger,
 ) *Service {
 	return &Service{
-		UserFinder:        userFinder,
-		CityFinder:        cityFinder,
-		WeatherForecaster: weatherForecaster,
-		WaveForecaster:    waveForecaster,
-		Notifier:          notifier,
+		UserFinder:         userFinder,
+		CityFinder:         cityFinder,
+		CityRelocator:      cityRelocator,
+		CitySearcher:       citySearcher,
+		WeatherForecaster:  weatherForecaster,
+		ExtendedForecaster: extendedForecaster,
+		WaveForecaster:     waveForecaster,
+		ConditionsFinder:   conditionsFinder,
+		Renderer:           renderer,
+		Notifier:           notifier,
+		Logger:             logger,
+	}
+}
+
+// ValidateForecastDays checks a forecast horizon, where 0 stands for
+// DefaultForecastDays, so it is accepted along with 1 to MaxForecastDays.
+func ValidateForecastDays(days int) error {
+	if days < 0 || days > MaxForecastDays {
+		return fmt.Errorf("%w: %d, must be between 0 and %d, where 0 means the default of %d", ErrInvalidForecastDays, days, MaxForecastDays, DefaultForecastDays)
 	}
+
+	return nil
 }
 
-func (s *Service) getUserAndCity(userID, cityName string) (user.User, City, error) {
+func (s *Service) getUserAndCity(userID string, query CityQuery) (user.User, City, error) {
+	err := query.Validate()
+
+	if err != nil {
+		return user.User{}, City{}, err
+	}
+
 	userEntry, err := s.UserFinder.FindUser(userID)
 
 	if err != nil {
@@ -44,82 +92,354 @@ func (s *Service) getUserAndCity(userID, cityName string) (user.User, City, erro
 		return user.User{}, City{}, fmt.Errorf("unexpected error fetching user: %w", err)
 	}
 
-	city, err := s.CityFinder.FindCity(cityName)
+	city, err := s.getCity(query)
+
+	if err != nil {
+		return user.User{}, City{}, err
+	}
+
+	return userEntry, city, nil
+}
+
+func (s *Service) getCity(query CityQuery) (City, error) {
+	city, err := s.findCity(query)
 
 	if err != nil {
 		if errors.Is(err, ErrCityNotFound) || errors.Is(err, ErrMultipleCities) {
-			return user.User{}, City{}, err
+			return City{}, err
 		}
 
-		return user.User{}, City{}, fmt.Errorf("unexpected error fetching city: %w", err)
+		return City{}, fmt.Errorf("unexpected error fetching city: %w", err)
 	}
 
-	return userEntry, city, nil
+	return city, nil
+}
+
+func (s *Service) findCity(query CityQuery) (City, error) {
+	if query.ID != "" {
+		city, err := s.CityFinder.FindCityByID(query.ID)
+
+		if !errors.Is(err, ErrProviderUnavailable) {
+			return city, err
+		}
+
+		relocated, relocateErr := s.CityRelocator.Relocate(query.ID, query)
+
+		if relocateErr != nil {
+			return City{}, err
+		}
+
+		return relocated, nil
+	}
+
+	if query.Coordinates != nil {
+		return s.CityFinder.FindCityByCoordinates(query.Coordinates.Latitude, query.Coordinates.Longitude)
+	}
+
+	return s.CityFinder.FindCity(query.Name, query.State)
+}
+
+func (s *Service) SearchCities(name string) ([]City, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidCity)
+	}
+
+	cities, err := s.CitySearcher.SearchCities(name)
+
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error searching cities: %w", err)
+	}
+
+	return cities, nil
+}
+
+// Validate checks that both the user and the city exist, returning the city
+// found, so it does not have to be looked up by name again.
+func (s *Service) Validate(userID string, query CityQuery) (City, error) {
+	_, city, err := s.getUserAndCity(userID, query)
+	return city, err
+}
+
+// ValidateForecastStyle checks a forecast style, where empty stands for the
+// style chosen by the user.
+func ValidateForecastStyle(style string) error {
+	if style != "" && style != StyleSimple && style != StyleDetailed {
+		return fmt.Errorf("%w: %s, must be %s or %s", ErrInvalidForecastStyle, style, StyleSimple, StyleDetailed)
+	}
+
+	return nil
+}
+
+// NotifyUserByCity notifies about a city already found by Validate, by its
+// ID, which, unlike names, is never ambiguous, in the style chosen by the
+// user. The name and state or coordinates stored with the ID find the city on
+// another provider when the one of the ID is unavailable.
+func (s *Service) NotifyUserByCity(userID string, city CityQuery, days int) error {
+	if city.ID == "" {
+		return fmt.Errorf("%w: city id is required", ErrInvalidCity)
+	}
+
+	return s.NotifyUser(userID, city, days, "", "")
 }
 
-func (s *Service) Validate(userID, cityName string) error {
-	_, _, err := s.getUserAndCity(userID, cityName)
-	return err
+// NotifyUserByCityID notifies about a city known only by its ID, so there is
+// no other provider to fall back to when the one of the ID is unavailable.
+func (s *Service) NotifyUserByCityID(userID, cityID string, days int) error {
+	return s.NotifyUserByCity(userID, CityQuery{ID: cityID}, days)
 }
 
-func (s *Service) NotifyUser(userID, cityName string) error {
-	userEntry, city, err := s.getUserAndCity(userID, cityName)
+// NotifyUser sends the forecast of a city to a user. An empty style uses the
+// one chosen by the user, and then StyleSimple, and an empty channel every
+// channel enabled by the user.
+func (s *Service) NotifyUser(userID string, query CityQuery, days int, style, channel string) error {
+	err := ValidateForecastDays(days)
+
+	if err != nil {
+		return err
+	}
+
+	err = ValidateForecastStyle(style)
+
+	if err != nil {
+		return err
+	}
+
+	userEntry, city, err := s.getUserAndCity(userID, query)
+
+	if err != nil {
+		return err
+	}
+
+	weatherForecast, waveForecasts, err := s.GetForecasts(foundCity(city, query), days)
 
 	if err != nil {
 		return err
 	}
 
-	weatherForecast, err := s.WeatherForecaster.GetForecast(city.ID)
+	conditions, err := s.ConditionsFinder.GetCityConditions(city.ID)
+
+	// The forecast is worth sending without the current conditions
+	if err != nil && !errors.Is(err, ErrStationNotFound) {
+		s.Logger.Warn("error fetching current conditions", zap.String("cityID", city.ID), zap.Error(err))
+	}
+
+	if style == "" {
+		style = userEntry.ForecastStyle
+	}
+
+	return s.sendNotification(userEntry, city, conditions, weatherForecast, waveForecasts, style == StyleDetailed, channel)
+}
+
+// FindForecast looks a city up and fetches its weather forecast for the
+// given number of days, where 0 stands for DefaultForecastDays.
+func (s *Service) FindForecast(query CityQuery, days int) (City, CityForecast, error) {
+	err := ValidateForecastDays(days)
+
+	if err != nil {
+		return City{}, CityForecast{}, err
+	}
+
+	err = query.Validate()
 
 	if err != nil {
-		return fmt.Errorf("unexpected error fetching weather forecast: %w", err)
+		return City{}, CityForecast{}, err
 	}
 
-	waveForecast, err := s.WaveForecaster.GetWaveForecast(city.ID)
+	city, err := s.getCity(query)
+
+	if err != nil {
+		return City{}, CityForecast{}, err
+	}
+
+	forecast, err := s.GetForecast(foundCity(city, query), days)
+
+	if err != nil {
+		return City{}, CityForecast{}, err
+	}
+
+	return city, forecast, nil
+}
+
+// FindWaveForecast looks a city up and fetches its wave forecast for a single
+// day, counted from today, which is 0. Cities away from the sea fail with
+// ErrWavesNotFound.
+func (s *Service) FindWaveForecast(query CityQuery, day int) (City, CityWaveForecast, error) {
+	if day < 0 || day >= MaxWaveDays {
+		return City{}, CityWaveForecast{}, fmt.Errorf("%w: day %d, must be between 0 and %d", ErrInvalidForecastDays, day, MaxWaveDays-1)
+	}
+
+	err := query.Validate()
+
+	if err != nil {
+		return City{}, CityWaveForecast{}, err
+	}
+
+	city, err := s.getCity(query)
+
+	if err != nil {
+		return City{}, CityWaveForecast{}, err
+	}
+
+	waveForecasts, err := withFallback(s, foundCity(city, query), func(cityID string) ([]CityWaveForecast, error) {
+		return s.WaveForecaster.GetWaveForecastRange(cityID, day+1)
+	})
+
+	if errors.Is(err, ErrCityNotFound) {
+		return City{}, CityWaveForecast{}, fmt.Errorf("%w: %s", ErrWavesNotFound, city.ID)
+	}
+
+	if err != nil {
+		return City{}, CityWaveForecast{}, fmt.Errorf("unexpected error fetching wave forecast: %w", err)
+	}
+
+	if len(waveForecasts) <= day {
+		return City{}, CityWaveForecast{}, fmt.Errorf("%w: %s on day %d", ErrWavesNotFound, city.ID, day)
+	}
+
+	return city, waveForecasts[day], nil
+}
+
+// GetForecasts fetches the weather and wave forecasts of a city for the given
+// number of days, where 0 stands for DefaultForecastDays. Cities away from the
+// sea have no wave forecasts.
+func (s *Service) GetForecasts(city CityQuery, days int) (CityForecast, []CityWaveForecast, error) {
+	weatherForecast, err := s.GetForecast(city, days)
+
+	if err != nil {
+		return CityForecast{}, nil, err
+	}
+
+	if days == 0 {
+		days = DefaultForecastDays
+	}
+
+	waveForecasts, err := withFallback(s, city, func(cityID string) ([]CityWaveForecast, error) {
+		return s.WaveForecaster.GetWaveForecastRange(cityID, min(days, MaxWaveDays))
+	})
 
 	if err != nil && !errors.Is(err, ErrCityNotFound) {
-		return fmt.Errorf("unexpected error fetching wave forecast: %w", err)
+		return CityForecast{}, nil, fmt.Errorf("unexpected error fetching wave forecast: %w", err)
 	}
 
-	return s.sendNotification(userEntry, city, weatherForecast, waveForecast)
+	return weatherForecast, waveForecasts, nil
+}
+
+// GetForecast fetches the weather forecast of a city, without the waves, for
+// the given number of days, where 0 stands for DefaultForecastDays.
+func (s *Service) GetForecast(city CityQuery, days int) (CityForecast, error) {
+	err := ValidateForecastDays(days)
+
+	if err != nil {
+		return CityForecast{}, err
+	}
+
+	if days == 0 {
+		days = DefaultForecastDays
+	}
+
+	return withFallback(s, city, func(cityID string) (CityForecast, error) {
+		return s.getForecast(cityID, days)
+	})
+}
+
+// withFallback fetches by the ID of the city and, when its provider is
+// unavailable, from the provider the city is found on instead
+func withFallback[T any](s *Service, city CityQuery, fetch func(cityID string) (T, error)) (T, error) {
+	result, err := fetch(city.ID)
+
+	if !errors.Is(err, ErrProviderUnavailable) {
+		return result, err
+	}
+
+	relocated, relocateErr := s.CityRelocator.Relocate(city.ID, city)
+
+	if relocateErr != nil {
+		return result, err
+	}
+
+	return fetch(relocated.ID)
+}
+
+// foundCity describes a city found for the query, keeping the coordinates it
+// was asked by
+func foundCity(city City, query CityQuery) CityQuery {
+	return CityQuery{ID: city.ID, Name: city.Name, State: city.State, Coordinates: query.Coordinates}
+}
+
+// getForecast fetches the forecast for the given number of days, completing
+// the regular forecast with the extended one when it does not reach that far.
+func (s *Service) getForecast(cityID string, days int) (CityForecast, error) {
+	forecast, err := s.WeatherForecaster.GetForecast(cityID)
+
+	if err != nil {
+		return CityForecast{}, fmt.Errorf("unexpected error fetching weather forecast: %w", err)
+	}
+
+	if len(forecast.Forecast) < days {
+		extended, err := s.ExtendedForecaster.GetExtendedForecast(cityID)
+
+		if err != nil {
+			return CityForecast{}, fmt.Errorf("unexpected error fetching extended weather forecast: %w", err)
+		}
+
+		lastDate := ""
+
+		if len(forecast.Forecast) > 0 {
+			lastDate = forecast.Forecast[len(forecast.Forecast)-1].Date
+		}
+
+		// Dates are in ISO format, so they compare as strings
+		for _, day := range extended.Forecast {
+			if day.Date > lastDate {
+				forecast.Forecast = append(forecast.Forecast, day)
+			}
+		}
+	}
+
+	if len(forecast.Forecast) > days {
+		forecast.Forecast = forecast.Forecast[:days]
+	}
+
+	return forecast, nil
 }
 
 func (s *Service) sendNotification(
 	userEntry user.User,
 	city City,
+	conditions CurrentConditions,
 	weatherForecast CityForecast,
-	waveForecast CityWaveForecast,
+	waveForecasts []CityWaveForecast,
+	detailed bool,
+	channel string,
 ) error {
-	var buffer strings.Builder
-
-	buffer.WriteString(fmt.Sprintf("%s, aqui está a previsão do tempo para %s\n\n", userEntry.Name, city.Name))
-
-	for i, forecast := range weatherForecast.Forecast {
-		date := formatDate(forecast.Date)
-
-		buffer.WriteString(fmt.Sprintf("%s: %d - %d", date, forecast.MinTemperature, forecast.MaxTemperature))
-		if i < (len(weatherForecast.Forecast) - 1) {
-			buffer.WriteString("\n")
-		}
+	data := ForecastMessage{
+		UserName: userEntry.Name,
+		City:     city,
+		Forecast: weatherForecast.Forecast,
+		Waves:    waveForecasts,
+		Detailed: detailed,
 	}
 
-	if waveForecast != (CityWaveForecast{}) {
-		date := formatDate(waveForecast.Date)
-		buffer.WriteString("\n\n")
-		buffer.WriteString(fmt.Sprintf("Ondas para o dia %s:\n", date))
-		buffer.WriteString(fmt.Sprintf("Manhã: %s %.2fm\n", waveForecast.Morning.Swell, waveForecast.Morning.Height))
-		buffer.WriteString(fmt.Sprintf("Tarde: %s %.2fm\n", waveForecast.Afternoon.Swell, waveForecast.Afternoon.Height))
-		buffer.WriteString(fmt.Sprintf("Noite: %s %.2fm", waveForecast.Evening.Swell, waveForecast.Evening.Height))
+	if conditions != (CurrentConditions{}) {
+		data.Conditions = &conditions
 	}
 
-	content := buffer.String()
+	content, err := s.Renderer.Render(userEntry.Locale, "forecast", data)
 
-	s.Notifier.Notify(userEntry.ID, content)
+	if err != nil {
+		return fmt.Errorf("unexpected error rendering notification: %w", err)
+	}
 
-	return nil
-}
+	s.Notifier.Notify(userEntry.ID, Notification{
+		Content: content,
+		Payload: Payload{
+			Type:       "forecast",
+			City:       city,
+			Conditions: data.Conditions,
+			Days:       NewPayloadDays(weatherForecast.Forecast, waveForecasts),
+		},
+		Channel: channel,
+	})
 
-func formatDate(date string) string {
-	d, _ := time.Parse("2006-01-02", date)
-	return d.Format("02/01/2006")
+	return nil
 }
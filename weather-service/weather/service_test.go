@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/fgouvea/weather/weather-service/message"
 	"github.com/fgouvea/weather/weather-service/user"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
 )
 
+var testRenderer, _ = message.NewEmbeddedRenderer()
+var testLogger, _ = zap.NewDevelopment()
+
 func TestService_NotifyUser(t *testing.T) {
 	tests := []struct {
 		name                         string
@@ -18,7 +23,7 @@ func TestService_NotifyUser(t *testing.T) {
 		cityError                    error
 		weatherResult                CityForecast
 		weatherError                 error
-		waveResult                   CityWaveForecast
+		waveResult                   []CityWaveForecast
 		waveError                    error
 		notifierError                error
 		expectedError                error
@@ -33,7 +38,7 @@ func TestService_NotifyUser(t *testing.T) {
 			userResult:                   testUser,
 			cityResult:                   testCity,
 			weatherResult:                testWeatherForecast,
-			waveResult:                   testWavesForecast,
+			waveResult:                   []CityWaveForecast{testWavesForecast},
 			expectedError:                nil,
 			expectedFindUserCalls:        []string{"user-id"},
 			expectedFindCityCalls:        []string{"test city"},
@@ -41,6 +46,19 @@ func TestService_NotifyUser(t *testing.T) {
 			expectedGetWaveForecastCalls: []string{"city-id"},
 			expectedNotifications:        []string{"Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n09/02/2025: 3 - 33\n10/02/2025: 4 - 34\n\nOndas para o dia 07/02/2025:\nManhã: Fraca 0.10m\nTarde: Moderada 0.23m\nNoite: Forte 0.46m"},
 		},
+		{
+			name:                         "success with several days of waves",
+			userResult:                   testUser,
+			cityResult:                   testCity,
+			weatherResult:                testWeatherForecast,
+			waveResult:                   []CityWaveForecast{testWavesForecast, {Date: "2025-02-08", Morning: WaveForecast{Swell: "Fraca", Height: 0.2}, Afternoon: WaveForecast{Swell: "Fraca", Height: 0.3}, Evening: WaveForecast{Swell: "Moderada", Height: 1}}},
+			expectedError:                nil,
+			expectedFindUserCalls:        []string{"user-id"},
+			expectedFindCityCalls:        []string{"test city"},
+			expectedGetForecastCalls:     []string{"city-id"},
+			expectedGetWaveForecastCalls: []string{"city-id"},
+			expectedNotifications:        []string{"Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n09/02/2025: 3 - 33\n10/02/2025: 4 - 34\n\nOndas para o dia 07/02/2025:\nManhã: Fraca 0.10m\nTarde: Moderada 0.23m\nNoite: Forte 0.46m\n\nOndas para o dia 08/02/2025:\nManhã: Fraca 0.20m\nTarde: Fraca 0.30m\nNoite: Moderada 1.00m"},
+		},
 		{
 			name:                         "success without waves",
 			userResult:                   testUser,
@@ -135,20 +153,22 @@ func TestService_NotifyUser(t *testing.T) {
 				getForecastResult: tt.weatherResult,
 				getForecastError:  tt.weatherError,
 
-				getWaveForecastResult: tt.waveResult,
-				getWaveForecastError:  tt.waveError,
+				getWaveForecastRangeResult: tt.waveResult,
+				getWaveForecastRangeError:  tt.waveError,
+
+				getCityConditionsError: ErrStationNotFound,
 			}
 
-			service := NewService(mock, mock, mock, mock, mock)
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
 
-			err := service.NotifyUser("user-id", "test city")
+			err := service.NotifyUser("user-id", CityQuery{Name: "test city"}, 0, "", "")
 
 			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
 
 			assert.Equal(t, tt.expectedFindUserCalls, mock.findUserCalls)
 			assert.Equal(t, tt.expectedFindCityCalls, mock.findCityCalls)
 			assert.Equal(t, tt.expectedGetForecastCalls, mock.getForecastCalls)
-			assert.Equal(t, tt.expectedGetWaveForecastCalls, mock.getWaveForecastCalls)
+			assert.Equal(t, tt.expectedGetWaveForecastCalls, mock.getWaveForecastRangeCalls)
 			assert.Equal(t, tt.expectedNotifications, mock.notifyCallsContent)
 
 			for i, _ := range tt.expectedNotifications {
@@ -157,3 +177,741 @@ func TestService_NotifyUser(t *testing.T) {
 		})
 	}
 }
+
+func TestService_NotifyUser_Days(t *testing.T) {
+	extendedForecast := CityForecast{
+		Forecast: []Forecast{
+			Forecast{Date: "2025-02-10", MinTemperature: 9, MaxTemperature: 39},
+			Forecast{Date: "2025-02-11", MinTemperature: 5, MaxTemperature: 35},
+			Forecast{Date: "2025-02-12", MinTemperature: 6, MaxTemperature: 36},
+			Forecast{Date: "2025-02-13", MinTemperature: 7, MaxTemperature: 37},
+		},
+	}
+
+	tests := []struct {
+		name                             string
+		days                             int
+		extendedError                    error
+		expectedError                    error
+		expectedGetExtendedForecastCalls []string
+		expectedWaveDays                 []int
+		expectedNotifications            []string
+	}{
+		{
+			name:                  "fewer days than the forecast",
+			days:                  2,
+			expectedWaveDays:      []int{2},
+			expectedNotifications: []string{"Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32"},
+		},
+		{
+			name:                             "completed with extended forecast",
+			days:                             6,
+			expectedGetExtendedForecastCalls: []string{"city-id"},
+			expectedWaveDays:                 []int{6},
+			expectedNotifications:            []string{"Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n09/02/2025: 3 - 33\n10/02/2025: 4 - 34\n11/02/2025: 5 - 35\n12/02/2025: 6 - 36"},
+		},
+		{
+			name:                             "waves capped at the longest range",
+			days:                             7,
+			expectedGetExtendedForecastCalls: []string{"city-id"},
+			expectedWaveDays:                 []int{MaxWaveDays},
+			expectedNotifications:            []string{"Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n09/02/2025: 3 - 33\n10/02/2025: 4 - 34\n11/02/2025: 5 - 35\n12/02/2025: 6 - 36\n13/02/2025: 7 - 37"},
+		},
+		{
+			name:                             "error getting extended forecast",
+			days:                             7,
+			extendedError:                    runtimeError,
+			expectedError:                    runtimeError,
+			expectedGetExtendedForecastCalls: []string{"city-id"},
+		},
+		{
+			name:          "too many days",
+			days:          MaxForecastDays + 1,
+			expectedError: ErrInvalidForecastDays,
+		},
+		{
+			name:          "negative days",
+			days:          -1,
+			expectedError: ErrInvalidForecastDays,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				findUserResult:            testUser,
+				findCityResult:            testCity,
+				getForecastResult:         testWeatherForecast,
+				getExtendedForecastResult: extendedForecast,
+				getExtendedForecastError:  tt.extendedError,
+				getWaveForecastRangeError: ErrCityNotFound,
+				getCityConditionsError:    ErrStationNotFound,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			err := service.NotifyUser("user-id", CityQuery{Name: "test city"}, tt.days, "", "")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedGetExtendedForecastCalls, mock.getExtendedForecastCalls)
+			assert.Equal(t, tt.expectedWaveDays, mock.getWaveForecastRangeDays)
+			assert.Equal(t, tt.expectedNotifications, mock.notifyCallsContent)
+		})
+	}
+}
+
+func TestService_NotifyUser_CurrentConditions(t *testing.T) {
+	conditions := CurrentConditions{
+		Station:     "SBRJ",
+		Weather:     "Predomínio de Sol",
+		Temperature: 28,
+		Pressure:    1012,
+		Humidity:    70,
+		WindSpeed:   15,
+	}
+
+	tests := []struct {
+		name                  string
+		conditionsResult      CurrentConditions
+		conditionsError       error
+		expectedError         error
+		expectedNotifications []string
+	}{
+		{
+			name:                  "with current conditions",
+			conditionsResult:      conditions,
+			expectedNotifications: []string{"Fulano, aqui está a previsão do tempo para Test City\n\nAgora: Predomínio de Sol, 28°C, umidade 70%, vento 15km/h, pressão 1012hPa\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n09/02/2025: 3 - 33\n10/02/2025: 4 - 34"},
+		},
+		{
+			name:                  "city without station",
+			conditionsError:       ErrStationNotFound,
+			expectedNotifications: []string{"Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n09/02/2025: 3 - 33\n10/02/2025: 4 - 34"},
+		},
+		{
+			name:                  "unexpected error getting current conditions",
+			conditionsError:       runtimeError,
+			expectedNotifications: []string{"Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n09/02/2025: 3 - 33\n10/02/2025: 4 - 34"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				findUserResult:            testUser,
+				findCityResult:            testCity,
+				getForecastResult:         testWeatherForecast,
+				getWaveForecastRangeError: ErrCityNotFound,
+				getCityConditionsResult:   tt.conditionsResult,
+				getCityConditionsError:    tt.conditionsError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			err := service.NotifyUser("user-id", CityQuery{Name: "test city"}, 0, "", "")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, []string{"city-id"}, mock.getCityConditionsCalls)
+			assert.Equal(t, tt.expectedNotifications, mock.notifyCallsContent)
+		})
+	}
+}
+
+func TestService_NotifyUser_Locale(t *testing.T) {
+	conditions := CurrentConditions{Weather: "Predomínio de Sol", Temperature: 28, Humidity: 70, WindSpeed: 15, Pressure: 1012}
+
+	tests := []struct {
+		name                  string
+		locale                string
+		expectedNotifications []string
+	}{
+		{
+			name:                  "default locale",
+			locale:                "",
+			expectedNotifications: []string{"Fulano, aqui está a previsão do tempo para Test City\n\nAgora: Predomínio de Sol, 28°C, umidade 70%, vento 15km/h, pressão 1012hPa\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n\nOndas para o dia 07/02/2025:\nManhã: Fraca 0.10m\nTarde: Moderada 0.23m\nNoite: Forte 0.46m"},
+		},
+		{
+			name:                  "english",
+			locale:                "en-US",
+			expectedNotifications: []string{"Fulano, here is the weather forecast for Test City\n\nNow: Mostly Sunny, 28°C, humidity 70%, wind 15km/h, pressure 1012hPa\n\n02/07/2025: 1 - 31\n02/08/2025: 2 - 32\n\nWaves on 02/07/2025:\nMorning: Weak 0.10m\nAfternoon: Moderate 0.23m\nEvening: Strong 0.46m"},
+		},
+		{
+			name:                  "spanish",
+			locale:                "es",
+			expectedNotifications: []string{"Fulano, aquí está el pronóstico del tiempo para Test City\n\nAhora: Mayormente Soleado, 28°C, humedad 70%, viento 15km/h, presión 1012hPa\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n\nOlas para el día 07/02/2025:\nMañana: Débil 0.10m\nTarde: Moderada 0.23m\nNoche: Fuerte 0.46m"},
+		},
+		{
+			name:                  "unknown locale",
+			locale:                "fr",
+			expectedNotifications: []string{"Fulano, aqui está a previsão do tempo para Test City\n\nAgora: Predomínio de Sol, 28°C, umidade 70%, vento 15km/h, pressão 1012hPa\n\n07/02/2025: 1 - 31\n08/02/2025: 2 - 32\n\nOndas para o dia 07/02/2025:\nManhã: Fraca 0.10m\nTarde: Moderada 0.23m\nNoite: Forte 0.46m"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localeUser := testUser
+			localeUser.Locale = tt.locale
+
+			mock := &mockClient{
+				findUserResult:             localeUser,
+				findCityResult:             testCity,
+				getForecastResult:          testWeatherForecast,
+				getWaveForecastRangeResult: []CityWaveForecast{testWavesForecast},
+				getCityConditionsResult:    conditions,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			err := service.NotifyUser("user-id", CityQuery{Name: "test city"}, 2, "", "")
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedNotifications, mock.notifyCallsContent)
+		})
+	}
+}
+
+func TestService_NotifyUser_Style(t *testing.T) {
+	forecast := CityForecast{
+		Forecast: []Forecast{
+			{Date: "2025-02-07", Weather: "Chuva", MinTemperature: 21, MaxTemperature: 30, IUV: 7},
+			{Date: "2025-02-08", Weather: "Céu Claro", MinTemperature: 22, MaxTemperature: 34, IUV: 12},
+		},
+	}
+
+	waves := CityWaveForecast{
+		Date:      "2025-02-07",
+		Morning:   WaveForecast{Swell: "Fraca", Height: 0.5, WaveDirection: "E", Wind: 5, WindDirection: "ENE"},
+		Afternoon: WaveForecast{Swell: "Moderada", Height: 1, WaveDirection: "SE", Wind: 7.5, WindDirection: "S"},
+		Evening:   WaveForecast{Swell: "Forte", Height: 2, WaveDirection: "S"},
+	}
+
+	simple := "Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: 21 - 30\n08/02/2025: 22 - 34\n\nOndas para o dia 07/02/2025:\nManhã: Fraca 0.50m\nTarde: Moderada 1.00m\nNoite: Forte 2.00m"
+	detailed := "Fulano, aqui está a previsão do tempo para Test City\n\n07/02/2025: Chuva, 21 - 30°C, IUV 7 (alto)\n08/02/2025: Céu Claro, 22 - 34°C, IUV 12 (extremo)\n\nOndas para o dia 07/02/2025:\nManhã: Fraca 0.50m, direção E, vento 5.0 ENE\nTarde: Moderada 1.00m, direção SE, vento 7.5 S\nNoite: Forte 2.00m, direção S"
+
+	tests := []struct {
+		name                  string
+		userStyle             string
+		style                 string
+		locale                string
+		expectedError         error
+		expectedNotifications []string
+	}{
+		{
+			name:                  "simple by default",
+			expectedNotifications: []string{simple},
+		},
+		{
+			name:                  "chosen by the user",
+			userStyle:             StyleDetailed,
+			expectedNotifications: []string{detailed},
+		},
+		{
+			name:                  "chosen in the request",
+			style:                 StyleDetailed,
+			expectedNotifications: []string{detailed},
+		},
+		{
+			name:                  "request overrides the user",
+			userStyle:             StyleDetailed,
+			style:                 StyleSimple,
+			expectedNotifications: []string{simple},
+		},
+		{
+			name:                  "in the locale of the user",
+			style:                 StyleDetailed,
+			locale:                "en",
+			expectedNotifications: []string{"Fulano, here is the weather forecast for Test City\n\n02/07/2025: Rain, 21 - 30°C, UV index 7 (high)\n02/08/2025: Clear Sky, 22 - 34°C, UV index 12 (extreme)\n\nWaves on 02/07/2025:\nMorning: Weak 0.50m, direction E, wind 5.0 ENE\nAfternoon: Moderate 1.00m, direction SE, wind 7.5 S\nEvening: Strong 2.00m, direction S"},
+		},
+		{
+			name:          "invalid style",
+			style:         "verbose",
+			expectedError: ErrInvalidForecastStyle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			styleUser := testUser
+			styleUser.ForecastStyle = tt.userStyle
+			styleUser.Locale = tt.locale
+
+			mock := &mockClient{
+				findUserResult:             styleUser,
+				findCityResult:             testCity,
+				getForecastResult:          forecast,
+				getWaveForecastRangeResult: []CityWaveForecast{waves},
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			err := service.NotifyUser("user-id", CityQuery{Name: "test city"}, 2, tt.style, "")
+
+			assert.True(t, errors.Is(err, tt.expectedError), fmt.Sprintf("Expected: %s / Actual: %s", tt.expectedError, err))
+			assert.Equal(t, tt.expectedNotifications, mock.notifyCallsContent)
+		})
+	}
+}
+
+func TestForecast_UVRisk(t *testing.T) {
+	tests := []struct {
+		iuv            float64
+		expectedResult string
+	}{
+		{iuv: 0, expectedResult: "baixo"},
+		{iuv: 2.9, expectedResult: "baixo"},
+		{iuv: 3, expectedResult: "moderado"},
+		{iuv: 5, expectedResult: "moderado"},
+		{iuv: 6, expectedResult: "alto"},
+		{iuv: 7.5, expectedResult: "alto"},
+		{iuv: 8, expectedResult: "muito alto"},
+		{iuv: 10, expectedResult: "muito alto"},
+		{iuv: 11, expectedResult: "extremo"},
+		{iuv: 14, expectedResult: "extremo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.iuv), func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, Forecast{IUV: tt.iuv}.UVRisk())
+		})
+	}
+}
+
+func TestService_NotifyUser_Payload(t *testing.T) {
+	mock := &mockClient{
+		findUserResult:             testUser,
+		findCityResult:             testCity,
+		getForecastResult:          testWeatherForecast,
+		getWaveForecastRangeResult: []CityWaveForecast{testWavesForecast},
+	}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+	err := service.NotifyUser("user-id", CityQuery{Name: "test city"}, 2, "", "telegram")
+
+	assert.Nil(t, err)
+	assert.Len(t, mock.notifyCallsNotification, 1)
+
+	notification := mock.notifyCallsNotification[0]
+
+	assert.Equal(t, "telegram", notification.Channel)
+
+	assert.Equal(t, Payload{
+		Type: "forecast",
+		City: testCity,
+		Days: []PayloadDay{
+			{Forecast: testWeatherForecast.Forecast[0], Waves: &testWavesForecast},
+			{Forecast: testWeatherForecast.Forecast[1]},
+		},
+	}, notification.Payload)
+	assert.Contains(t, notification.Markdown, "*07/02/2025*: 1 - 31")
+	assert.Contains(t, notification.HTML, "<li><b>07/02/2025</b>: 1 - 31</li>")
+}
+
+func TestStationConditions_GetCityConditions(t *testing.T) {
+	mock := &mockClient{getCityConditionsResult: CurrentConditions{Station: "SBRJ"}}
+
+	conditions := NewStationConditions(mock, map[string]string{"cptec:241": "SBRJ"})
+
+	result, err := conditions.GetCityConditions("cptec:241")
+	assert.Nil(t, err)
+	assert.Equal(t, "SBRJ", result.Station)
+
+	_, err = conditions.GetCityConditions("cptec:244")
+	assert.ErrorIs(t, err, ErrStationNotFound)
+
+	assert.Equal(t, []string{"SBRJ"}, mock.getCityConditionsCalls)
+}
+
+func TestService_NotifyUser_CityQuery(t *testing.T) {
+	tests := []struct {
+		name                          string
+		query                         CityQuery
+		expectedError                 error
+		expectedFindCityCalls         []string
+		expectedFindCityStates        []string
+		expectedFindCityByCoordinates []Coordinates
+		expectedFindCityByID          []string
+	}{
+		{
+			name:                   "by name",
+			query:                  CityQuery{Name: "test city"},
+			expectedFindCityCalls:  []string{"test city"},
+			expectedFindCityStates: []string{""},
+		},
+		{
+			name:                   "by name and state",
+			query:                  CityQuery{Name: "test city", State: "SP"},
+			expectedFindCityCalls:  []string{"test city"},
+			expectedFindCityStates: []string{"SP"},
+		},
+		{
+			name:                 "by id",
+			query:                CityQuery{ID: "cptec:244", Name: "ignored", Coordinates: &Coordinates{Latitude: -22.9, Longitude: -43.2}},
+			expectedFindCityByID: []string{"cptec:244"},
+		},
+		{
+			name:                          "by coordinates",
+			query:                         CityQuery{Name: "ignored", Coordinates: &Coordinates{Latitude: -22.9, Longitude: -43.2}},
+			expectedFindCityByCoordinates: []Coordinates{{Latitude: -22.9, Longitude: -43.2}},
+		},
+		{
+			name:          "coordinates out of range",
+			query:         CityQuery{Coordinates: &Coordinates{Latitude: -122.9, Longitude: -43.2}},
+			expectedError: ErrInvalidCity,
+		},
+		{
+			name:          "empty query",
+			query:         CityQuery{},
+			expectedError: ErrInvalidCity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				findUserResult:            testUser,
+				findCityResult:            testCity,
+				getForecastResult:         testWeatherForecast,
+				getWaveForecastRangeError: ErrCityNotFound,
+				getCityConditionsError:    ErrStationNotFound,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			err := service.NotifyUser("user-id", tt.query, 0, "", "")
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFindCityCalls, mock.findCityCalls)
+			assert.Equal(t, tt.expectedFindCityStates, mock.findCityStates)
+			assert.Equal(t, tt.expectedFindCityByCoordinates, mock.findCityByCoordinatesCalls)
+			assert.Equal(t, tt.expectedFindCityByID, mock.findCityByIDCalls)
+		})
+	}
+}
+
+func TestService_SearchCities(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		searchError    error
+		expectedResult []City
+		expectedError  error
+		expectedCalls  []string
+	}{
+		{
+			name:           "success",
+			query:          "sao paulo",
+			expectedResult: []City{testCity},
+			expectedCalls:  []string{"sao paulo"},
+		},
+		{
+			name:          "empty query",
+			query:         "  ",
+			expectedError: ErrInvalidCity,
+		},
+		{
+			name:          "provider unavailable",
+			query:         "sao paulo",
+			searchError:   ErrProviderUnavailable,
+			expectedError: ErrProviderUnavailable,
+			expectedCalls: []string{"sao paulo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{searchCitiesResult: []City{testCity}, searchCitiesError: tt.searchError}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			result, err := service.SearchCities(tt.query)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expectedResult, result)
+			}
+			assert.Equal(t, tt.expectedCalls, mock.searchCitiesCalls)
+		})
+	}
+}
+
+func TestService_FindForecast(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          CityQuery
+		days           int
+		findCityError  error
+		forecastError  error
+		expectedResult CityForecast
+		expectedError  error
+		expectedCalls  []string
+	}{
+		{
+			name:           "success",
+			query:          CityQuery{Name: "test city"},
+			days:           2,
+			expectedResult: CityForecast{Forecast: testWeatherForecast.Forecast[:2]},
+			expectedCalls:  []string{"city-id"},
+		},
+		{
+			name:           "default days",
+			query:          CityQuery{Name: "test city"},
+			expectedResult: testWeatherForecast,
+			expectedCalls:  []string{"city-id"},
+		},
+		{
+			name:          "invalid days",
+			query:         CityQuery{Name: "test city"},
+			days:          MaxForecastDays + 1,
+			expectedError: ErrInvalidForecastDays,
+		},
+		{
+			name:          "missing city",
+			query:         CityQuery{},
+			expectedError: ErrInvalidCity,
+		},
+		{
+			name:          "city not found",
+			query:         CityQuery{Name: "test city"},
+			findCityError: ErrCityNotFound,
+			expectedError: ErrCityNotFound,
+		},
+		{
+			name:          "ambiguous city",
+			query:         CityQuery{Name: "test city"},
+			findCityError: ErrMultipleCities,
+			expectedError: ErrMultipleCities,
+		},
+		{
+			name:          "error fetching forecast",
+			query:         CityQuery{Name: "test city"},
+			forecastError: runtimeError,
+			expectedError: runtimeError,
+			expectedCalls: []string{"city-id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				findCityResult:    testCity,
+				findCityError:     tt.findCityError,
+				getForecastResult: testWeatherForecast,
+				getForecastError:  tt.forecastError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			city, result, err := service.FindForecast(tt.query, tt.days)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				assert.Equal(t, testCity, city)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+			assert.Equal(t, tt.expectedCalls, mock.getForecastCalls)
+		})
+	}
+}
+
+func TestService_FindWaveForecast(t *testing.T) {
+	tomorrow := CityWaveForecast{Date: "2025-02-08", Morning: WaveForecast{Swell: "Fraca", Height: 0.5}}
+
+	tests := []struct {
+		name           string
+		day            int
+		wavesResult    []CityWaveForecast
+		wavesError     error
+		expectedResult CityWaveForecast
+		expectedError  error
+		expectedDays   []int
+	}{
+		{
+			name:           "today",
+			day:            0,
+			wavesResult:    []CityWaveForecast{testWavesForecast},
+			expectedResult: testWavesForecast,
+			expectedDays:   []int{1},
+		},
+		{
+			name:           "tomorrow",
+			day:            1,
+			wavesResult:    []CityWaveForecast{testWavesForecast, tomorrow},
+			expectedResult: tomorrow,
+			expectedDays:   []int{2},
+		},
+		{
+			name:          "invalid day",
+			day:           MaxWaveDays,
+			expectedError: ErrInvalidForecastDays,
+		},
+		{
+			name:          "city away from the sea",
+			day:           0,
+			wavesError:    ErrCityNotFound,
+			expectedError: ErrWavesNotFound,
+			expectedDays:  []int{1},
+		},
+		{
+			name:          "day not forecast",
+			day:           1,
+			wavesResult:   []CityWaveForecast{testWavesForecast},
+			expectedError: ErrWavesNotFound,
+			expectedDays:  []int{2},
+		},
+		{
+			name:          "error fetching waves",
+			day:           0,
+			wavesError:    runtimeError,
+			expectedError: runtimeError,
+			expectedDays:  []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				findCityResult:             testCity,
+				getWaveForecastRangeResult: tt.wavesResult,
+				getWaveForecastRangeError:  tt.wavesError,
+			}
+
+			service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+			city, result, err := service.FindWaveForecast(CityQuery{Name: "test city"}, tt.day)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				assert.Equal(t, testCity, city)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+			assert.Equal(t, tt.expectedDays, mock.getWaveForecastRangeDays)
+		})
+	}
+}
+
+func TestService_Validate(t *testing.T) {
+	mock := &mockClient{findUserResult: testUser, findCityResult: testCity}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+	city, err := service.Validate("user-id", CityQuery{Name: "test city"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, testCity, city)
+
+	mock.findCityError = ErrMultipleCities
+
+	city, err = service.Validate("user-id", CityQuery{Name: "test city"})
+
+	assert.ErrorIs(t, err, ErrMultipleCities)
+	assert.Equal(t, City{}, city)
+}
+
+func TestService_NotifyUserByCity(t *testing.T) {
+	mock := &mockClient{
+		findUserResult:            testUser,
+		findCityResult:            testCity,
+		getForecastResult:         testWeatherForecast,
+		getWaveForecastRangeError: ErrCityNotFound,
+		getCityConditionsError:    ErrStationNotFound,
+	}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+	err := service.NotifyUserByCity("user-id", CityQuery{ID: "city-id"}, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"city-id"}, mock.findCityByIDCalls)
+	assert.Nil(t, mock.findCityCalls)
+	assert.Equal(t, []string{"city-id"}, mock.getForecastCalls)
+	assert.Len(t, mock.notifyCallsContent, 1)
+
+	err = service.NotifyUserByCity("user-id", CityQuery{Name: "test city"}, 0)
+
+	assert.ErrorIs(t, err, ErrInvalidCity)
+}
+
+func TestService_NotifyUserByCityID(t *testing.T) {
+	mock := &mockClient{
+		findUserResult:            testUser,
+		findCityResult:            testCity,
+		getForecastResult:         testWeatherForecast,
+		getWaveForecastRangeError: ErrCityNotFound,
+		getCityConditionsError:    ErrStationNotFound,
+	}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+	err := service.NotifyUserByCityID("user-id", "city-id", 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"city-id"}, mock.findCityByIDCalls)
+	assert.Nil(t, mock.findCityCalls)
+	assert.Equal(t, []string{"city-id"}, mock.getForecastCalls)
+	assert.Len(t, mock.notifyCallsContent, 1)
+
+	err = service.NotifyUserByCityID("user-id", "", 0)
+
+	assert.ErrorIs(t, err, ErrInvalidCity)
+}
+
+func TestService_NotifyUserByCity_Relocate(t *testing.T) {
+	mock := &mockClient{
+		findUserResult:            testUser,
+		findCityError:             ErrProviderUnavailable,
+		relocateResult:            City{ID: "openmeteo:-22.9,-43.2", Name: "Test City"},
+		getForecastResult:         testWeatherForecast,
+		getWaveForecastRangeError: ErrCityNotFound,
+		getCityConditionsError:    ErrStationNotFound,
+	}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+	err := service.NotifyUserByCity("user-id", CityQuery{ID: "cptec:241", Name: "Test City", State: "XY"}, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []CityQuery{{ID: "cptec:241", Name: "Test City", State: "XY"}}, mock.relocateCalls)
+	assert.Equal(t, []string{"openmeteo:-22.9,-43.2"}, mock.getForecastCalls)
+	assert.Len(t, mock.notifyCallsContent, 1)
+
+	// Without anywhere else to find it, the provider stays unavailable
+	mock = &mockClient{
+		findCityError: ErrProviderUnavailable,
+		relocateError: ErrCityNotFound,
+	}
+
+	service = NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+	err = service.NotifyUserByCity("user-id", CityQuery{ID: "cptec:241"}, 0)
+
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+	assert.Nil(t, mock.notifyCallsContent)
+}
+
+func TestService_GetForecast_Relocate(t *testing.T) {
+	mock := &mockClient{
+		getForecastError: ErrProviderUnavailable,
+		relocateResult:   City{ID: "openmeteo:-22.9,-43.2"},
+	}
+
+	service := NewService(mock, mock, mock, mock, mock, mock, mock, mock, testRenderer, mock, testLogger)
+
+	city := CityQuery{ID: "cptec:241", Name: "Test City", State: "XY"}
+
+	_, err := service.GetForecast(city, 0)
+
+	// Relocated, but the forecast is fetched again from the city found
+	assert.ErrorIs(t, err, ErrProviderUnavailable)
+	assert.Equal(t, []CityQuery{city}, mock.relocateCalls)
+	assert.Equal(t, []string{"cptec:241", "openmeteo:-22.9,-43.2"}, mock.getForecastCalls)
+
+	mock.getForecastError = ErrCityNotFound
+	mock.relocateCalls = nil
+	mock.getForecastCalls = nil
+
+	_, err = service.GetForecast(city, 0)
+
+	assert.ErrorIs(t, err, ErrCityNotFound)
+	assert.Nil(t, mock.relocateCalls)
+	assert.Equal(t, []string{"cptec:241"}, mock.getForecastCalls)
+}
@@ -1,23 +1,62 @@
 package weather
 
-import "github.com/fgouvea/weather/weather-service/user"
+import (
+	"github.com/fgouvea/weather/weather-service/message"
+	"github.com/fgouvea/weather/weather-service/user"
+)
+
+// Renderer writes the content of a notification from the templates of the
+// given name, in the locale of the user.
+type Renderer interface {
+	Render(locale, name string, data any) (message.Content, error)
+}
 
 type UserFinder interface {
 	FindUser(id string) (user.User, error)
 }
 
+// CityFinder finds a single city. Searching by name fails with
+// ErrMultipleCities when the name, even within the given state (which may be
+// empty), is ambiguous.
 type CityFinder interface {
-	FindCity(name string) (City, error)
+	FindCity(name, state string) (City, error)
+	FindCityByCoordinates(latitude, longitude float64) (City, error)
+	FindCityByID(id string) (City, error)
+}
+
+// CityRelocator finds a city again on another provider, by the name and state
+// or coordinates kept along with its ID, when the provider of the ID is
+// unavailable
+type CityRelocator interface {
+	Relocate(id string, query CityQuery) (City, error)
+}
+
+// CitySearcher lists the cities matching a name, for users to choose from.
+type CitySearcher interface {
+	SearchCities(name string) ([]City, error)
 }
 
 type WeatherForecaster interface {
 	GetForecast(id string) (CityForecast, error)
 }
 
+// ExtendedForecaster reaches further ahead than WeatherForecaster, usually
+// with less detail. The forecast may or may not include the first days.
+type ExtendedForecaster interface {
+	GetExtendedForecast(id string) (CityForecast, error)
+}
+
 type WaveForecaster interface {
 	GetWaveForecast(id string) (CityWaveForecast, error)
+	// GetWaveForecastRange returns the wave forecast for the given number of
+	// days, starting today
+	GetWaveForecastRange(id string, days int) ([]CityWaveForecast, error)
+}
+
+type CityConditionsFinder interface {
+	GetCityConditions(cityID string) (CurrentConditions, error)
 }
 
 type Notifier interface {
-	Notify(userID, content string) error
+	Notify(userID string, notification Notification) error
 }
@@ -20,8 +20,10 @@ func readConfigFromEnv() AppConfig {
 }
 
 type Notification struct {
-	ID      string `json:"id"`
-	Content string `json:"content"`
+	ID      string          `json:"id"`
+	Content string          `json:"content"`
+	HTML    string          `json:"html"`
+	Payload json.RawMessage `json:"payload"`
 }
 
 func main() {
@@ -44,6 +46,10 @@ func main() {
 			fmt.Printf("ID: %s\n", body.ID)
 			fmt.Println(body.Content)
 
+			if len(body.Payload) > 0 {
+				fmt.Printf("Payload: %s\n", body.Payload)
+			}
+
 			w.WriteHeader(http.StatusAccepted)
 		})
 	})